@@ -0,0 +1,132 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Code is a stable, machine-readable error code
+type Code string
+
+// Definition is a registered error of the catalogue: its code, http status and user-safe message
+type Definition struct {
+	Code       Code
+	HTTPStatus int
+	Message    string
+}
+
+// Error is an error instance of a definition, with details describing the failing request
+type Error struct {
+	Code       Code                   `json:"code"`
+	HTTPStatus int                    `json:"-"`
+	Message    string                 `json:"message"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	cause      error
+}
+
+var registry = make(map[Code]Definition)
+
+// register adds the definition to the catalogue, the code must be unique
+func register(code Code, httpStatus int, message string) Definition {
+	if _, exists := registry[code]; exists {
+		panic(fmt.Sprintf("apperror: code %s is already registered", code))
+	}
+
+	definition := Definition{
+		Code:       code,
+		HTTPStatus: httpStatus,
+		Message:    message,
+	}
+	registry[code] = definition
+
+	return definition
+}
+
+// Lookup returns the registered definition of the code
+func Lookup(code Code) (Definition, bool) {
+	definition, ok := registry[code]
+	return definition, ok
+}
+
+// Definitions returns every registered definition, sorted by code
+func Definitions() []Definition {
+	var definitions []Definition
+	for _, v := range registry {
+		definitions = append(definitions, v)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+
+	return definitions
+}
+
+// Error makes a definition usable as errors.Is target
+func (d Definition) Error() string {
+	return string(d.Code)
+}
+
+// New returns a new error of the definition
+func (d Definition) New() *Error {
+	return &Error{
+		Code:       d.Code,
+		HTTPStatus: d.HTTPStatus,
+		Message:    d.Message,
+	}
+}
+
+// Wrap returns a new error of the definition caused by err, the cause is never rendered to clients
+func (d Definition) Wrap(err error) *Error {
+	e := d.New()
+	e.cause = err
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Code, e.Message)
+	if len(e.Details) > 0 {
+		msg += fmt.Sprintf(" %v", e.Details)
+	}
+	if e.cause != nil {
+		msg += ": " + e.cause.Error()
+	}
+
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether the target is the definition or an error of the same code
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Definition:
+		return e.Code == t.Code
+	case *Error:
+		return e.Code == t.Code
+	}
+
+	return false
+}
+
+// WithDetail adds a detail of the failing request and returns the error
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+
+	return e
+}
+
+// From returns the first coded error in the chain of err, or an internal error wrapping err
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return Internal.Wrap(err)
+}
@@ -0,0 +1,47 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogue(t *testing.T) {
+	definitions := Definitions()
+	assert.NotEmpty(t, definitions)
+
+	for _, v := range definitions {
+		assert.NotEmpty(t, v.Code)
+		assert.NotEmpty(t, v.Message)
+		assert.NotEmpty(t, http.StatusText(v.HTTPStatus), "code %s has invalid http status", v.Code)
+		assert.GreaterOrEqual(t, v.HTTPStatus, 400, "code %s must be an error status", v.Code)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	assert.Panics(t, func() {
+		register(LoanNotFound.Code, http.StatusNotFound, "duplicate")
+	})
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("invest: %w", AgreementGenerationFailed.Wrap(cause).WithDetail("loan_id", 1))
+
+	assert.True(t, errors.Is(err, AgreementGenerationFailed))
+	assert.False(t, errors.Is(err, LoanNotFound))
+	assert.True(t, errors.Is(err, cause))
+
+	appErr := From(err)
+	assert.Equal(t, AgreementGenerationFailed.Code, appErr.Code)
+	assert.Equal(t, http.StatusInternalServerError, appErr.HTTPStatus)
+	assert.Equal(t, map[string]interface{}{"loan_id": 1}, appErr.Details)
+	assert.Contains(t, appErr.Error(), "connection refused")
+
+	internal := From(cause)
+	assert.Equal(t, Internal.Code, internal.Code)
+	assert.True(t, errors.Is(internal, cause))
+}
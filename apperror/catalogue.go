@@ -0,0 +1,42 @@
+package apperror
+
+import "net/http"
+
+// catalogue of every error returned to clients
+var (
+	InvalidRequest    = register("invalid_request", http.StatusBadRequest, "request is invalid")
+	InvalidPagination = register("invalid_pagination", http.StatusBadRequest, "pagination is invalid")
+	RequestTooLarge   = register("request_too_large", http.StatusRequestEntityTooLarge, "request body is too large")
+	RequestTimeout    = register("request_timeout", http.StatusServiceUnavailable, "request took too long to serve")
+	RateLimited       = register("rate_limited", http.StatusTooManyRequests, "too many requests, retry later")
+	NotReady          = register("not_ready", http.StatusServiceUnavailable, "server is not ready to serve requests")
+
+	InvalidIdempotencyKey        = register("invalid_idempotency_key", http.StatusBadRequest, "idempotency key is invalid")
+	IdempotencyKeyReused         = register("idempotency_key_reused", http.StatusUnprocessableEntity, "idempotency key was already used with a different request")
+	IdempotencyRequestInProgress = register("idempotency_request_in_progress", http.StatusConflict, "request with this idempotency key is still in progress")
+
+	UserNotFound      = register("user_not_found", http.StatusNotFound, "user is not found")
+	LoanNotFound      = register("loan_not_found", http.StatusNotFound, "loan is not found")
+	AgreementNotFound = register("agreement_not_found", http.StatusNotFound, "agreement is not found")
+	DocumentNotFound  = register("document_not_found", http.StatusNotFound, "document is not found")
+	ProofNotFound     = register("proof_not_found", http.StatusNotFound, "picture proof of the loan is not found")
+
+	WebhookSubscriptionNotFound = register("webhook_subscription_not_found", http.StatusNotFound, "webhook subscription is not found")
+	WebhookDeliveryNotFound     = register("webhook_delivery_not_found", http.StatusNotFound, "webhook delivery is not found")
+
+	UserTypeNotAllowed = register("user_type_not_allowed", http.StatusForbidden, "user type is not allowed to do this action")
+	WrongSigner        = register("wrong_signer", http.StatusForbidden, "user is not the signer of this agreement")
+	Unauthenticated    = register("unauthenticated", http.StatusUnauthorized, "user is not identified")
+	AccessDenied       = register("access_denied", http.StatusForbidden, "user is not allowed to access this resource")
+
+	InvalidLoanStatus      = register("invalid_loan_status", http.StatusBadRequest, "loan status does not allow this action")
+	InvestedAmountExceeded = register("invested_amount_exceeded", http.StatusBadRequest, "invested amount is bigger than remaining required amount")
+	AgreementLoanMismatch  = register("agreement_loan_mismatch", http.StatusBadRequest, "agreement does not belong to this loan")
+	AgreementAlreadySigned = register("agreement_already_signed", http.StatusBadRequest, "agreement is already signed")
+	AgreementNotSignable   = register("agreement_not_signable", http.StatusBadRequest, "agreement type can not be signed")
+	InvalidPictureProof    = register("invalid_picture_proof", http.StatusBadRequest, "picture proof is not a valid picture")
+	VisitOutOfRange        = register("visit_out_of_range", http.StatusBadRequest, "visit is too far from the borrower address")
+
+	AgreementGenerationFailed = register("agreement_generation_failed", http.StatusInternalServerError, "failed to generate agreement")
+	Internal                  = register("internal_error", http.StatusInternalServerError, "internal error")
+)
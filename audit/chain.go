@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"amartha-test/model"
+)
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// GenesisHash is the previous hash of the first entry
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// NewEntry returns the unchained entry of the write of the target by the actor of ctx, before is nil when the
+// target is created and after is nil when it is deleted. The entry has no changes when the write changes nothing
+func NewEntry(ctx context.Context, targetType string, targetID string, before interface{}, after interface{}) (model.AuditEntry, error) {
+	beforeData, err := marshal(before)
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+	afterData, err := marshal(after)
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+	changes, err := Diff(beforeData, afterData)
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+
+	action := ActionUpdated
+	switch {
+	case beforeData == nil:
+		action = ActionCreated
+	case afterData == nil:
+		action = ActionDeleted
+	}
+
+	return model.AuditEntry{
+		Actor:      ActorFrom(ctx),
+		Action:     targetType + "." + action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeData,
+		After:      afterData,
+		Changes:    changes,
+		RequestID:  RequestIDFrom(ctx),
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Chain links the entry after the entry of the previous hash
+func Chain(entry model.AuditEntry, sequence int64, prevHash string) model.AuditEntry {
+	entry.Sequence = sequence
+	entry.PrevHash = prevHash
+	entry.Hash = Hash(entry)
+	return entry
+}
+
+// Hash returns the sha256 of the entry without its own hash
+func Hash(entry model.AuditEntry) string {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify recomputes the hash chain of the entries ordered by sequence, reporting the first entry not matching
+func Verify(entries []model.AuditEntry) model.AuditVerification {
+	prevHash := GenesisHash
+	for i, entry := range entries {
+		if entry.Sequence != int64(i+1) || entry.PrevHash != prevHash || Hash(entry) != entry.Hash {
+			return model.AuditVerification{
+				EntryCount:     len(entries),
+				BrokenSequence: int64(i + 1),
+				LastHash:       prevHash,
+			}
+		}
+		prevHash = entry.Hash
+	}
+
+	return model.AuditVerification{
+		IsValid:    true,
+		EntryCount: len(entries),
+		LastHash:   prevHash,
+	}
+}
+
+// Diff returns the fields changed between the json objects, ordered by field
+func Diff(before json.RawMessage, after json.RawMessage) ([]model.AuditChange, error) {
+	beforeFields := make(map[string]json.RawMessage)
+	err := flatten(beforeFields, "", before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields := make(map[string]json.RawMessage)
+	err = flatten(afterFields, "", after)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, exists := beforeFields[field]; !exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []model.AuditChange
+	for _, field := range fields {
+		if bytes.Equal(beforeFields[field], afterFields[field]) {
+			continue
+		}
+		changes = append(changes, model.AuditChange{
+			Field:  field,
+			Before: beforeFields[field],
+			After:  afterFields[field],
+		})
+	}
+
+	return changes, nil
+}
+
+// flatten collects the values of the json object by field, nested objects are joined by a dot and other values are kept whole
+func flatten(fields map[string]json.RawMessage, prefix string, data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != '{' {
+		fields[prefix] = data
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return err
+	}
+	for k, v := range object {
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		err = flatten(fields, field, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// marshal returns the json of the value, nil for a nil value
+func marshal(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	return data, nil
+}
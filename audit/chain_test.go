@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/model"
+)
+
+func TestNewEntry(t *testing.T) {
+	ctx := WithRequestID(WithActor(context.Background(), UserActor(4)), "req-1")
+	before := model.Loan{LoanID: 1, Status: 1, StatusDesc: "proposed"}
+	after := model.Loan{LoanID: 1, Status: 2, StatusDesc: "approved", ApprovalInfo: &model.ApprovalInfo{FieldValidatorEmployeeID: 4}}
+
+	entry, err := NewEntry(ctx, "loan", "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", &before, after)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user:4", entry.Actor)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, "loan.updated", entry.Action)
+	assert.Equal(t, "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", entry.TargetID)
+	assert.False(t, entry.CreatedAt.IsZero())
+	assert.Contains(t, changedFields(entry.Changes), "status")
+	assert.Contains(t, changedFields(entry.Changes), "approval_info.field_validator_employee_id")
+	assert.NotContains(t, changedFields(entry.Changes), "loan_id")
+
+	var nilLoan *model.Loan
+	entry, err = NewEntry(context.Background(), "loan", "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", nilLoan, after)
+	assert.NoError(t, err)
+	assert.Equal(t, "loan.created", entry.Action)
+	assert.Equal(t, SystemActor, entry.Actor)
+	assert.Nil(t, entry.Before)
+
+	entry, err = NewEntry(context.Background(), "loan", "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", after, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "loan.deleted", entry.Action)
+	assert.Nil(t, entry.After)
+
+	entry, err = NewEntry(context.Background(), "loan", "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", after, after)
+	assert.NoError(t, err)
+	assert.Empty(t, entry.Changes)
+}
+
+func TestDiff(t *testing.T) {
+	changes, err := Diff(
+		json.RawMessage(`{"status":1,"approval_info":{"picture_proof":"a","employee_id":4},"lending":[]}`),
+		json.RawMessage(`{"status":2,"approval_info":{"picture_proof":"b","employee_id":4},"lending":[{"lender_id":2}]}`),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AuditChange{
+		{Field: "approval_info.picture_proof", Before: json.RawMessage(`"a"`), After: json.RawMessage(`"b"`)},
+		{Field: "lending", Before: json.RawMessage(`[]`), After: json.RawMessage(`[{"lender_id":2}]`)},
+		{Field: "status", Before: json.RawMessage(`1`), After: json.RawMessage(`2`)},
+	}, changes)
+}
+
+func TestVerify(t *testing.T) {
+	var entries []model.AuditEntry
+	prevHash := GenesisHash
+	for i := int64(1); i <= 3; i++ {
+		entry, err := NewEntry(context.Background(), "loan", strconv.FormatInt(i, 10), nil, model.Loan{LoanID: i})
+		assert.NoError(t, err)
+		entry = Chain(entry, i, prevHash)
+		prevHash = entry.Hash
+		entries = append(entries, entry)
+	}
+
+	verification := Verify(entries)
+	assert.True(t, verification.IsValid)
+	assert.Equal(t, 3, verification.EntryCount)
+	assert.Equal(t, entries[2].Hash, verification.LastHash)
+
+	t.Run("tampered entry", func(t *testing.T) {
+		tampered := append([]model.AuditEntry(nil), entries...)
+		tampered[1].Actor = "user:1"
+
+		verification := Verify(tampered)
+		assert.False(t, verification.IsValid)
+		assert.Equal(t, int64(2), verification.BrokenSequence)
+	})
+
+	t.Run("removed entry", func(t *testing.T) {
+		verification := Verify([]model.AuditEntry{entries[0], entries[2]})
+		assert.False(t, verification.IsValid)
+		assert.Equal(t, int64(2), verification.BrokenSequence)
+	})
+
+	t.Run("empty log", func(t *testing.T) {
+		verification := Verify(nil)
+		assert.True(t, verification.IsValid)
+		assert.Equal(t, GenesisHash, verification.LastHash)
+	})
+}
+
+func changedFields(changes []model.AuditChange) []string {
+	var fields []string
+	for _, v := range changes {
+		fields = append(fields, v.Field)
+	}
+
+	return fields
+}
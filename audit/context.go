@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+)
+
+// SystemActor is the actor of the writes made outside of a request, by the background workers
+const SystemActor = "system"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// UserActor returns the actor of the user
+func UserActor(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// WithActor returns the context of the actor making the writes
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor of the context, the system when there is none
+func ActorFrom(ctx context.Context) string {
+	actor, ok := ctx.Value(actorKey).(string)
+	if !ok || actor == "" {
+		return SystemActor
+	}
+
+	return actor
+}
+
+// WithRequestID returns the context of the request making the writes
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom returns the request id of the context, empty when there is none
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenVersion prefixes every token, so the format can change without accepting the old tokens by mistake
+const tokenVersion = "v1"
+
+// secretSize is the size of the generated signing secret
+const secretSize = 32
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token is expired")
+)
+
+// Authenticator issues and verifies the bearer tokens identifying the users, a token is
+// "v1.<user id>.<expiry unix>.<signature>" signed with HMAC-SHA256
+type Authenticator struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewAuthenticator(secret []byte, ttl time.Duration) *Authenticator {
+	return &Authenticator{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// Issue returns the token of the user, valid until the returned expiry
+func (a *Authenticator) Issue(userID int64, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(a.ttl).Truncate(time.Second)
+	payload := fmt.Sprintf("%s.%d.%d", tokenVersion, userID, expiresAt.Unix())
+
+	return payload + "." + a.sign(payload), expiresAt
+}
+
+// Verify returns the user of the token, the signature is checked before anything of the token is trusted
+func (a *Authenticator) Verify(token string, now time.Time) (int64, error) {
+	// 1. check signature
+	separator := strings.LastIndexByte(token, '.')
+	if separator < 0 {
+		return 0, ErrInvalidToken
+	}
+	payload, signature := token[:separator], token[separator+1:]
+	if !hmac.Equal([]byte(signature), []byte(a.sign(payload))) {
+		return 0, ErrInvalidToken
+	}
+
+	// 2. parse payload
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 || parts[0] != tokenVersion {
+		return 0, ErrInvalidToken
+	}
+	userID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	// 3. check expiry
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return 0, ErrExpiredToken
+	}
+
+	return userID, nil
+}
+
+func (a *Authenticator) sign(payload string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// LoadOrCreateSecret returns the signing secret kept in the file of path, generating it on the first run
+// so the tokens issued stay valid across restarts
+func LoadOrCreateSecret(path string) ([]byte, error) {
+	secret, err := os.ReadFile(path)
+	if err == nil {
+		if len(secret) < secretSize {
+			return nil, fmt.Errorf("auth secret %s is shorter than %d bytes", path, secretSize)
+		}
+		return secret, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read auth secret: %w", err)
+	}
+
+	secret = make([]byte, secretSize)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, fmt.Errorf("generate auth secret: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("create auth secret dir: %w", err)
+	}
+	err = os.WriteFile(path, secret, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("write auth secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+type contextKey int
+
+const userIDKey contextKey = iota
+
+// WithUserID returns the context of the authenticated user
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFrom returns the authenticated user of the context, false when the request is anonymous
+func UserIDFrom(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey).(int64)
+	return userID, ok
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator(t *testing.T) {
+	authenticator := NewAuthenticator([]byte(strings.Repeat("s", secretSize)), time.Hour)
+	now := time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+	token, expiresAt := authenticator.Issue(4, now)
+	assert.Equal(t, now.Add(time.Hour), expiresAt)
+
+	tests := []struct {
+		name           string
+		token          string
+		now            time.Time
+		expectedUserID int64
+		expectedErr    error
+	}{
+		{
+			name:           "success",
+			token:          token,
+			now:            now,
+			expectedUserID: 4,
+		},
+		{
+			name:        "expired",
+			token:       token,
+			now:         expiresAt,
+			expectedErr: ErrExpiredToken,
+		},
+		{
+			name:        "user changed",
+			token:       strings.Replace(token, "v1.4.", "v1.1.", 1),
+			now:         now,
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name:        "signed with other secret",
+			token:       func() string { t, _ := NewAuthenticator([]byte("other"), time.Hour).Issue(4, now); return t }(),
+			now:         now,
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name:        "malformed",
+			token:       "4",
+			now:         now,
+			expectedErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// main func
+			userID, err := authenticator.Verify(tt.token, tt.now)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedUserID, userID)
+		})
+	}
+}
+
+func TestLoadOrCreateSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "auth.key")
+
+	// first run generates the secret
+	secret, err := LoadOrCreateSecret(path)
+	assert.NoError(t, err)
+	assert.Len(t, secret, secretSize)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// next runs keep it
+	loaded, err := LoadOrCreateSecret(path)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, loaded)
+
+	// short secret is refused
+	os.WriteFile(path, []byte("short"), 0o600)
+	_, err = LoadOrCreateSecret(path)
+	assert.Error(t, err)
+}
+
+func TestUserIDFrom(t *testing.T) {
+	_, ok := UserIDFrom(context.Background())
+	assert.False(t, ok)
+
+	userID, ok := UserIDFrom(WithUserID(context.Background(), 4))
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), userID)
+}
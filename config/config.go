@@ -0,0 +1,409 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/ratelimit"
+	"amartha-test/tracing"
+)
+
+const (
+	EnvConfigFile     = "AMARTHA_CONFIG_FILE"
+	EnvListenAddr     = "AMARTHA_LISTEN_ADDR"
+	EnvGRPCListenAddr = "AMARTHA_GRPC_LISTEN_ADDR"
+	EnvPublicBaseURL  = "AMARTHA_PUBLIC_BASE_URL"
+	EnvDocumentDir    = "AMARTHA_DOCUMENT_DIR"
+	EnvSMTPAddr       = "AMARTHA_SMTP_ADDR"
+	EnvSMTPFrom       = "AMARTHA_SMTP_FROM"
+	EnvSMTPUsername   = "AMARTHA_SMTP_USERNAME"
+	EnvSMTPPassword   = "AMARTHA_SMTP_PASSWORD"
+	EnvSMSGatewayURL  = "AMARTHA_SMS_GATEWAY_URL"
+	EnvMaxBodyBytes   = "AMARTHA_MAX_BODY_BYTES"
+	EnvRequestTimeout = "AMARTHA_REQUEST_TIMEOUT"
+	EnvInjectLatency  = "AMARTHA_INJECT_LATENCY"
+	EnvTraceExporter  = "AMARTHA_TRACE_EXPORTER"
+	EnvTraceEndpoint  = "AMARTHA_TRACE_ENDPOINT"
+	EnvLogLevel       = "AMARTHA_LOG_LEVEL"
+	EnvLogFormat      = "AMARTHA_LOG_FORMAT"
+
+	EnvReadHeaderTimeout = "AMARTHA_READ_HEADER_TIMEOUT"
+	EnvReadTimeout       = "AMARTHA_READ_TIMEOUT"
+	EnvWriteTimeout      = "AMARTHA_WRITE_TIMEOUT"
+	EnvIdleTimeout       = "AMARTHA_IDLE_TIMEOUT"
+	EnvDrainDelay        = "AMARTHA_DRAIN_DELAY"
+	EnvShutdownTimeout   = "AMARTHA_SHUTDOWN_TIMEOUT"
+
+	EnvVisitRequired          = "AMARTHA_VISIT_REQUIRED"
+	EnvVisitMaxDistanceMeters = "AMARTHA_VISIT_MAX_DISTANCE_METERS"
+	EnvVisitOutOfRange        = "AMARTHA_VISIT_OUT_OF_RANGE"
+	EnvVisitMaxAge            = "AMARTHA_VISIT_MAX_AGE"
+
+	EnvAuthSecretFile = "AMARTHA_AUTH_SECRET_FILE"
+	EnvAuthTokenTTL   = "AMARTHA_AUTH_TOKEN_TTL"
+	EnvTrustedProxies = "AMARTHA_TRUSTED_PROXIES"
+
+	EnvWebhookAllowPrivate = "AMARTHA_WEBHOOK_ALLOW_PRIVATE"
+)
+
+// Config is the server configuration, loaded once in main.go
+type Config struct {
+	// ListenAddr is the address the http server listens on, e.g. ":8080"
+	ListenAddr string `json:"listen_addr"`
+	// GRPCListenAddr is the address the grpc server listens on, e.g. ":9090"
+	GRPCListenAddr string `json:"grpc_listen_addr"`
+	// PublicBaseURL is the externally reachable base url used to build links, e.g. "https://loan.example.com"
+	PublicBaseURL string `json:"public_base_url"`
+	// DocumentDir is the directory of the local document store
+	DocumentDir string `json:"document_dir"`
+	// SMTPAddr is the "host:port" of the smtp server sending email notifications, empty disables email
+	SMTPAddr string `json:"smtp_addr"`
+	// SMTPFrom is the sender address of email notifications
+	SMTPFrom     string `json:"smtp_from"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"smtp_password"`
+	// SMSGatewayURL is the url the sms notifications are posted to, empty disables sms
+	SMSGatewayURL string `json:"sms_gateway_url"`
+	// MaxBodyBytes is the biggest request body accepted
+	MaxBodyBytes int64 `json:"max_body_bytes"`
+	// RequestTimeout is how long a request may take before it is answered with a timeout, streams are left out
+	RequestTimeout Duration `json:"request_timeout"`
+	// InjectLatency delays every request, for tests and chaos experiments only, zero disables it
+	InjectLatency Duration `json:"inject_latency"`
+	// TraceExporter is where the spans are exported, "stdout" for local runs or "otlp", empty disables tracing
+	TraceExporter string `json:"trace_exporter"`
+	// TraceEndpoint is the "host:port" of the otlp http collector, empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	TraceEndpoint string `json:"trace_endpoint"`
+	// LogLevel is the lowest level logged, "debug", "info", "warn" or "error"
+	LogLevel string `json:"log_level"`
+	// LogFormat is "json" for log collectors or "text" for reading in a terminal
+	LogFormat string `json:"log_format"`
+	// ReadHeaderTimeout is how long the http server waits for the request headers
+	ReadHeaderTimeout Duration `json:"read_header_timeout"`
+	// ReadTimeout is how long the http server waits for the whole request, body included
+	ReadTimeout Duration `json:"read_timeout"`
+	// WriteTimeout is how long the http server may take to write a response, it must leave room for RequestTimeout.
+	// Streams push their deadline forward on every event so they are not cut by it
+	WriteTimeout Duration `json:"write_timeout"`
+	// IdleTimeout is how long a keep-alive connection is kept waiting for the next request
+	IdleTimeout Duration `json:"idle_timeout"`
+	// DrainDelay is how long /readyz fails before the server stops accepting requests on shutdown,
+	// giving the load balancer time to stop routing to it, zero skips the wait
+	DrainDelay Duration `json:"drain_delay"`
+	// ShutdownTimeout is how long the in-flight requests and background workers get to finish on shutdown
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	// RateLimits are the token bucket policies per "METHOD /route/template" and "default", the config file
+	// policies are merged over ratelimit.DefaultPolicies
+	RateLimits ratelimit.Policies `json:"rate_limits"`
+	// VisitRequired rejects the approvals and disbursements without the gps position of the field visit
+	VisitRequired bool `json:"visit_required"`
+	// VisitMaxDistanceMeters is how far from the borrower address a field visit may be located
+	VisitMaxDistanceMeters float64 `json:"visit_max_distance_meters"`
+	// VisitOutOfRange is what happens to a visit farther than VisitMaxDistanceMeters, "flag" accepts it
+	// flagged for review, "reject" refuses the approval or disbursement
+	VisitOutOfRange string `json:"visit_out_of_range"`
+	// VisitMaxAge is how long before the approval or disbursement request a field visit may be timestamped
+	VisitMaxAge Duration `json:"visit_max_age"`
+	// AuthSecretFile is the file of the secret signing the bearer tokens, generated on the first run when missing
+	AuthSecretFile string `json:"auth_secret_file"`
+	// AuthTokenTTL is how long an issued bearer token is valid
+	AuthTokenTTL Duration `json:"auth_token_ttl"`
+	// TrustedProxies are the CIDRs of the reverse proxies in front of the server, the client address of their
+	// requests is read from X-Forwarded-For or Forwarded. Empty trusts no proxy and uses the connection address
+	TrustedProxies []string `json:"trusted_proxies"`
+	// WebhookAllowPrivate lets the webhooks reach loopback, private and special addresses, for local runs only
+	WebhookAllowPrivate bool `json:"webhook_allow_private"`
+}
+
+// Duration is a time.Duration written as a duration string in the config file, e.g. "30s"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v string
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Default returns the configuration used when nothing is configured
+func Default() Config {
+	return Config{
+		ListenAddr:        ":8080",
+		GRPCListenAddr:    ":9090",
+		PublicBaseURL:     "http://localhost:8080",
+		DocumentDir:       "data/documents",
+		MaxBodyBytes:      10 << 20,
+		RequestTimeout:    Duration(30 * time.Second),
+		LogLevel:          "info",
+		LogFormat:         logging.FormatJSON,
+		ReadHeaderTimeout: Duration(5 * time.Second),
+		ReadTimeout:       Duration(30 * time.Second),
+		WriteTimeout:      Duration(60 * time.Second),
+		IdleTimeout:       Duration(120 * time.Second),
+		ShutdownTimeout:   Duration(10 * time.Second),
+		RateLimits:        ratelimit.DefaultPolicies(),
+
+		VisitMaxDistanceMeters: 500,
+		VisitOutOfRange:        constant.VisitOutOfRangeFlag,
+		VisitMaxAge:            Duration(24 * time.Hour),
+
+		AuthSecretFile: "data/auth.key",
+		AuthTokenTTL:   Duration(24 * time.Hour),
+	}
+}
+
+// Load returns the default configuration, overridden by the json config file in
+// AMARTHA_CONFIG_FILE (if set) and then by the individual environment variables
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("read config file: %w", err)
+		}
+
+		err = json.Unmarshal(content, &cfg)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse config file: %w", err)
+		}
+	}
+
+	if v := os.Getenv(EnvListenAddr); v != "" {
+		cfg.ListenAddr = v
+	}
+	if v := os.Getenv(EnvGRPCListenAddr); v != "" {
+		cfg.GRPCListenAddr = v
+	}
+	if v := os.Getenv(EnvPublicBaseURL); v != "" {
+		cfg.PublicBaseURL = v
+	}
+	if v := os.Getenv(EnvDocumentDir); v != "" {
+		cfg.DocumentDir = v
+	}
+	if v := os.Getenv(EnvSMTPAddr); v != "" {
+		cfg.SMTPAddr = v
+	}
+	if v := os.Getenv(EnvSMTPFrom); v != "" {
+		cfg.SMTPFrom = v
+	}
+	if v := os.Getenv(EnvSMTPUsername); v != "" {
+		cfg.SMTPUsername = v
+	}
+	if v := os.Getenv(EnvSMTPPassword); v != "" {
+		cfg.SMTPPassword = v
+	}
+	if v := os.Getenv(EnvSMSGatewayURL); v != "" {
+		cfg.SMSGatewayURL = v
+	}
+	if v := os.Getenv(EnvMaxBodyBytes); v != "" {
+		maxBodyBytes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", EnvMaxBodyBytes, err)
+		}
+		cfg.MaxBodyBytes = maxBodyBytes
+	}
+	if v := os.Getenv(EnvVisitRequired); v != "" {
+		visitRequired, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", EnvVisitRequired, err)
+		}
+		cfg.VisitRequired = visitRequired
+	}
+	if v := os.Getenv(EnvVisitMaxDistanceMeters); v != "" {
+		visitMaxDistanceMeters, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", EnvVisitMaxDistanceMeters, err)
+		}
+		cfg.VisitMaxDistanceMeters = visitMaxDistanceMeters
+	}
+	if v := os.Getenv(EnvVisitOutOfRange); v != "" {
+		cfg.VisitOutOfRange = v
+	}
+	if v := os.Getenv(EnvAuthSecretFile); v != "" {
+		cfg.AuthSecretFile = v
+	}
+	if v := os.Getenv(EnvTrustedProxies); v != "" {
+		cfg.TrustedProxies = nil
+		for _, proxy := range strings.Split(v, ",") {
+			cfg.TrustedProxies = append(cfg.TrustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+	if v := os.Getenv(EnvWebhookAllowPrivate); v != "" {
+		webhookAllowPrivate, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", EnvWebhookAllowPrivate, err)
+		}
+		cfg.WebhookAllowPrivate = webhookAllowPrivate
+	}
+	if v := os.Getenv(EnvTraceExporter); v != "" {
+		cfg.TraceExporter = v
+	}
+	if v := os.Getenv(EnvTraceEndpoint); v != "" {
+		cfg.TraceEndpoint = v
+	}
+	if v := os.Getenv(EnvLogLevel); v != "" {
+		cfg.LogLevel = v
+	}
+	if v := os.Getenv(EnvLogFormat); v != "" {
+		cfg.LogFormat = v
+	}
+
+	durations := map[string]*Duration{
+		EnvRequestTimeout:    &cfg.RequestTimeout,
+		EnvInjectLatency:     &cfg.InjectLatency,
+		EnvReadHeaderTimeout: &cfg.ReadHeaderTimeout,
+		EnvReadTimeout:       &cfg.ReadTimeout,
+		EnvWriteTimeout:      &cfg.WriteTimeout,
+		EnvIdleTimeout:       &cfg.IdleTimeout,
+		EnvDrainDelay:        &cfg.DrainDelay,
+		EnvShutdownTimeout:   &cfg.ShutdownTimeout,
+		EnvVisitMaxAge:       &cfg.VisitMaxAge,
+		EnvAuthTokenTTL:      &cfg.AuthTokenTTL,
+	}
+	for env, duration := range durations {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", env, err)
+		}
+		*duration = Duration(parsed)
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the configuration is usable
+func (c Config) Validate() error {
+	if c.ListenAddr == "" {
+		return fmt.Errorf("listen address is empty")
+	}
+	if c.GRPCListenAddr == "" {
+		return fmt.Errorf("grpc listen address is empty")
+	}
+	if c.GRPCListenAddr == c.ListenAddr {
+		return fmt.Errorf("grpc listen address %q must differ from listen address", c.GRPCListenAddr)
+	}
+	if !strings.HasPrefix(c.PublicBaseURL, "http://") && !strings.HasPrefix(c.PublicBaseURL, "https://") {
+		return fmt.Errorf("public base url %q must start with http:// or https://", c.PublicBaseURL)
+	}
+	if c.DocumentDir == "" {
+		return fmt.Errorf("document dir is empty")
+	}
+	if c.AuthSecretFile == "" {
+		return fmt.Errorf("auth secret file is empty")
+	}
+	_, err := c.TrustedProxyPrefixes()
+	if err != nil {
+		return err
+	}
+	if c.SMTPAddr != "" && c.SMTPFrom == "" {
+		return fmt.Errorf("smtp from is empty while smtp address is set")
+	}
+	if c.SMSGatewayURL != "" && !strings.HasPrefix(c.SMSGatewayURL, "http://") && !strings.HasPrefix(c.SMSGatewayURL, "https://") {
+		return fmt.Errorf("sms gateway url %q must start with http:// or https://", c.SMSGatewayURL)
+	}
+	if c.MaxBodyBytes <= 0 {
+		return fmt.Errorf("max body bytes %d must be positive", c.MaxBodyBytes)
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("request timeout %s must be positive", time.Duration(c.RequestTimeout))
+	}
+	if c.InjectLatency < 0 {
+		return fmt.Errorf("inject latency %s must not be negative", time.Duration(c.InjectLatency))
+	}
+	if c.TraceExporter != "" && c.TraceExporter != tracing.ExporterStdout && c.TraceExporter != tracing.ExporterOTLP {
+		return fmt.Errorf("trace exporter %q must be %s or %s", c.TraceExporter, tracing.ExporterStdout, tracing.ExporterOTLP)
+	}
+	positives := map[string]Duration{
+		"read header timeout": c.ReadHeaderTimeout,
+		"read timeout":        c.ReadTimeout,
+		"idle timeout":        c.IdleTimeout,
+		"shutdown timeout":    c.ShutdownTimeout,
+		"visit max age":       c.VisitMaxAge,
+		"auth token ttl":      c.AuthTokenTTL,
+	}
+	for name, duration := range positives {
+		if duration <= 0 {
+			return fmt.Errorf("%s %s must be positive", name, time.Duration(duration))
+		}
+	}
+	if c.WriteTimeout <= c.RequestTimeout {
+		return fmt.Errorf("write timeout %s must be longer than request timeout %s", time.Duration(c.WriteTimeout), time.Duration(c.RequestTimeout))
+	}
+	if c.DrainDelay < 0 {
+		return fmt.Errorf("drain delay %s must not be negative", time.Duration(c.DrainDelay))
+	}
+	_, err = logging.ParseLevel(c.LogLevel)
+	if err != nil {
+		return err
+	}
+	err = c.RateLimits.Validate()
+	if err != nil {
+		return err
+	}
+	if c.LogFormat != logging.FormatJSON && c.LogFormat != logging.FormatText {
+		return fmt.Errorf("log format %q must be %s or %s", c.LogFormat, logging.FormatJSON, logging.FormatText)
+	}
+	if c.VisitMaxDistanceMeters <= 0 {
+		return fmt.Errorf("visit max distance meters %g must be positive", c.VisitMaxDistanceMeters)
+	}
+	if c.VisitOutOfRange != constant.VisitOutOfRangeFlag && c.VisitOutOfRange != constant.VisitOutOfRangeReject {
+		return fmt.Errorf("visit out of range %q must be %s or %s", c.VisitOutOfRange, constant.VisitOutOfRangeFlag, constant.VisitOutOfRangeReject)
+	}
+
+	return nil
+}
+
+// TrustedProxyPrefixes returns the parsed TrustedProxies, a single address is trusted as a CIDR of its own
+func (c Config) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, v := range c.TrustedProxies {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q must be an address or a CIDR", v)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// AgreementURL returns the public url to view the agreement of the public id
+func (c Config) AgreementURL(publicID string) string {
+	return strings.TrimRight(c.PublicBaseURL, "/") + fmt.Sprintf(constant.AgreementPathFormat, publicID)
+}
@@ -0,0 +1,427 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/ratelimit"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            map[string]string
+		fileContent    string
+		expectedConfig Config
+		isError        bool
+	}{
+		{
+			name:           "success - default",
+			expectedConfig: Default(),
+		},
+		{
+			name: "success - env override",
+			env: map[string]string{
+				EnvListenAddr:     ":8000",
+				EnvGRPCListenAddr: ":9000",
+				EnvPublicBaseURL:  "https://loan.example.com/",
+			},
+			expectedConfig: Config{
+				ListenAddr:        ":8000",
+				GRPCListenAddr:    ":9000",
+				PublicBaseURL:     "https://loan.example.com/",
+				DocumentDir:       Default().DocumentDir,
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:        "success - config file overridden by env",
+			env:         map[string]string{EnvListenAddr: ":7070"},
+			fileContent: `{"listen_addr": ":6060", "public_base_url": "https://proxy.example.com", "document_dir": "/var/lib/amartha"}`,
+			expectedConfig: Config{
+				ListenAddr:        ":7070",
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     "https://proxy.example.com",
+				DocumentDir:       "/var/lib/amartha",
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:        "error - invalid config file",
+			fileContent: `{`,
+			isError:     true,
+		},
+		{
+			name:    "error - grpc listen address same as listen address",
+			env:     map[string]string{EnvGRPCListenAddr: Default().ListenAddr},
+			isError: true,
+		},
+		{
+			name: "success - notification channels",
+			env: map[string]string{
+				EnvSMTPAddr:      "localhost:1025",
+				EnvSMTPFrom:      "noreply@loan.example.com",
+				EnvSMSGatewayURL: "http://localhost:9100/sms",
+			},
+			expectedConfig: Config{
+				ListenAddr:        Default().ListenAddr,
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     Default().PublicBaseURL,
+				DocumentDir:       Default().DocumentDir,
+				SMTPAddr:          "localhost:1025",
+				SMTPFrom:          "noreply@loan.example.com",
+				SMSGatewayURL:     "http://localhost:9100/sms",
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:    "error - smtp address without sender",
+			env:     map[string]string{EnvSMTPAddr: "localhost:1025"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid sms gateway url",
+			env:     map[string]string{EnvSMSGatewayURL: "localhost:9100/sms"},
+			isError: true,
+		},
+		{
+			name:        "success - request limits",
+			env:         map[string]string{EnvMaxBodyBytes: "1024", EnvInjectLatency: "50ms"},
+			fileContent: `{"request_timeout": "5s", "inject_latency": "1s"}`,
+			expectedConfig: Config{
+				ListenAddr:        Default().ListenAddr,
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     Default().PublicBaseURL,
+				DocumentDir:       Default().DocumentDir,
+				MaxBodyBytes:      1024,
+				RequestTimeout:    Duration(5 * time.Second),
+				InjectLatency:     Duration(50 * time.Millisecond),
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:        "error - request timeout not a duration string",
+			fileContent: `{"request_timeout": 5}`,
+			isError:     true,
+		},
+		{
+			name:    "error - invalid max body bytes",
+			env:     map[string]string{EnvMaxBodyBytes: "0"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid request timeout",
+			env:     map[string]string{EnvRequestTimeout: "soon"},
+			isError: true,
+		},
+		{
+			name: "success - tracing",
+			env:  map[string]string{EnvTraceExporter: "otlp", EnvTraceEndpoint: "localhost:4318"},
+			expectedConfig: Config{
+				ListenAddr:        Default().ListenAddr,
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     Default().PublicBaseURL,
+				DocumentDir:       Default().DocumentDir,
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				TraceExporter:     "otlp",
+				TraceEndpoint:     "localhost:4318",
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name: "success - logging",
+			env:  map[string]string{EnvLogLevel: "debug", EnvLogFormat: "text"},
+			expectedConfig: Config{
+				ListenAddr:        Default().ListenAddr,
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     Default().PublicBaseURL,
+				DocumentDir:       Default().DocumentDir,
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				LogLevel:          "debug",
+				LogFormat:         "text",
+				ReadHeaderTimeout: Default().ReadHeaderTimeout,
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Default().WriteTimeout,
+				IdleTimeout:       Default().IdleTimeout,
+				ShutdownTimeout:   Default().ShutdownTimeout,
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:        "success - server timeouts",
+			env:         map[string]string{EnvWriteTimeout: "2m", EnvDrainDelay: "5s"},
+			fileContent: `{"read_header_timeout": "2s", "shutdown_timeout": "20s"}`,
+			expectedConfig: Config{
+				ListenAddr:        Default().ListenAddr,
+				GRPCListenAddr:    Default().GRPCListenAddr,
+				PublicBaseURL:     Default().PublicBaseURL,
+				DocumentDir:       Default().DocumentDir,
+				MaxBodyBytes:      Default().MaxBodyBytes,
+				RequestTimeout:    Default().RequestTimeout,
+				LogLevel:          Default().LogLevel,
+				LogFormat:         Default().LogFormat,
+				ReadHeaderTimeout: Duration(2 * time.Second),
+				ReadTimeout:       Default().ReadTimeout,
+				WriteTimeout:      Duration(2 * time.Minute),
+				IdleTimeout:       Default().IdleTimeout,
+				DrainDelay:        Duration(5 * time.Second),
+				ShutdownTimeout:   Duration(20 * time.Second),
+				RateLimits:        Default().RateLimits,
+
+				VisitMaxDistanceMeters: Default().VisitMaxDistanceMeters,
+				VisitOutOfRange:        Default().VisitOutOfRange,
+				VisitMaxAge:            Default().VisitMaxAge,
+
+				AuthSecretFile: Default().AuthSecretFile,
+				AuthTokenTTL:   Default().AuthTokenTTL,
+			},
+		},
+		{
+			name:        "success - rate limits merged over defaults",
+			fileContent: `{"rate_limits": {"POST /v1/loan/submit": {"limit": 2, "period": "1m", "burst": 1}, "GET /v1/loan/list": {}}}`,
+			expectedConfig: func() Config {
+				cfg := Default()
+				cfg.RateLimits["POST /v1/loan/submit"] = ratelimit.Policy{Limit: 2, Period: time.Minute, Burst: 1}
+				cfg.RateLimits["GET /v1/loan/list"] = ratelimit.Policy{}
+				return cfg
+			}(),
+		},
+		{
+			name:        "error - invalid rate limit",
+			fileContent: `{"rate_limits": {"default": {"limit": 2, "period": "1m"}}}`,
+			isError:     true,
+		},
+		{
+			name:    "error - write timeout not longer than request timeout",
+			env:     map[string]string{EnvWriteTimeout: "30s"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid shutdown timeout",
+			env:     map[string]string{EnvShutdownTimeout: "0s"},
+			isError: true,
+		},
+		{
+			name:    "error - negative drain delay",
+			env:     map[string]string{EnvDrainDelay: "-1s"},
+			isError: true,
+		},
+		{
+			name:    "error - unknown log level",
+			env:     map[string]string{EnvLogLevel: "verbose"},
+			isError: true,
+		},
+		{
+			name:    "error - unknown log format",
+			env:     map[string]string{EnvLogFormat: "xml"},
+			isError: true,
+		},
+		{
+			name:    "error - unknown trace exporter",
+			env:     map[string]string{EnvTraceExporter: "jaeger"},
+			isError: true,
+		},
+		{
+			name: "success - field visits",
+			env:  map[string]string{EnvVisitRequired: "true", EnvVisitMaxDistanceMeters: "250.5", EnvVisitOutOfRange: "reject", EnvVisitMaxAge: "2h"},
+			expectedConfig: func() Config {
+				cfg := Default()
+				cfg.VisitRequired = true
+				cfg.VisitMaxDistanceMeters = 250.5
+				cfg.VisitOutOfRange = "reject"
+				cfg.VisitMaxAge = Duration(2 * time.Hour)
+				return cfg
+			}(),
+		},
+		{
+			name:    "error - invalid visit max distance",
+			env:     map[string]string{EnvVisitMaxDistanceMeters: "0"},
+			isError: true,
+		},
+		{
+			name:    "error - unknown visit out of range",
+			env:     map[string]string{EnvVisitOutOfRange: "ignore"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid visit max age",
+			env:     map[string]string{EnvVisitMaxAge: "0s"},
+			isError: true,
+		},
+		{
+			name: "success - auth and trusted proxies",
+			env:  map[string]string{EnvAuthSecretFile: "/run/secrets/auth.key", EnvAuthTokenTTL: "1h", EnvTrustedProxies: "10.0.0.0/8, 192.168.1.10"},
+			expectedConfig: func() Config {
+				cfg := Default()
+				cfg.AuthSecretFile = "/run/secrets/auth.key"
+				cfg.AuthTokenTTL = Duration(time.Hour)
+				cfg.TrustedProxies = []string{"10.0.0.0/8", "192.168.1.10"}
+				return cfg
+			}(),
+		},
+		{
+			name: "success - webhooks allowed to private addresses",
+			env:  map[string]string{EnvWebhookAllowPrivate: "true"},
+			expectedConfig: func() Config {
+				cfg := Default()
+				cfg.WebhookAllowPrivate = true
+				return cfg
+			}(),
+		},
+		{
+			name:    "error - invalid webhook allow private",
+			env:     map[string]string{EnvWebhookAllowPrivate: "sometimes"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid trusted proxy",
+			env:     map[string]string{EnvTrustedProxies: "10.0.0.0/33"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid auth token ttl",
+			env:     map[string]string{EnvAuthTokenTTL: "0s"},
+			isError: true,
+		},
+		{
+			name:    "error - invalid public base url",
+			env:     map[string]string{EnvPublicBaseURL: "loan.example.com"},
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if tt.fileContent != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				assert.NoError(t, os.WriteFile(path, []byte(tt.fileContent), 0o600))
+				t.Setenv(EnvConfigFile, path)
+			}
+
+			cfg, err := Load()
+
+			assert.Equal(t, tt.isError, err != nil)
+			if !tt.isError {
+				assert.Equal(t, tt.expectedConfig, cfg)
+			}
+		})
+	}
+}
+
+func TestAgreementURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		publicBaseURL string
+		expectedURL   string
+	}{
+		{
+			name:          "default",
+			publicBaseURL: Default().PublicBaseURL,
+			expectedURL:   "http://localhost:8080/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view",
+		},
+		{
+			name:          "behind proxy with trailing slash",
+			publicBaseURL: "https://loan.example.com/api/",
+			expectedURL:   "https://loan.example.com/api/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{PublicBaseURL: tt.publicBaseURL}
+			assert.Equal(t, tt.expectedURL, cfg.AgreementURL("0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c"))
+		})
+	}
+}
@@ -2,7 +2,27 @@ package constant
 
 const CtxStartTimeKey = "start_time"
 
-const AgreementPrefix = "http://localhost:8080/agreement/%d/view"
+const (
+	// HeaderIdempotencyKey is the request header holding the client chosen idempotency key
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses replayed from a previous request with the same key
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// HeaderAuthorization is the request header holding the bearer token of the user acting on the request
+	HeaderAuthorization = "Authorization"
+	// BearerPrefix prefixes the token in the Authorization header
+	BearerPrefix = "Bearer "
+	// HeaderForwardedFor and HeaderForwarded carry the client address through the reverse proxies
+	HeaderForwardedFor = "X-Forwarded-For"
+	HeaderForwarded    = "Forwarded"
+	// HeaderRequestID is the request header identifying the request in the audit log
+	HeaderRequestID = "X-Request-ID"
+)
+
+// APIVersionPrefix is the path prefix of every versioned REST route
+const APIVersionPrefix = "/v1"
+
+// AgreementPathFormat is the path of agreement view route by agreement public id, joined with the configured public base url
+const AgreementPathFormat = APIVersionPrefix + "/agreement/%s/view"
 
 const (
 	UserTypeBorrower               = 1
@@ -35,3 +55,126 @@ func GetLoanStatusDesc(status int) string {
 
 	return ""
 }
+
+// GetLoanStatusByDesc returns the loan status of the given description, zero if unknown
+func GetLoanStatusByDesc(desc string) int {
+	for status, v := range LoanStatusDesc {
+		if v == desc {
+			return status
+		}
+	}
+
+	return 0
+}
+
+const (
+	AgreementTypeOrganizerBorrower = 1
+	AgreementTypeOrganizerLender   = 2
+	AgreementTypeSignedCopy        = 3
+)
+
+var AgreementTypeDesc = map[int]string{
+	AgreementTypeOrganizerBorrower: "organizer-borrower",
+	AgreementTypeOrganizerLender:   "organizer-lender",
+	AgreementTypeSignedCopy:        "signed-copy",
+}
+
+func GetAgreementTypeDesc(agreementType int) string {
+	desc, ok := AgreementTypeDesc[agreementType]
+	if ok {
+		return desc
+	}
+
+	return ""
+}
+
+// GetAgreementTypeByDesc returns the agreement type of the given description, zero if unknown
+func GetAgreementTypeByDesc(desc string) int {
+	for agreementType, v := range AgreementTypeDesc {
+		if v == desc {
+			return agreementType
+		}
+	}
+
+	return 0
+}
+
+const (
+	TaskTypeValidationVisit   = 1
+	TaskTypeDisbursementVisit = 2
+)
+
+var TaskTypeDesc = map[int]string{
+	TaskTypeValidationVisit:   "validation-visit",
+	TaskTypeDisbursementVisit: "disbursement-visit",
+}
+
+// TaskTypeAssigneeUserType is the user type of the employees a task type is assigned to
+var TaskTypeAssigneeUserType = map[int]int{
+	TaskTypeValidationVisit:   UserTypeFieldValidatorEmployee,
+	TaskTypeDisbursementVisit: UserTypeFieldOfficerEmployee,
+}
+
+func GetTaskTypeDesc(taskType int) string {
+	desc, ok := TaskTypeDesc[taskType]
+	if ok {
+		return desc
+	}
+
+	return ""
+}
+
+// GetTaskTypeByDesc returns the task type of the given description, zero if unknown
+func GetTaskTypeByDesc(desc string) int {
+	for taskType, v := range TaskTypeDesc {
+		if v == desc {
+			return taskType
+		}
+	}
+
+	return 0
+}
+
+const (
+	TaskStatusOpen      = 1
+	TaskStatusCompleted = 2
+)
+
+var TaskStatusDesc = map[int]string{
+	TaskStatusOpen:      "open",
+	TaskStatusCompleted: "completed",
+}
+
+func GetTaskStatusDesc(status int) string {
+	desc, ok := TaskStatusDesc[status]
+	if ok {
+		return desc
+	}
+
+	return ""
+}
+
+// GetTaskStatusByDesc returns the task status of the given description, zero if unknown
+func GetTaskStatusByDesc(desc string) int {
+	for status, v := range TaskStatusDesc {
+		if v == desc {
+			return status
+		}
+	}
+
+	return 0
+}
+
+const (
+	LocaleIndonesian = "id"
+	LocaleEnglish    = "en"
+
+	DefaultLocale = LocaleIndonesian
+)
+
+const (
+	// VisitOutOfRangeFlag accepts a field visit farther than allowed from the borrower address, flagging it
+	VisitOutOfRangeFlag = "flag"
+	// VisitOutOfRangeReject rejects a field visit farther than allowed from the borrower address
+	VisitOutOfRangeReject = "reject"
+)
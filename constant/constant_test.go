@@ -46,3 +46,107 @@ func TestGetLoanStatusDesc(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAgreementTypeDesc(t *testing.T) {
+	tests := []struct {
+		name          string
+		agreementType int
+		expectedDesc  string
+	}{
+		{
+			name:          "type organizer borrower",
+			agreementType: AgreementTypeOrganizerBorrower,
+			expectedDesc:  "organizer-borrower",
+		},
+		{
+			name:          "type organizer lender",
+			agreementType: AgreementTypeOrganizerLender,
+			expectedDesc:  "organizer-lender",
+		},
+		{
+			name:          "type signed copy",
+			agreementType: AgreementTypeSignedCopy,
+			expectedDesc:  "signed-copy",
+		},
+		{
+			name:          "type unknown",
+			agreementType: 999,
+			expectedDesc:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedDesc, GetAgreementTypeDesc(tt.agreementType))
+			if tt.expectedDesc != "" {
+				assert.Equal(t, tt.agreementType, GetAgreementTypeByDesc(tt.expectedDesc))
+			}
+		})
+	}
+}
+
+func TestGetTaskTypeDesc(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskType     int
+		expectedDesc string
+	}{
+		{
+			name:         "type validation visit",
+			taskType:     TaskTypeValidationVisit,
+			expectedDesc: "validation-visit",
+		},
+		{
+			name:         "type disbursement visit",
+			taskType:     TaskTypeDisbursementVisit,
+			expectedDesc: "disbursement-visit",
+		},
+		{
+			name:         "type unknown",
+			taskType:     999,
+			expectedDesc: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedDesc, GetTaskTypeDesc(tt.taskType))
+			if tt.expectedDesc != "" {
+				assert.Equal(t, tt.taskType, GetTaskTypeByDesc(tt.expectedDesc))
+			}
+		})
+	}
+}
+
+func TestGetTaskStatusDesc(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		expectedDesc string
+	}{
+		{
+			name:         "status open",
+			status:       TaskStatusOpen,
+			expectedDesc: "open",
+		},
+		{
+			name:         "status completed",
+			status:       TaskStatusCompleted,
+			expectedDesc: "completed",
+		},
+		{
+			name:         "status unknown",
+			status:       999,
+			expectedDesc: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedDesc, GetTaskStatusDesc(tt.status))
+			if tt.expectedDesc != "" {
+				assert.Equal(t, tt.status, GetTaskStatusByDesc(tt.expectedDesc))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"amartha-test/constant"
+)
+
+type numberFormat struct {
+	thousandSeparator string
+	decimalSeparator  string
+}
+
+var numberFormats = map[string]numberFormat{
+	constant.LocaleIndonesian: {thousandSeparator: ".", decimalSeparator: ","},
+	constant.LocaleEnglish:    {thousandSeparator: ",", decimalSeparator: "."},
+}
+
+var monthNames = map[string][12]string{
+	constant.LocaleIndonesian: {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	constant.LocaleEnglish:    {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+// messages is the catalogue of every agreement text, keyed by locale then message key
+var messages = map[string]map[string]string{
+	constant.LocaleIndonesian: {
+		"organizer_borrower_title": "PERJANJIAN PENYELENGGARA-PEMINJAM",
+		"organizer_lender_title":   "PERJANJIAN PENYELENGGARA-PEMBERI PINJAMAN",
+		"loan_id":                  "ID Pinjaman",
+		"agreement_date":           "Tanggal Perjanjian",
+		"borrower_id":              "ID Peminjam",
+		"borrower_name":            "Nama Peminjam",
+		"principal_amount":         "Jumlah Pokok",
+		"interest_rate":            "Suku Bunga",
+		"debt_amount":              "Jumlah Utang",
+		"lender_id":                "ID Pemberi Pinjaman",
+		"lender_name":              "Nama Pemberi Pinjaman",
+		"invested_amount":          "Jumlah Pendanaan",
+		"return_amount":            "Jumlah Pengembalian",
+		"sign":                     "Tanda Tangan",
+		"signed":                   "SUDAH DITANDATANGANI",
+		"unsigned":                 "BELUM DITANDATANGANI",
+	},
+	constant.LocaleEnglish: {
+		"organizer_borrower_title": "ORGANIZER-BORROWER AGREEMENT",
+		"organizer_lender_title":   "ORGANIZER-LENDER AGREEMENT",
+		"loan_id":                  "Loan ID",
+		"agreement_date":           "Agreement Date",
+		"borrower_id":              "Borrower ID",
+		"borrower_name":            "Borrower Name",
+		"principal_amount":         "Principal Amount",
+		"interest_rate":            "Interest Rate",
+		"debt_amount":              "Amount of debt",
+		"lender_id":                "Lender ID",
+		"lender_name":              "Lender Name",
+		"invested_amount":          "Invested Amount",
+		"return_amount":            "Return Amount",
+		"sign":                     "Sign",
+		"signed":                   "SIGNED",
+		"unsigned":                 "UNSIGNED",
+	},
+}
+
+// Locales returns every locale supported by the message catalogue
+func Locales() []string {
+	return []string{constant.LocaleIndonesian, constant.LocaleEnglish}
+}
+
+// NormalizeLocale returns the given locale if supported, otherwise the default locale
+func NormalizeLocale(locale string) string {
+	if _, ok := messages[locale]; ok {
+		return locale
+	}
+
+	return constant.DefaultLocale
+}
+
+// Translate returns the catalogue message of the given key in the given locale
+func Translate(locale, key string) (string, error) {
+	message, ok := messages[NormalizeLocale(locale)][key]
+	if !ok {
+		return "", fmt.Errorf("message %q is not found in locale %q", key, NormalizeLocale(locale))
+	}
+
+	return message, nil
+}
+
+// FormatNumber formats the number with two decimals and the locale thousand separators
+func FormatNumber(locale string, number float64) string {
+	format := numberFormats[NormalizeLocale(locale)]
+
+	sign := ""
+	if number < 0 {
+		sign = "-"
+		number = -number
+	}
+
+	cents := int64(math.Round(number * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+	fraction := fmt.Sprintf("%02d", cents%100)
+
+	var sb strings.Builder
+	for i, v := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(format.thousandSeparator)
+		}
+		sb.WriteRune(v)
+	}
+
+	return sign + sb.String() + format.decimalSeparator + fraction
+}
+
+// FormatRupiah formats the amount as rupiah, e.g. "Rp 1.500.000,00" in Indonesian
+func FormatRupiah(locale string, amount float64) string {
+	return "Rp " + FormatNumber(locale, amount)
+}
+
+// FormatRate formats the interest rate as percentage, e.g. "12,50%" in Indonesian
+func FormatRate(locale string, rate float64) string {
+	return FormatNumber(locale, rate*100) + "%"
+}
+
+// FormatDate formats the date with the locale month name, e.g. "17 Agustus 2026" in Indonesian
+func FormatDate(locale string, date time.Time) string {
+	return fmt.Sprintf("%d %s %d", date.Day(), monthNames[NormalizeLocale(locale)][date.Month()-1], date.Year())
+}
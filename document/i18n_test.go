@@ -0,0 +1,98 @@
+package document
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/constant"
+)
+
+func TestCatalogueCompleteness(t *testing.T) {
+	reference := messages[constant.DefaultLocale]
+	for _, locale := range Locales() {
+		assert.Len(t, messages[locale], len(reference), "locale %s", locale)
+		for key := range reference {
+			_, err := Translate(locale, key)
+			assert.NoError(t, err, "locale %s", locale)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name            string
+		locale          string
+		key             string
+		expectedMessage string
+		isError         bool
+	}{
+		{
+			name:            "indonesian",
+			locale:          constant.LocaleIndonesian,
+			key:             "borrower_name",
+			expectedMessage: "Nama Peminjam",
+		},
+		{
+			name:            "english",
+			locale:          constant.LocaleEnglish,
+			key:             "borrower_name",
+			expectedMessage: "Borrower Name",
+		},
+		{
+			name:            "unknown locale falls back to default",
+			locale:          "fr",
+			key:             "borrower_name",
+			expectedMessage: "Nama Peminjam",
+		},
+		{
+			name:    "error - unknown key",
+			locale:  constant.LocaleEnglish,
+			key:     "unknown",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Translate(tt.locale, tt.key)
+			assert.Equal(t, tt.isError, err != nil)
+			assert.Equal(t, tt.expectedMessage, message)
+		})
+	}
+}
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         string
+		amount         float64
+		expectedAmount string
+	}{
+		{name: "indonesian millions", locale: constant.LocaleIndonesian, amount: 1500000, expectedAmount: "Rp 1.500.000,00"},
+		{name: "indonesian cents", locale: constant.LocaleIndonesian, amount: 1234.5, expectedAmount: "Rp 1.234,50"},
+		{name: "indonesian small", locale: constant.LocaleIndonesian, amount: 999, expectedAmount: "Rp 999,00"},
+		{name: "english millions", locale: constant.LocaleEnglish, amount: 1500000, expectedAmount: "Rp 1,500,000.00"},
+		{name: "english negative", locale: constant.LocaleEnglish, amount: -2500.75, expectedAmount: "Rp -2,500.75"},
+		{name: "zero", locale: constant.LocaleEnglish, amount: 0, expectedAmount: "Rp 0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedAmount, FormatRupiah(tt.locale, tt.amount))
+		})
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "12,50%", FormatRate(constant.LocaleIndonesian, 0.125))
+	assert.Equal(t, "12.50%", FormatRate(constant.LocaleEnglish, 0.125))
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2026, time.August, 17, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "17 Agustus 2026", FormatDate(constant.LocaleIndonesian, date))
+	assert.Equal(t, "17 August 2026", FormatDate(constant.LocaleEnglish, date))
+}
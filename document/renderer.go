@@ -0,0 +1,35 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf/v2"
+)
+
+// RenderPDF renders the named template of the given version into a PDF document.
+// Every line of the rendered text becomes a cell, empty lines add vertical spacing.
+func RenderPDF(name, version string, data AgreementData) ([]byte, error) {
+	text, err := Execute(name, version, data)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			pdf.Cell(40, 10, line)
+		}
+		pdf.Ln(5)
+	}
+
+	var buf bytes.Buffer
+	err = pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
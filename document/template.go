@@ -0,0 +1,129 @@
+package document
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"amartha-test/model"
+)
+
+// CurrentVersion is the template version used for newly generated agreements
+const CurrentVersion = "v2"
+
+const (
+	TemplateOrganizerBorrower = "organizer_borrower"
+	TemplateOrganizerLender   = "organizer_lender"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// AgreementData is the data passed into every agreement template
+type AgreementData struct {
+	Loan     model.Loan
+	Borrower model.User
+	Lender   model.User
+	Lending  model.Lending
+	Signed   bool
+	Locale   string
+	Date     time.Time
+}
+
+// DebtAmount is the total amount the borrower has to return
+func (d AgreementData) DebtAmount() float64 {
+	return d.Loan.CalculateReturnAmount()
+}
+
+var templateFuncs = template.FuncMap{
+	"money": func(amount float64) string {
+		return fmt.Sprintf("%.2f", amount)
+	},
+	"percent": func(rate float64) string {
+		return fmt.Sprintf("%.2f", rate*100)
+	},
+}
+
+// localeFuncs returns the template functions bound to the given locale
+func localeFuncs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string) (string, error) {
+			return Translate(locale, key)
+		},
+		"rupiah": func(amount float64) string {
+			return FormatRupiah(locale, amount)
+		},
+		"rate": func(rate float64) string {
+			return FormatRate(locale, rate)
+		},
+		"date": func(date time.Time) string {
+			return FormatDate(locale, date)
+		},
+	}
+}
+
+// ErrTemplateNotFound is returned when the requested template name or version does not exist
+var ErrTemplateNotFound = errors.New("agreement template is not found")
+
+// Versions returns every available template version, sorted ascending
+func Versions() []string {
+	entries, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil
+	}
+
+	var versions []string
+	for _, v := range entries {
+		if v.IsDir() {
+			versions = append(versions, v.Name())
+		}
+	}
+	sort.Strings(versions)
+
+	return versions
+}
+
+// Templates returns every template name available in the given version
+func Templates(version string) []string {
+	entries, err := fs.ReadDir(templateFS, path.Join("templates", version))
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, v := range entries {
+		if !v.IsDir() && strings.HasSuffix(v.Name(), ".tmpl") {
+			names = append(names, strings.TrimSuffix(v.Name(), ".tmpl"))
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Execute renders the named template of the given version into plain text, in the locale of the data
+func Execute(name, version string, data AgreementData) (string, error) {
+	content, err := templateFS.ReadFile(path.Join("templates", version, name+".tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s/%s", ErrTemplateNotFound, version, name)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Funcs(localeFuncs(data.Locale)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,167 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/constant"
+	"amartha-test/model"
+)
+
+var fixtureLoans = []struct {
+	name string
+	data AgreementData
+}{
+	{
+		name: "single lender unsigned",
+		data: AgreementData{
+			Loan: model.Loan{
+				LoanID:          1,
+				PublicID:        "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+				BorrowerID:      1,
+				PrincipalAmount: 5000000,
+				CollectedAmount: 5000000,
+				InterestRate:    0.1,
+			},
+			Borrower: model.User{UserID: 1, UserName: "Septian"},
+			Lender:   model.User{UserID: 2, UserName: "Pratama"},
+			Lending:  model.Lending{LenderID: 2, InvestedAmount: 5000000, ReturnAmount: 5500000},
+			Date:     time.Date(2026, time.August, 17, 0, 0, 0, 0, time.UTC),
+		},
+	},
+	{
+		name: "syndicated loan signed",
+		data: AgreementData{
+			Loan: model.Loan{
+				LoanID:          2,
+				PublicID:        "b8e1f4a2-6c3d-4f9e-8a1b-7d2c5e9f0a13",
+				BorrowerID:      1,
+				PrincipalAmount: 1000000,
+				CollectedAmount: 1000000,
+				InterestRate:    0.125,
+				Lending: []model.Lending{
+					{LenderID: 2, InvestedAmount: 400000, ReturnAmount: 450000},
+					{LenderID: 3, InvestedAmount: 600000, ReturnAmount: 675000},
+				},
+			},
+			Borrower: model.User{UserID: 1, UserName: "Septian"},
+			Lender:   model.User{UserID: 3, UserName: "Rusmana"},
+			Lending:  model.Lending{LenderID: 3, InvestedAmount: 600000, ReturnAmount: 675000},
+			Signed:   true,
+			Date:     time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		},
+	},
+	{
+		name: "zero interest rate",
+		data: AgreementData{
+			Loan:     model.Loan{LoanID: 3, PublicID: "0f3a9d2c-5b7e-4c1a-8d6f-2e9b4a7c1d58", BorrowerID: 1, PrincipalAmount: 250000},
+			Borrower: model.User{UserID: 1, UserName: "Septian"},
+		},
+	},
+}
+
+func TestRenderEveryTemplate(t *testing.T) {
+	versions := Versions()
+	assert.Contains(t, versions, CurrentVersion)
+
+	for _, version := range versions {
+		names := Templates(version)
+		assert.NotEmpty(t, names)
+
+		for _, name := range names {
+			for _, locale := range Locales() {
+				for _, fixture := range fixtureLoans {
+					data := fixture.data
+					data.Locale = locale
+
+					t.Run(version+"/"+name+"/"+locale+"/"+fixture.name, func(t *testing.T) {
+						text, err := Execute(name, version, data)
+						assert.NoError(t, err)
+						assert.NotEmpty(t, strings.TrimSpace(text))
+
+						pdfData, err := RenderPDF(name, version, data)
+						assert.NoError(t, err)
+						assert.True(t, bytes.HasPrefix(pdfData, []byte("%PDF-")))
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateName string
+		version      string
+		data         AgreementData
+		contains     []string
+		isError      bool
+	}{
+		{
+			name:         "error - template not found",
+			templateName: "unknown",
+			version:      CurrentVersion,
+			isError:      true,
+		},
+		{
+			name:         "error - version not found",
+			templateName: TemplateOrganizerBorrower,
+			version:      "v0",
+			isError:      true,
+		},
+		{
+			name:         "success - v1 borrower unsigned",
+			templateName: TemplateOrganizerBorrower,
+			version:      "v1",
+			data:         fixtureLoans[0].data,
+			contains:     []string{"ORGANIZER-BORROWER AGREEMENT [Loan ID: 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24]", "Amount of debt: Rp 5500000.00", "Sign: UNSIGNED"},
+		},
+		{
+			name:         "success - borrower unsigned in indonesian",
+			templateName: TemplateOrganizerBorrower,
+			version:      CurrentVersion,
+			data:         withLocale(fixtureLoans[0].data, constant.LocaleIndonesian),
+			contains:     []string{"PERJANJIAN PENYELENGGARA-PEMINJAM [ID Pinjaman: 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24]", "Tanggal Perjanjian: 17 Agustus 2026", "Jumlah Utang: Rp 5.500.000,00", "Tanda Tangan: BELUM DITANDATANGANI"},
+		},
+		{
+			name:         "success - lender signed in english",
+			templateName: TemplateOrganizerLender,
+			version:      CurrentVersion,
+			data:         withLocale(fixtureLoans[1].data, constant.LocaleEnglish),
+			contains:     []string{"ORGANIZER-LENDER AGREEMENT [Loan ID: b8e1f4a2-6c3d-4f9e-8a1b-7d2c5e9f0a13]", "Agreement Date: 1 October 2026", "Lender Name: Rusmana", "Interest Rate: 12.50%", "Return Amount: Rp 675,000.00", "Sign: SIGNED"},
+		},
+		{
+			name:         "success - v1 lender signed",
+			templateName: TemplateOrganizerLender,
+			version:      "v1",
+			data:         fixtureLoans[1].data,
+			contains:     []string{"Lender Name: Rusmana", "Interest Rate: 12.50%", "Sign: SIGNED"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := Execute(tt.templateName, tt.version, tt.data)
+			if tt.isError {
+				assert.True(t, errors.Is(err, ErrTemplateNotFound))
+				return
+			}
+
+			assert.NoError(t, err)
+			for _, v := range tt.contains {
+				assert.Contains(t, text, v)
+			}
+		})
+	}
+}
+
+func withLocale(data AgreementData, locale string) AgreementData {
+	data.Locale = locale
+	return data
+}
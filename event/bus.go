@@ -0,0 +1,69 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"amartha-test/logging"
+)
+
+// Handler handles a published event, it is called on the publisher goroutine so it must not block
+type Handler func(ctx context.Context, e Event)
+
+type IBus interface {
+	Publish(ctx context.Context, e Event)
+	Subscribe(handler Handler) (unsubscribe func())
+}
+
+// Bus is an in-process event bus delivering every event to every subscriber
+type Bus struct {
+	mutex       sync.RWMutex
+	nextID      int64
+	subscribers map[int64]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[int64]Handler),
+	}
+}
+
+// Publish calls every subscriber with the event, a panicking subscriber does not stop the others
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	b.mutex.RLock()
+	handlers := make([]Handler, 0, len(b.subscribers))
+	for _, v := range b.subscribers {
+		handlers = append(handlers, v)
+	}
+	b.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		b.call(ctx, handler, e)
+	}
+}
+
+// Subscribe adds the handler until unsubscribe is called
+func (b *Bus) Subscribe(handler Handler) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscribers[id] = handler
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+func (b *Bus) call(ctx context.Context, handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ctx).Error("subscriber panic", "op", "Publish", "event_id", e.ID, "type", e.Type, "panic", r)
+		}
+	}()
+
+	handler(ctx, e)
+}
@@ -0,0 +1,99 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"amartha-test/model"
+)
+
+// Type is the type of a domain event, named "<entity>.<change>"
+type Type string
+
+const (
+	LoanSubmitted       Type = "loan.submitted"
+	LoanApproved        Type = "loan.approved"
+	LoanInvestmentAdded Type = "loan.investment_added"
+	LoanInvested        Type = "loan.invested"
+	// AgreementReady carries the lending of a generated organizer-lender agreement,
+	// without lending it is the organizer-borrower agreement of the loan
+	AgreementReady  Type = "agreement.ready"
+	AgreementSigned Type = "agreement.signed"
+	LoanSigned      Type = "loan.signed"
+	LoanDisbursed   Type = "loan.disbursed"
+)
+
+var types = []Type{
+	LoanSubmitted,
+	LoanApproved,
+	LoanInvestmentAdded,
+	LoanInvested,
+	AgreementReady,
+	AgreementSigned,
+	LoanSigned,
+	LoanDisbursed,
+}
+
+// Types returns every event type, in loan lifecycle order
+func Types() []Type {
+	return append([]Type(nil), types...)
+}
+
+// IsValidType reports whether the event type is known
+func IsValidType(eventType string) bool {
+	for _, v := range types {
+		if string(v) == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event is a state change of a loan, carrying the loan as it is after the change
+type Event struct {
+	ID         string    `json:"id"`
+	Type       Type      `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// UserID is the user whose action emitted the event
+	UserID    int64            `json:"user_id"`
+	Loan      model.Loan       `json:"loan"`
+	Agreement *model.Aggrement `json:"agreement,omitempty"`
+	Lending   *model.Lending   `json:"lending,omitempty"`
+}
+
+// New returns a new event of the loan, the picture proof is left out since events leave the service
+func New(eventType Type, userID int64, loan model.Loan) Event {
+	if loan.ApprovalInfo != nil {
+		approvalInfo := *loan.ApprovalInfo
+		approvalInfo.PictureProof = ""
+		loan.ApprovalInfo = &approvalInfo
+	}
+
+	return Event{
+		ID:         newID(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		UserID:     userID,
+		Loan:       loan,
+	}
+}
+
+// WithAgreement returns the event carrying the agreement
+func (e Event) WithAgreement(agreement model.Aggrement) Event {
+	e.Agreement = &agreement
+	return e
+}
+
+// WithLending returns the event carrying the lending
+func (e Event) WithLending(lending model.Lending) Event {
+	e.Lending = &lending
+	return e
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/model"
+)
+
+func TestNew(t *testing.T) {
+	loan := model.Loan{
+		LoanID: 1,
+		ApprovalInfo: &model.ApprovalInfo{
+			PictureProof:             "aW1hZ2U=",
+			FieldValidatorEmployeeID: 4,
+		},
+	}
+
+	e := New(LoanApproved, 4, loan)
+
+	assert.Regexp(t, "^evt_[0-9a-f]{32}$", e.ID)
+	assert.Equal(t, LoanApproved, e.Type)
+	assert.False(t, e.OccurredAt.IsZero())
+	assert.Equal(t, int64(4), e.UserID)
+	assert.Empty(t, e.Loan.ApprovalInfo.PictureProof)
+	assert.Equal(t, int64(4), e.Loan.ApprovalInfo.FieldValidatorEmployeeID)
+	// the loan of the caller is untouched
+	assert.Equal(t, "aW1hZ2U=", loan.ApprovalInfo.PictureProof)
+	assert.NotEqual(t, e.ID, New(LoanApproved, 4, loan).ID)
+}
+
+func TestIsValidType(t *testing.T) {
+	for _, v := range Types() {
+		assert.True(t, IsValidType(string(v)))
+	}
+	assert.False(t, IsValidType("loan.unknown"))
+	assert.False(t, IsValidType(""))
+}
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+
+	var first, second []Type
+	unsubscribeFirst := bus.Subscribe(func(ctx context.Context, e Event) {
+		first = append(first, e.Type)
+	})
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		panic("broken subscriber")
+	})
+	bus.Subscribe(func(ctx context.Context, e Event) {
+		second = append(second, e.Type)
+	})
+
+	bus.Publish(context.Background(), New(LoanSubmitted, 1, model.Loan{LoanID: 1}))
+	unsubscribeFirst()
+	bus.Publish(context.Background(), New(LoanApproved, 4, model.Loan{LoanID: 1}))
+
+	assert.Equal(t, []Type{LoanSubmitted}, first)
+	assert.Equal(t, []Type{LoanSubmitted, LoanApproved}, second)
+}
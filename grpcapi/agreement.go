@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+
+	"amartha-test/apperror"
+	"amartha-test/auth"
+	"amartha-test/model"
+	"amartha-test/pb"
+	"amartha-test/service"
+)
+
+// AgreementServer is grpc server of the agreement service
+type AgreementServer struct {
+	pb.UnimplementedAgreementServiceServer
+	Service service.IService
+}
+
+func (s *AgreementServer) ListAgreements(ctx context.Context, req *pb.ListAgreementsRequest) (*pb.ListAgreementsResponse, error) {
+	agreements := s.Service.ListAgreements(ctx, model.AgreementFilter{
+		LoanPublicID:  req.GetLoanId(),
+		UserID:        req.GetUserId(),
+		AgreementType: int(req.GetAgreementType()),
+		CreatedFrom:   fromTimestamp(req.GetCreatedFrom()),
+		CreatedTo:     fromTimestamp(req.GetCreatedTo()),
+	})
+
+	resp := &pb.ListAgreementsResponse{}
+	for _, v := range agreements {
+		resp.Agreements = append(resp.Agreements, toAgreement(v))
+	}
+
+	return resp, nil
+}
+
+func (s *AgreementServer) GetAgreementDocument(ctx context.Context, req *pb.GetAgreementDocumentRequest) (*pb.AgreementDocument, error) {
+	userID, _ := auth.UserIDFrom(ctx)
+	agreement, document, err := s.Service.OpenAgreement(ctx, req.GetPublicId(), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer document.Content.Close()
+
+	content, err := io.ReadAll(document.Content)
+	if err != nil {
+		return nil, apperror.Internal.Wrap(err).WithDetail("agreement_id", agreement.PublicID)
+	}
+
+	return &pb.AgreementDocument{
+		Agreement:   toAgreement(agreement),
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+func (s *AgreementServer) SignAgreement(ctx context.Context, req *pb.SignAgreementRequest) (*pb.Loan, error) {
+	userID, _ := auth.UserIDFrom(ctx)
+	loan, err := s.Service.SignAgreement(ctx, req.GetPublicId(), req.GetLoanId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/logging"
+)
+
+// authInterceptor carries the user of the bearer token of the authorization metadata in the call context, like the
+// Authenticate middleware of the REST API. A call without token is anonymous, an invalid token is rejected
+func authInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if authenticator == nil {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		header := metadataCarrier(md).Get(constant.HeaderAuthorization)
+		if header == "" {
+			return handler(ctx, req)
+		}
+
+		token, ok := strings.CutPrefix(header, constant.BearerPrefix)
+		if !ok {
+			return nil, apperror.Unauthenticated.New().WithDetail("reason", "authorization must be a bearer token")
+		}
+		userID, err := authenticator.Verify(token, time.Now())
+		if err != nil {
+			logging.FromContext(ctx).Info("rejected bearer token", "op", "Authenticate", "error", err)
+			return nil, apperror.Unauthenticated.Wrap(err).WithDetail("reason", err.Error())
+		}
+
+		ctx = auth.WithUserID(ctx, userID)
+		ctx = audit.WithActor(ctx, audit.UserActor(userID))
+		return handler(ctx, req)
+	}
+}
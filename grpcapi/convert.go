@@ -0,0 +1,164 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"amartha-test/model"
+	"amartha-test/pb"
+)
+
+// toTimestamp returns nil for zero time so unset times stay unset on the wire
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+// toOptionalTimestamp returns nil for nil time
+func toOptionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return toTimestamp(*t)
+}
+
+// fromTimestamp returns zero time for nil timestamp
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
+
+func toUser(user model.User) *pb.User {
+	return &pb.User{
+		UserId:      user.UserID,
+		UserName:    user.UserName,
+		UserType:    int32(user.UserType),
+		Locale:      user.Locale,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Address:     user.Address,
+		Location:    toGeoPoint(user.Location),
+		Region:      user.Region,
+	}
+}
+
+func toGeoPoint(point *model.GeoPoint) *pb.GeoPoint {
+	if point == nil {
+		return nil
+	}
+
+	return &pb.GeoPoint{
+		Latitude:  point.Latitude,
+		Longitude: point.Longitude,
+	}
+}
+
+func toVisit(visit *model.Visit) *pb.Visit {
+	if visit == nil {
+		return nil
+	}
+
+	// a missing visit has no location
+	location := toGeoPoint(&visit.Location)
+	if visit.IsMissing {
+		location = nil
+	}
+
+	return &pb.Visit{
+		Location:       location,
+		VisitedAt:      toTimestamp(visit.VisitedAt),
+		DistanceMeters: visit.DistanceMeters,
+		IsOutOfRange:   visit.IsOutOfRange,
+		IsMissing:      visit.IsMissing,
+	}
+}
+
+// fromVisit returns nil for nil visit, the distance and flag are left to the service
+func fromVisit(visit *pb.Visit) *model.Visit {
+	if visit == nil {
+		return nil
+	}
+
+	return &model.Visit{
+		Location: model.GeoPoint{
+			Latitude:  visit.GetLocation().GetLatitude(),
+			Longitude: visit.GetLocation().GetLongitude(),
+		},
+		VisitedAt: fromTimestamp(visit.GetVisitedAt()),
+	}
+}
+
+func toLoan(loan model.Loan) *pb.Loan {
+	result := &pb.Loan{
+		LoanId:                        loan.PublicID,
+		TrxId:                         loan.TrxID,
+		BorrowerId:                    loan.BorrowerID,
+		PrincipalAmount:               loan.PrincipalAmount,
+		CollectedAmount:               loan.CollectedAmount,
+		InterestRate:                  loan.InterestRate,
+		Status:                        int32(loan.Status),
+		StatusDesc:                    loan.StatusDesc,
+		OrganizerBorrowerAggrementUrl: loan.OrganizerBorrowerAggrementURL,
+		DisbursementInfo: &pb.DisbursementInfo{
+			AgreementSignedUrls: loan.DisbursementInfo.AgreementSignedURLs,
+			FieldOfficerId:      loan.DisbursementInfo.FieldOfficerID,
+			DisbursementDate:    toTimestamp(loan.DisbursementInfo.DisbursementDate),
+			Visit:               toVisit(loan.DisbursementInfo.Visit),
+		},
+		CreatedAt: toTimestamp(loan.CreatedAt),
+	}
+
+	if loan.ApprovalInfo != nil {
+		result.ApprovalInfo = &pb.ApprovalInfo{
+			FieldValidatorEmployeeId: loan.ApprovalInfo.FieldValidatorEmployeeID,
+			ApprovalDate:             toTimestamp(loan.ApprovalInfo.ApprovalDate),
+			Visit:                    toVisit(loan.ApprovalInfo.Visit),
+		}
+		if proof := loan.ApprovalInfo.Proof; proof != nil {
+			result.ApprovalInfo.Proof = &pb.PictureProof{
+				DocumentKey:  proof.DocumentKey,
+				ThumbnailKey: proof.ThumbnailKey,
+				ContentType:  proof.ContentType,
+				Width:        int32(proof.Width),
+				Height:       int32(proof.Height),
+				SizeBytes:    int64(proof.SizeBytes),
+			}
+		}
+	}
+
+	for _, v := range loan.Lending {
+		result.Lending = append(result.Lending, &pb.Lending{
+			LenderId:                    v.LenderID,
+			InvestedAmount:              v.InvestedAmount,
+			OrganizerLenderAggrementUrl: v.OrganizerLenderAggrementURL,
+			ReturnAmount:                v.ReturnAmount,
+		})
+	}
+
+	return result
+}
+
+func toAgreement(agreement model.Aggrement) *pb.Agreement {
+	return &pb.Agreement{
+		AgreementId:       agreement.PublicID,
+		LoanId:            agreement.LoanPublicID,
+		AgreementType:     int32(agreement.AgreementType),
+		AgreementTypeDesc: agreement.AgreementTypeDesc,
+		SupersedesId:      agreement.SupersedesPublicID,
+		DocumentKey:       agreement.DocumentKey,
+		UserId:            agreement.UserID,
+		IsSigned:          agreement.IsSigned,
+		TemplateVersion:   agreement.TemplateVersion,
+		Locale:            agreement.Locale,
+		CreatedAt:         toTimestamp(agreement.CreatedAt),
+		SignedAt:          toOptionalTimestamp(agreement.SignedAt),
+	}
+}
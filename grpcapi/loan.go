@@ -0,0 +1,93 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+
+	"amartha-test/apperror"
+	"amartha-test/model"
+	"amartha-test/pb"
+	"amartha-test/service"
+)
+
+// LoanServer is grpc server of the loan service
+type LoanServer struct {
+	pb.UnimplementedLoanServiceServer
+	Service service.IService
+}
+
+func (s *LoanServer) ListLoans(ctx context.Context, req *pb.ListLoansRequest) (*pb.ListLoansResponse, error) {
+	loans := s.Service.ListLoans(ctx, model.LoanFilter{
+		Status:            int(req.GetStatus()),
+		BorrowerID:        req.GetBorrowerId(),
+		LenderID:          req.GetLenderId(),
+		CreatedFrom:       fromTimestamp(req.GetCreatedFrom()),
+		CreatedTo:         fromTimestamp(req.GetCreatedTo()),
+		IsVisitOutOfRange: req.GetVisitOutOfRange(),
+	})
+
+	resp := &pb.ListLoansResponse{}
+	for _, v := range loans {
+		resp.Loans = append(resp.Loans, toLoan(v))
+	}
+
+	return resp, nil
+}
+
+func (s *LoanServer) GetLoan(ctx context.Context, req *pb.GetLoanRequest) (*pb.Loan, error) {
+	loan, err := s.Service.GetLoan(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
+
+func (s *LoanServer) SubmitLoan(ctx context.Context, req *pb.SubmitLoanRequest) (*pb.Loan, error) {
+	loan, err := s.Service.SubmitLoan(ctx, req.GetBorrowerId(), req.GetPrincipalAmount(), req.GetInterestRate())
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
+
+func (s *LoanServer) ApproveLoan(ctx context.Context, req *pb.ApproveLoanRequest) (*pb.Loan, error) {
+	pictureProof, err := base64.StdEncoding.DecodeString(req.GetPictureProof())
+	if err != nil {
+		return nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "picture_proof")
+	}
+
+	loan, err := s.Service.ApproveLoan(ctx, req.GetLoanId(), model.ApprovalInfo{
+		FieldValidatorEmployeeID: req.GetFieldValidatorEmployeeId(),
+		ApprovalDate:             fromTimestamp(req.GetApprovalDate()),
+		Visit:                    fromVisit(req.GetVisit()),
+	}, pictureProof)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
+
+func (s *LoanServer) InvestLoan(ctx context.Context, req *pb.InvestLoanRequest) (*pb.Loan, error) {
+	loan, err := s.Service.Invest(ctx, req.GetLoanId(), req.GetLenderId(), req.GetInvestedAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
+
+func (s *LoanServer) DisburseLoan(ctx context.Context, req *pb.DisburseLoanRequest) (*pb.Loan, error) {
+	loan, err := s.Service.Disburse(ctx, req.GetLoanId(), model.Disbursement{
+		FieldOfficerID:   req.GetFieldOfficerId(),
+		DisbursementDate: fromTimestamp(req.GetDisbursementDate()),
+		Visit:            fromVisit(req.GetVisit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoan(loan), nil
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"amartha-test/apperror"
+	"amartha-test/auth"
+	"amartha-test/logging"
+	"amartha-test/pb"
+	"amartha-test/service"
+)
+
+// ErrorDomain is the domain of the error info attached to every error status
+const ErrorDomain = "amartha-test"
+
+// NewServer returns a grpc server serving the user, loan and agreement services on top of the service layer,
+// the calls are authenticated by the bearer token of their authorization metadata, nil authenticator skips it
+func NewServer(svc service.IService, authenticator *auth.Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(traceInterceptor, logInterceptor, errorInterceptor, authInterceptor(authenticator)))
+	server := grpc.NewServer(opts...)
+
+	pb.RegisterUserServiceServer(server, &UserServer{Service: svc})
+	pb.RegisterLoanServiceServer(server, &LoanServer{Service: svc})
+	pb.RegisterAgreementServiceServer(server, &AgreementServer{Service: svc})
+
+	return server
+}
+
+// logInterceptor carries the default logger in the call context, with the method and trace id of the call as
+// attributes of every log
+func logInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	logger := slog.Default().With("grpc_method", info.FullMethod)
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		logger = logger.With("trace_id", spanContext.TraceID().String())
+	}
+
+	return handler(logging.WithLogger(ctx, logger), req)
+}
+
+// errorInterceptor converts the coded errors returned by the servers to grpc status
+func errorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return resp, err
+		}
+
+		logging.FromContext(ctx).Info("call failed", "error", err)
+		return resp, toStatus(err).Err()
+	}
+
+	return resp, nil
+}
+
+// toStatus maps the coded error to the grpc code of its http status, the error code and details are kept in the error info
+func toStatus(err error) *status.Status {
+	appErr := apperror.From(err)
+
+	st := status.New(grpcCode(appErr.HTTPStatus), appErr.Message)
+	errorInfo := &errdetails.ErrorInfo{
+		Reason: string(appErr.Code),
+		Domain: ErrorDomain,
+	}
+	if len(appErr.Details) > 0 {
+		errorInfo.Metadata = make(map[string]string, len(appErr.Details))
+		for k, v := range appErr.Details {
+			errorInfo.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+
+	withDetails, detailErr := st.WithDetails(errorInfo)
+	if detailErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,301 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"amartha-test/auth"
+	"amartha-test/config"
+	"amartha-test/constant"
+	"amartha-test/helper"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/pb"
+	"amartha-test/service"
+	"amartha-test/storage"
+)
+
+const (
+	loanPublicID        = "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24"
+	unknownLoanPublicID = "b8e1f4a2-6c3d-4f9e-8a1b-7d2c5e9f0a13"
+)
+
+var testAuthenticator = auth.NewAuthenticator([]byte("grpcapi-test-secret-of-32-bytes!"), time.Hour)
+
+// withBearer returns the context calling as the user, with its bearer token in the authorization metadata
+func withBearer(ctx context.Context, userID int64) context.Context {
+	token, _ := testAuthenticator.Issue(userID, time.Now())
+	return metadata.AppendToOutgoingContext(ctx, "authorization", constant.BearerPrefix+token)
+}
+
+// newTestConn serves the service on an in-process listener and returns a client connection to it
+func newTestConn(t *testing.T, svc service.IService) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(svc, testAuthenticator)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// newPictureProof returns a base64 encoded png picture big enough to prove a visit
+func newPictureProof(t *testing.T) string {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 400, 300)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestLoanFlow(t *testing.T) {
+	h := helper.NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+	h.InitUsers(context.Background())
+	svc := service.NewService(h)
+	svc.Visits = service.VisitPolicy{MaxDistanceMeters: 500, OutOfRange: constant.VisitOutOfRangeFlag}
+	conn := newTestConn(t, svc)
+	ctx := context.Background()
+
+	users := pb.NewUserServiceClient(conn)
+	loans := pb.NewLoanServiceClient(conn)
+	agreements := pb.NewAgreementServiceClient(conn)
+
+	// 1. users are served
+	lenders, err := users.ListUsers(ctx, &pb.ListUsersRequest{UserType: constant.UserTypeLender})
+	assert.NoError(t, err)
+	assert.Len(t, lenders.GetUsers(), 2)
+
+	borrower, err := users.GetUser(ctx, &pb.GetUserRequest{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "Septian", borrower.GetUserName())
+	assert.NotNil(t, borrower.GetLocation())
+
+	// 2. submit, approve and invest by two lenders
+	loan, err := loans.SubmitLoan(ctx, &pb.SubmitLoanRequest{BorrowerId: 1, PrincipalAmount: 1000000, InterestRate: 0.1})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(constant.LoanStatusProposed), loan.GetStatus())
+
+	loan, err = loans.ApproveLoan(ctx, &pb.ApproveLoanRequest{LoanId: loan.GetLoanId(), PictureProof: newPictureProof(t), FieldValidatorEmployeeId: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(constant.LoanStatusApproved), loan.GetStatus())
+	assert.NotNil(t, loan.GetApprovalInfo().GetApprovalDate())
+	assert.Equal(t, "image/png", loan.GetApprovalInfo().GetProof().GetContentType())
+
+	loan, err = loans.InvestLoan(ctx, &pb.InvestLoanRequest{LoanId: loan.GetLoanId(), LenderId: 2, InvestedAmount: 600000})
+	assert.NoError(t, err)
+	loan, err = loans.InvestLoan(ctx, &pb.InvestLoanRequest{LoanId: loan.GetLoanId(), LenderId: 3, InvestedAmount: 400000})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(constant.LoanStatusInvested), loan.GetStatus())
+	assert.Len(t, loan.GetLending(), 2)
+
+	// 3. every lender signs, then the borrower signs, each by its own bearer token
+	lenderAgreements, err := agreements.ListAgreements(ctx, &pb.ListAgreementsRequest{LoanId: loan.GetLoanId(), AgreementType: constant.AgreementTypeOrganizerLender})
+	assert.NoError(t, err)
+	assert.Len(t, lenderAgreements.GetAgreements(), 2)
+	for _, v := range lenderAgreements.GetAgreements() {
+		_, err = agreements.SignAgreement(withBearer(ctx, 1), &pb.SignAgreementRequest{PublicId: v.GetAgreementId(), LoanId: loan.GetLoanId()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), "only the lender of the agreement signs it")
+
+		loan, err = agreements.SignAgreement(withBearer(ctx, v.GetUserId()), &pb.SignAgreementRequest{PublicId: v.GetAgreementId(), LoanId: loan.GetLoanId()})
+		assert.NoError(t, err)
+	}
+
+	borrowerAgreements, err := agreements.ListAgreements(ctx, &pb.ListAgreementsRequest{LoanId: loan.GetLoanId(), AgreementType: constant.AgreementTypeOrganizerBorrower})
+	assert.NoError(t, err)
+	if !assert.Len(t, borrowerAgreements.GetAgreements(), 1) {
+		return
+	}
+	borrowerAgreement := borrowerAgreements.GetAgreements()[0]
+	assert.NotEmpty(t, borrowerAgreement.GetAgreementId())
+	assert.Contains(t, loan.GetOrganizerBorrowerAggrementUrl(), borrowerAgreement.GetAgreementId())
+
+	_, err = agreements.GetAgreementDocument(ctx, &pb.GetAgreementDocumentRequest{PublicId: borrowerAgreement.GetAgreementId()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "the document is not served to anonymous calls")
+
+	document, err := agreements.GetAgreementDocument(withBearer(ctx, 1), &pb.GetAgreementDocumentRequest{PublicId: borrowerAgreement.GetAgreementId()})
+	assert.NoError(t, err)
+	assert.Equal(t, loan.GetLoanId(), document.GetAgreement().GetLoanId())
+	assert.Equal(t, borrowerAgreement.GetDocumentKey(), document.GetAgreement().GetDocumentKey())
+	assert.Equal(t, "application/pdf", document.GetContentType())
+	assert.Contains(t, string(document.GetContent()), "%PDF")
+
+	loan, err = agreements.SignAgreement(withBearer(ctx, 1), &pb.SignAgreementRequest{PublicId: borrowerAgreement.GetAgreementId(), LoanId: loan.GetLoanId()})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(constant.LoanStatusSigned), loan.GetStatus())
+
+	// 4. disburse
+	visit := &pb.Visit{
+		Location:  &pb.GeoPoint{Latitude: borrower.GetLocation().GetLatitude() + 0.01, Longitude: borrower.GetLocation().GetLongitude()},
+		VisitedAt: timestamppb.New(time.Now()),
+	}
+	loan, err = loans.DisburseLoan(ctx, &pb.DisburseLoanRequest{LoanId: loan.GetLoanId(), FieldOfficerId: 5, DisbursementDate: timestamppb.New(time.Now()), Visit: visit})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(constant.LoanStatusDisbursed), loan.GetStatus())
+	assert.InDelta(t, 1112, loan.GetDisbursementInfo().GetVisit().GetDistanceMeters(), 1)
+	assert.True(t, loan.GetDisbursementInfo().GetVisit().GetIsOutOfRange())
+	signedCopies, err := agreements.ListAgreements(ctx, &pb.ListAgreementsRequest{LoanId: loan.GetLoanId(), AgreementType: constant.AgreementTypeSignedCopy})
+	assert.NoError(t, err)
+	assert.Len(t, signedCopies.GetAgreements(), 3)
+	for _, v := range signedCopies.GetAgreements() {
+		assert.NotEmpty(t, v.GetSupersedesId())
+	}
+
+	detail, err := loans.GetLoan(ctx, &pb.GetLoanRequest{LoanId: loan.GetLoanId()})
+	assert.NoError(t, err)
+	assert.Equal(t, "disbursed", detail.GetStatusDesc())
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		call           func(conn *grpc.ClientConn) error
+		mocks          func(mockHelper *mocks.IHelper)
+		expectedCode   codes.Code
+		expectedReason string
+	}{
+		{
+			name: "invalid request",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewUserServiceClient(conn).GetUser(context.Background(), &pb.GetUserRequest{})
+				return err
+			},
+			mocks:          func(mockHelper *mocks.IHelper) {},
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: "invalid_request",
+		},
+		{
+			name: "invalid bearer token",
+			call: func(conn *grpc.ClientConn) error {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer v1.1.1.forged")
+				_, err := pb.NewUserServiceClient(conn).GetUser(ctx, &pb.GetUserRequest{UserId: 1})
+				return err
+			},
+			mocks:          func(mockHelper *mocks.IHelper) {},
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: "unauthenticated",
+		},
+		{
+			name: "anonymous signer",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewAgreementServiceClient(conn).SignAgreement(context.Background(), &pb.SignAgreementRequest{PublicId: "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c", LoanId: loanPublicID})
+				return err
+			},
+			mocks:          func(mockHelper *mocks.IHelper) {},
+			expectedCode:   codes.Unauthenticated,
+			expectedReason: "unauthenticated",
+		},
+		{
+			name: "picture proof is not base64",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewLoanServiceClient(conn).ApproveLoan(context.Background(), &pb.ApproveLoanRequest{LoanId: loanPublicID, PictureProof: "not base64!", FieldValidatorEmployeeId: 4})
+				return err
+			},
+			mocks:          func(mockHelper *mocks.IHelper) {},
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: "invalid_request",
+		},
+		{
+			name: "not found",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewLoanServiceClient(conn).GetLoan(context.Background(), &pb.GetLoanRequest{LoanId: unknownLoanPublicID})
+				return err
+			},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, unknownLoanPublicID).Return(model.Loan{})
+			},
+			expectedCode:   codes.NotFound,
+			expectedReason: "loan_not_found",
+		},
+		{
+			name: "user type not allowed",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewLoanServiceClient(conn).SubmitLoan(context.Background(), &pb.SubmitLoanRequest{BorrowerId: 2, PrincipalAmount: 1000, InterestRate: 0.1})
+				return err
+			},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(model.User{UserID: 2, UserType: constant.UserTypeLender})
+			},
+			expectedCode:   codes.PermissionDenied,
+			expectedReason: "user_type_not_allowed",
+		},
+		{
+			name: "invalid loan status",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewLoanServiceClient(conn).InvestLoan(context.Background(), &pb.InvestLoanRequest{LoanId: loanPublicID, LenderId: 2, InvestedAmount: 1000})
+				return err
+			},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed})
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedReason: "invalid_loan_status",
+		},
+		{
+			name: "agreement generation failed",
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewLoanServiceClient(conn).InvestLoan(context.Background(), &pb.InvestLoanRequest{LoanId: loanPublicID, LenderId: 2, InvestedAmount: 1000})
+				return err
+			},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, PrincipalAmount: 1000, Status: constant.LoanStatusApproved})
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(model.User{UserID: 2, UserType: constant.UserTypeLender})
+				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything, mock.Anything).Return(assert.AnError)
+			},
+			expectedCode:   codes.Internal,
+			expectedReason: "agreement_generation_failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			conn := newTestConn(t, service.NewService(mockHelper))
+
+			// main func
+			err := tt.call(conn)
+
+			st, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, st.Code())
+			var reason string
+			for _, detail := range st.Details() {
+				if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+					assert.Equal(t, ErrorDomain, errorInfo.GetDomain())
+					reason = errorInfo.GetReason()
+				}
+			}
+			assert.Equal(t, tt.expectedReason, reason)
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/pb"
+	"amartha-test/service"
+)
+
+func TestTraceInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
+	loans := pb.NewLoanServiceClient(newTestConn(t, service.NewService(mockHelper)))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// main func
+	_, err := loans.GetLoan(ctx, &pb.GetLoanRequest{LoanId: loanPublicID})
+
+	assert.Error(t, err)
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+	assert.Equal(t, pb.LoanService_GetLoan_FullMethodName, spans[0].Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, otelcodes.Error, spans[0].Status().Code)
+	mockHelper.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+
+	"amartha-test/model"
+	"amartha-test/pb"
+	"amartha-test/service"
+)
+
+// UserServer is grpc server of the user service
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+	Service service.IService
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	users := s.Service.ListUsers(ctx, model.UserFilter{
+		UserType: int(req.GetUserType()),
+	})
+
+	resp := &pb.ListUsersResponse{}
+	for _, v := range users {
+		resp.Users = append(resp.Users, toUser(v))
+	}
+
+	return resp, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	user, err := s.Service.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toUser(user), nil
+}
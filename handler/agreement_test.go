@@ -14,55 +14,117 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"amartha-test/apperror"
+	"amartha-test/auth"
 	"amartha-test/constant"
 	"amartha-test/helper/mocks"
 	"amartha-test/model"
+	"amartha-test/service"
+	"amartha-test/storage"
 )
 
+type nopReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadSeekCloser) Close() error {
+	return nil
+}
+
 func TestListAgreement(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
 		name         string
+		query        string
 		isError      bool
 		expectedCode int
+		expectedBody string
 		mocks        func()
 	}{
 		{
-			name:         "error - list empty",
+			name:         "error - invalid limit",
+			query:        "?limit=1000",
 			isError:      true,
-			expectedCode: http.StatusNotFound,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid created to",
+			query:        "?created_to=tomorrow",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid user id",
+			query:        "?user_id=?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid type",
+			query:        "?type=unknown",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "success - list empty",
+			isError:      false,
+			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetAgreements").Return([]model.Aggrement{}).Once()
+				mockHelper.On("GetAgreementsByFilter", mock.Anything, mock.Anything).Return([]model.Aggrement{}).Once()
 			},
 		},
 		{
-			name:         "success",
+			name:         "success - with filter",
+			query:        "?loan_id=" + loanPublicID + "&user_id=2&type=organizer-lender",
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetAgreements").Return([]model.Aggrement{
+				mockHelper.On("GetAgreementsByFilter", mock.Anything, model.AgreementFilter{
+					LoanPublicID:  loanPublicID,
+					UserID:        2,
+					AgreementType: constant.AgreementTypeOrganizerLender,
+				}).Return([]model.Aggrement{
 					{
 						AggrementID: 1,
 					},
 				}).Once()
 			},
 		},
+		{
+			name:         "success",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			expectedBody: `"agreement_id":"0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c"`,
+			mocks: func() {
+				mockHelper.On("GetAgreementsByFilter", mock.Anything, mock.Anything).Return([]model.Aggrement{
+					{
+						AggrementID:  1,
+						PublicID:     "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c",
+						LoanID:       1,
+						LoanPublicID: loanPublicID,
+					},
+				}).Once()
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mocks()
 
-			r, err := http.NewRequest("GET", "/agreement/list", nil)
+			r, err := http.NewRequest("GET", "/agreement/list"+tt.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -77,6 +139,15 @@ func TestListAgreement(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				// unsigned agreements have no signed_at
+				assert.NotContains(t, w.Body.String(), "signed_at")
+				// the agreement is served by its public id, the sequential ids are not served
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+				assert.NotContains(t, w.Body.String(), "aggrement_id")
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -85,48 +156,105 @@ func TestListAgreement(t *testing.T) {
 func TestViewAgreement(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
+	publicID := "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c"
+	lender := model.User{UserID: 2, UserType: constant.UserTypeLender}
 
 	tests := []struct {
 		name         string
 		vars         string
+		userID       int64
 		isError      bool
 		expectedCode int
 		mocks        func()
 	}{
 		{
-			name:         "error - convert string to int64",
-			vars:         "?",
+			name:         "error - anonymous user",
+			vars:         publicID,
+			isError:      true,
+			expectedCode: http.StatusUnauthorized,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - sequential agreement id",
+			vars:         "1",
+			userID:       2,
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
 			name:         "error - sanitize payload",
-			vars:         "0",
+			vars:         "",
+			userID:       2,
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
 			name:         "error - agreement data not found",
-			vars:         "1",
+			vars:         publicID,
+			userID:       2,
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, publicID).Return(model.Aggrement{}).Once()
+			},
+		},
+		{
+			name:         "error - agreement of other user",
+			vars:         publicID,
+			userID:       2,
+			isError:      true,
+			expectedCode: http.StatusForbidden,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, publicID).Return(model.Aggrement{AggrementID: 1, UserID: 3}).Once()
+			},
+		},
+		{
+			name:         "error - agreement document not found",
+			vars:         publicID,
+			userID:       2,
+			isError:      true,
+			expectedCode: http.StatusNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, publicID).Return(model.Aggrement{AggrementID: 1, UserID: 2}).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, mock.Anything).Return(storage.Document{}, apperror.DocumentNotFound.New()).Once()
+			},
+		},
+		{
+			name:         "error - fail open agreement document",
+			vars:         publicID,
+			userID:       2,
+			isError:      true,
+			expectedCode: http.StatusInternalServerError,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, publicID).Return(model.Aggrement{AggrementID: 1, UserID: 2}).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, mock.Anything).Return(storage.Document{}, errors.New("fail")).Once()
 			},
 		},
 		{
 			name:         "success",
-			vars:         "1",
+			vars:         publicID,
+			userID:       2,
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, publicID).Return(model.Aggrement{
 					AggrementID: 1,
+					UserID:      2,
 				}).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, mock.Anything).Return(storage.Document{
+					Key:     storage.DocumentKey([]byte("%PDF-1.3")),
+					Size:    8,
+					Content: nopReadSeekCloser{bytes.NewReader([]byte("%PDF-1.3"))},
+				}, nil).Once()
 			},
 		},
 	}
@@ -135,15 +263,17 @@ func TestViewAgreement(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mocks()
 
-			r, err := http.NewRequest("GET", "agreement/1/view", nil)
+			r, err := http.NewRequest("GET", "agreement/"+tt.vars+"/view", nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			vars := map[string]string{"agreement_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			if tt.userID != 0 {
+				ctx = auth.WithUserID(ctx, tt.userID)
+			}
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
 
@@ -157,6 +287,9 @@ func TestViewAgreement(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -165,28 +298,22 @@ func TestViewAgreement(t *testing.T) {
 func TestSignAgreement(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
+	agreementPublicID := "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c"
 
 	tests := []struct {
 		name         string
 		vars         string
+		userID       int64
 		requestBody  interface{}
 		isError      bool
 		expectedCode int
 		mocks        func()
 	}{
-		{
-			name:         "error - get agreement id from vars",
-			vars:         "?",
-			requestBody:  "invalid body",
-			isError:      true,
-			expectedCode: http.StatusBadRequest,
-			mocks:        func() {},
-		},
 		{
 			name:         "error - decode body",
-			vars:         "1",
+			vars:         agreementPublicID,
 			requestBody:  "invalid body",
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
@@ -194,177 +321,192 @@ func TestSignAgreement(t *testing.T) {
 		},
 		{
 			name:         "error - loan id is empty",
-			vars:         "1",
+			vars:         agreementPublicID,
+			userID:       2,
 			requestBody:  model.Sign{},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
-			name: "error - user id is empty",
-			vars: "1",
+			name: "error - anonymous signer",
+			vars: agreementPublicID,
 			requestBody: model.Sign{
-				LoanID: 1,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
-			expectedCode: http.StatusBadRequest,
+			expectedCode: http.StatusUnauthorized,
 			mocks:        func() {},
 		},
 		{
-			name: "error - loan data not found",
-			vars: "1",
+			name:   "error - loan data not found",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
 			},
 		},
 		{
-			name: "error - loan status is not invested",
-			vars: "1",
+			name:   "error - loan status is not invested",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
 			},
 		},
 		{
-			name: "error - user data is not found",
-			vars: "1",
+			name:   "error - user data is not found",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
-			expectedCode: http.StatusNotFound,
+			expectedCode: http.StatusUnauthorized,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
-			name: "error - agreement data is not found",
-			vars: "1",
+			name:   "error - agreement data is not found",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 1}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 1}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{}).Once()
 			},
 		},
 		{
-			name: "error - forbidden user to sign",
-			vars: "1",
+			name:   "error - forbidden user to sign",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusForbidden,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 1}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, UserID: 1}).Once()
 			},
 		},
 		{
-			name: "error - agreement is already signed",
-			vars: "1",
+			name:   "error - agreement does not belong to loan",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 2, IsSigned: true}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 2, UserID: 2}).Once()
 			},
 		},
 		{
-			name: "error - fail generate signed agreement",
-			vars: "1",
+			name:   "error - agreement is already signed",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
+			},
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 1, UserID: 2, IsSigned: true}).Once()
+			},
+		},
+		{
+			name:   "error - fail generate signed agreement",
+			vars:   agreementPublicID,
+			userID: 2,
+			requestBody: model.Sign{
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusInternalServerError,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 2, IsSigned: false}).Once()
-				mockHelper.On("UpsertAgreement", mock.Anything).Return().Once()
-				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything).Return(errors.New("fail")).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 1, UserID: 2, IsSigned: false}).Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("fail")).Once()
 			},
 		},
 		{
-			name: "error - lender - fail check all lender signed",
-			vars: "1",
+			name:   "error - lender - fail check all lender signed",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusInternalServerError,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 2, IsSigned: false}).Once()
-				mockHelper.On("UpsertAgreement", mock.Anything).Return().Once()
-				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
-				mockHelper.On("CheckAgreementCompletelySignedByLender", mock.Anything).Return(false, errors.New("fail")).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 1, UserID: 2, IsSigned: false}).Once()
+				mockHelper.On("UpsertAgreement", mock.Anything, mock.Anything).Return().Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("CheckAgreementCompletelySignedByLender", mock.Anything, mock.Anything).Return(false, errors.New("fail")).Once()
 			},
 		},
 		{
-			name: "error - lender - generate borrower agreement",
-			vars: "1",
+			name:   "error - lender - generate borrower agreement",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      true,
 			expectedCode: http.StatusInternalServerError,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 2, IsSigned: false}).Once()
-				mockHelper.On("UpsertAgreement", mock.Anything).Return().Once()
-				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
-				mockHelper.On("CheckAgreementCompletelySignedByLender", mock.Anything).Return(true, nil).Once()
-				mockHelper.On("GenerateBorrowerAgreementPDF", mock.Anything).Return(errors.New("fail")).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 1, UserID: 2, IsSigned: false}).Once()
+				mockHelper.On("UpsertAgreement", mock.Anything, mock.Anything).Return().Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("CheckAgreementCompletelySignedByLender", mock.Anything, mock.Anything).Return(true, nil).Once()
+				mockHelper.On("GenerateBorrowerAgreementPDF", mock.Anything, mock.Anything).Return(errors.New("fail")).Once()
 			},
 		},
 		{
-			name: "success - borrower",
-			vars: "1",
+			name:   "success - borrower",
+			vars:   agreementPublicID,
+			userID: 2,
 			requestBody: model.Sign{
-				LoanID: 1,
-				UserID: 2,
+				LoanID: loanPublicID,
 			},
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeBorrower}).Once()
-				mockHelper.On("GetAgreementByAgreementID", mock.Anything).Return(model.Aggrement{AggrementID: 1, UserID: 2, IsSigned: false}).Once()
-				mockHelper.On("UpsertAgreement", mock.Anything).Return().Once()
-				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
-				mockHelper.On("UpsertLoan", mock.Anything).Return().Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementPublicID).Return(model.Aggrement{AggrementID: 1, LoanID: 1, UserID: 2, IsSigned: false}).Once()
+				mockHelper.On("UpsertAgreement", mock.Anything, mock.Anything).Return().Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
 			},
 		},
 	}
@@ -378,15 +520,17 @@ func TestSignAgreement(t *testing.T) {
 				t.Fatalf("could not encode request body: %v", err)
 			}
 
-			r, err := http.NewRequest("POST", "agreement/1/sign", &requestBody)
+			r, err := http.NewRequest("POST", "agreement/"+agreementPublicID+"/sign", &requestBody)
 			if err != nil {
 				t.Fatal(err)
 			}
 			vars := map[string]string{"agreement_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			if tt.userID != 0 {
+				ctx = auth.WithUserID(ctx, tt.userID)
+			}
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
 
@@ -400,6 +544,9 @@ func TestSignAgreement(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"amartha-test/apperror"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+var auditEntrySortKeys = sortKeys[model.AuditEntry]{
+	"sequence":   func(entry model.AuditEntry) float64 { return float64(entry.Sequence) },
+	"created_at": func(entry model.AuditEntry) float64 { return float64(entry.CreatedAt.UnixMicro()) },
+}
+
+// ListAudit is handler to get list of audit log entries, filterable by actor, action, target_type, target_id
+// (the public id of loans and agreements), request_id, created_from and created_to
+func (h *Handler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	// 1. get query params
+	query := r.URL.Query()
+	page, err := parsePageRequest(query, auditEntrySortKeys, "sequence")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListAudit", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	filter := model.AuditFilter{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		RequestID:  query.Get("request_id"),
+	}
+	filter.CreatedFrom, filter.CreatedTo, err = parseCreatedRangeQuery(query)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid filter", "op", "ListAudit", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	// 2. get audit entry list
+	entries := h.Service.ListAuditEntries(r.Context(), filter)
+
+	// 3. paginate audit entry list
+	result, meta, err := paginate(entries, page, auditEntrySortKeys, func(entry model.AuditEntry) int64 { return entry.Sequence })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListAudit", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 4. render response
+	h.RenderListResponse(w, r, result, meta)
+}
+
+// VerifyAudit is handler to verify the hash chain of the audit log
+func (h *Handler) VerifyAudit(w http.ResponseWriter, r *http.Request) {
+	// 1. verify audit log
+	verification := h.Service.VerifyAuditLog(r.Context())
+
+	// 2. render response
+	h.RenderResponse(w, r, verification, http.StatusOK)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/service"
+)
+
+func TestListAudit(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - invalid sort",
+			query:        "?sort=actor",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid created from",
+			query:        "?created_from=yesterday",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "success - with filter",
+			query:        "?actor=user:4&action=loan.updated&target_type=loan&target_id=" + loanPublicID + "&request_id=req-1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetAuditEntriesByFilter", mock.Anything, model.AuditFilter{
+					Actor:      "user:4",
+					Action:     "loan.updated",
+					TargetType: "loan",
+					TargetID:   loanPublicID,
+					RequestID:  "req-1",
+				}).Return([]model.AuditEntry{{Sequence: 2, Actor: "user:4", Action: "loan.updated"}}).Once()
+			},
+		},
+		{
+			name:         "success",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetAuditEntriesByFilter", mock.Anything, mock.Anything).Return([]model.AuditEntry{{Sequence: 1}, {Sequence: 2}}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("GET", "/audit/list"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.ListAudit(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerifyAudit(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+	mockHelper.On("GetAuditEntries", mock.Anything).Return([]model.AuditEntry{{Sequence: 1, Hash: "tampered"}}).Once()
+
+	r, err := http.NewRequest("GET", "/audit/verify", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now())
+	r = r.WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	// main func
+	mockHandler.VerifyAudit(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"is_valid":false,"entry_count":1,"broken_sequence":1`)
+	mockHelper.AssertExpectations(t)
+}
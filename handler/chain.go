@@ -0,0 +1,574 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/ratelimit"
+	"amartha-test/tracing"
+)
+
+const maxRequestIDLength = 128
+
+// Chain wraps next by the middlewares, the first middleware is the outermost
+func Chain(next http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+
+	return next
+}
+
+// Trace is middleware handler to serve the request in a span named by its route, continuing the trace of the W3C
+// traceparent header when present. The helpers and the PDF renderer add their spans under it
+func (h *Handler) Trace(router *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routeTemplate(router, r)
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := otel.Tracer(tracing.ServiceName).Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(r.URL.Path),
+				))
+			defer span.End()
+
+			recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.statusCode))
+			if recorder.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(recorder.statusCode))
+			}
+		})
+	}
+}
+
+// MeasureLatency is middleware handler to initialize start time, the latency of the response is measured from it
+func (h *Handler) MeasureLatency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// MeasureRequests is middleware handler to count the requests and observe their duration per route and status.
+// The route is the path template matched by router, keeping the metric labels bounded
+func (h *Handler) MeasureRequests(router *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if h.Metrics == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			h.Metrics.ObserveRequest(r.Method, routeTemplate(router, r), recorder.statusCode, time.Since(startTime))
+		})
+	}
+}
+
+// RequestID is middleware handler to identify the request by its X-Request-ID header, generated when missing or invalid.
+// The request id is echoed in the response, and recorded in the access log and the audit log
+func (h *Handler) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(constant.HeaderRequestID)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(constant.HeaderRequestID, requestID)
+		ctx := audit.WithRequestID(r.Context(), requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Authenticate is middleware handler to identify the user acting on the request by the bearer token of its
+// Authorization header. A request without token is anonymous, an invalid or expired token answers unauthenticated (401)
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	if h.Authenticator == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(constant.HeaderAuthorization)
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, constant.BearerPrefix)
+		if !ok {
+			h.RenderError(w, r, apperror.Unauthenticated.New().WithDetail("reason", "authorization must be a bearer token"))
+			return
+		}
+		userID, err := h.Authenticator.Verify(token, time.Now())
+		if err != nil {
+			logging.FromContext(r.Context()).Info("rejected bearer token", "op", "Authenticate", "error", err)
+			h.RenderError(w, r, apperror.Unauthenticated.Wrap(err).WithDetail("reason", err.Error()))
+			return
+		}
+
+		ctx := auth.WithUserID(r.Context(), userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Logger is middleware handler to carry logger in the request context, with the request id, actor and trace id of
+// the request as attributes of every log. The request id and the authenticated user have to be initialized before
+func (h *Handler) Logger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestLogger := logger.With("request_id", audit.RequestIDFrom(r.Context()), "actor", h.requestActor(r))
+			if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.HasTraceID() {
+				requestLogger = requestLogger.With("trace_id", spanContext.TraceID().String())
+			}
+
+			ctx := logging.WithLogger(r.Context(), requestLogger)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Actor is middleware handler to initialize the actor of the audited writes
+func (h *Handler) Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithActor(r.Context(), h.requestActor(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AccessLog is middleware handler to log every request with its status, size and duration
+func (h *Handler) AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		logging.FromContext(r.Context()).Info("access", "method", r.Method, "uri", r.URL.RequestURI(), "status", recorder.statusCode,
+			"size_bytes", recorder.size, "duration_ms", time.Since(startTime).Milliseconds())
+	})
+}
+
+// Recover is middleware handler to answer internal error on panic instead of dropping the connection
+func (h *Handler) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			logging.FromContext(r.Context()).Error("panic serving request", "op", "Recover", "method", r.Method, "path", r.URL.Path,
+				"panic", fmt.Sprint(v), "stack", string(debug.Stack()))
+			if !recorder.wroteHeader {
+				h.RenderError(recorder, r, apperror.Internal.New())
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// BodyLimit is middleware handler to reject request body bigger than maxBytes
+func (h *Handler) BodyLimit(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 1. reject declared length early, bodies of unknown length are cut while read
+			if r.ContentLength > maxBytes {
+				logging.FromContext(r.Context()).Info("content length is too large", "op", "BodyLimit", "content_length", r.ContentLength, "max_bytes", maxBytes)
+				h.RenderError(w, r, apperror.RequestTooLarge.New().WithDetail("max_bytes", maxBytes))
+				return
+			}
+
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimit is middleware handler to limit the requests of every client address and user per route with the policy of the
+// route, answering rate_limited (429) with Retry-After. A user rotating its addresses is still limited by its user, and
+// anonymous requests by their address only
+func (h *Handler) RateLimit(router *mux.Router, policies ratelimit.Policies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if h.RateLimitStore == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 1. get policy of the route
+			route := routeTemplate(router, r)
+			policy := policies.For(r.Method, route)
+			if policy.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// 2. take a token of the address and of the user, the longest wait wins
+			actors := []string{"addr:" + h.clientAddr(r)}
+			if userID, ok := auth.UserIDFrom(r.Context()); ok {
+				actors = append(actors, audit.UserActor(userID))
+			}
+
+			limited := false
+			var retryAfter time.Duration
+			for _, actor := range actors {
+				decision, err := h.RateLimitStore.Take(r.Context(), r.Method+" "+route+"|"+actor, policy)
+				if err != nil {
+					// fail open, an unreachable shared store must not take the api down with it
+					logging.FromContext(r.Context()).Error("failed take rate limit token", "op", "RateLimit", "route", route, "error", err)
+					continue
+				}
+				if !decision.Allowed {
+					limited = true
+					retryAfter = max(retryAfter, decision.RetryAfter)
+				}
+			}
+
+			// 3. reject limited request
+			if limited {
+				retryAfterSeconds := max(int64(math.Ceil(retryAfter.Seconds())), 1)
+				logging.FromContext(r.Context()).Info("rate limited", "op", "RateLimit", "route", route, "retry_after_seconds", retryAfterSeconds)
+				w.Header().Set("Retry-After", strconv.FormatInt(retryAfterSeconds, 10))
+				h.RenderError(w, r, apperror.RateLimited.New().WithDetail("retry_after_seconds", retryAfterSeconds))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout is middleware handler to answer request timeout and cancel the request context when the request has not
+// started its response within timeout. A started response is written through as it is served, so documents are
+// streamed from the document store and the loan events keep running, and it is not cut by the timeout
+func (h *Handler) Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			// 1. serve in the background, holding the headers until the response starts
+			tw := &timeoutWriter{w: w, header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					v := recover()
+					if v != nil {
+						if v != http.ErrAbortHandler {
+							v = fmt.Sprintf("%v\n%s", v, debug.Stack())
+						}
+						panicked <- v
+						return
+					}
+					close(done)
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+			}()
+
+			// wait returns once the handler returned, so no work is left running after the request
+			wait := func() {
+				select {
+				case v := <-panicked:
+					panic(v)
+				case <-done:
+				}
+			}
+
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
+			// 2. wait for the handler, or answer the timeout when the response has not started
+			select {
+			case v := <-panicked:
+				panic(v)
+			case <-done:
+				tw.mutex.Lock()
+				defer tw.mutex.Unlock()
+				tw.commit(http.StatusOK)
+			case <-timer.C:
+				tw.mutex.Lock()
+				if tw.wroteHeader {
+					tw.mutex.Unlock()
+					wait()
+					return
+				}
+				tw.isTimedOut = true
+				tw.mutex.Unlock()
+
+				cancel()
+				logging.FromContext(r.Context()).Warn("request is not served within timeout", "op", "Timeout", "method", r.Method, "path", r.URL.Path, "timeout", timeout.String())
+				h.RenderError(w, r, apperror.RequestTimeout.New().WithDetail("timeout", timeout.String()))
+				wait()
+			}
+		})
+	}
+}
+
+// InjectLatency is middleware handler to delay every request by latency, only meant for tests and chaos experiments
+// checking clients against a slow server
+func (h *Handler) InjectLatency(latency time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timer := time.NewTimer(latency)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				next.ServeHTTP(w, r)
+			case <-r.Context().Done():
+			}
+		})
+	}
+}
+
+// requestActor returns the authenticated user acting on the request, falling back to the client address
+func (h *Handler) requestActor(r *http.Request) string {
+	if userID, ok := auth.UserIDFrom(r.Context()); ok {
+		return audit.UserActor(userID)
+	}
+
+	return "addr:" + h.clientAddr(r)
+}
+
+// clientAddr returns the host of the client address, without its port. Behind a trusted proxy it is the nearest
+// address of X-Forwarded-For or Forwarded which is not a trusted proxy, the addresses further left are client-set
+func (h *Handler) clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !h.isTrustedProxy(host) {
+		return host
+	}
+
+	hops := forwardedFor(r.Header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !h.isTrustedProxy(hops[i]) {
+			return hops[i]
+		}
+	}
+
+	return host
+}
+
+// isTrustedProxy tells whether the address is of a trusted proxy
+func (h *Handler) isTrustedProxy(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range h.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwardedFor returns the client addresses of the proxies chain from the farthest, read from X-Forwarded-For
+// or else from the for parameters of Forwarded (RFC 7239), without their ports
+func forwardedFor(header http.Header) []string {
+	var hops []string
+	for _, v := range header.Values(constant.HeaderForwardedFor) {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	if len(hops) > 0 {
+		return hops
+	}
+
+	for _, v := range header.Values(constant.HeaderForwarded) {
+		for _, element := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(key, "for") {
+					continue
+				}
+
+				// e.g. for=192.0.2.43, for="[2001:db8::17]:4711"
+				value = strings.Trim(value, `"`)
+				if host, _, err := net.SplitHostPort(value); err == nil {
+					value = host
+				}
+				hops = append(hops, strings.Trim(value, "[]"))
+			}
+		}
+	}
+
+	return hops
+}
+
+// routeTemplate returns the path template of the route matching the request, or unmatched
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return "unmatched"
+	}
+
+	template, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+
+	return template
+}
+
+// isValidRequestID reports whether the client chosen request id is short and printable
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "req_" + hex.EncodeToString(b)
+}
+
+// statusRecorder writes through to the response writer while keeping the status and size of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	size        int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	if !sr.wroteHeader {
+		sr.statusCode = statusCode
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	n, err := sr.ResponseWriter.Write(b)
+	sr.size += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the flusher and deadlines of the response writer
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// timeoutWriter holds the headers of the response until it starts, then writes through. The timeout is only
+// answered while the response has not started
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mutex       sync.Mutex
+	wroteHeader bool
+	isTimedOut  bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.isTimedOut {
+		return
+	}
+
+	tw.commit(statusCode)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.isTimedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	tw.commit(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+func (tw *timeoutWriter) FlushError() error {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.isTimedOut {
+		return http.ErrHandlerTimeout
+	}
+
+	tw.commit(http.StatusOK)
+	return http.NewResponseController(tw.w).Flush()
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.FlushError()
+}
+
+// Unwrap lets http.ResponseController reach the deadlines of the response writer
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// commit starts the response with the held headers once, the caller holds the mutex
+func (tw *timeoutWriter) commit(statusCode int) {
+	if tw.wroteHeader {
+		return
+	}
+
+	tw.wroteHeader = true
+	for k, v := range tw.header {
+		tw.w.Header()[k] = v
+	}
+	tw.w.WriteHeader(statusCode)
+}
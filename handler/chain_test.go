@@ -0,0 +1,594 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"amartha-test/audit"
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/metrics"
+	"amartha-test/model"
+	"amartha-test/ratelimit"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	middleware := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+
+	// main func
+	Chain(handlerFunc, middleware("first"), middleware("second")).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, []string{"first", "second", "handler"}, order)
+}
+
+func TestMeasureLatency(t *testing.T) {
+	mockHandler := &Handler{}
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		mockHandler.RenderResponse(w, r, "test data", http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+
+	// main func
+	mockHandler.MeasureLatency(handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	var response Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEqual(t, "0ms", response.Latency)
+}
+
+// testAuthenticator issues the bearer tokens of the handler tests
+var testAuthenticator = auth.NewAuthenticator([]byte("handler-test-secret-of-32-bytes!"), time.Hour)
+
+// bearer returns the Authorization header of the user
+func bearer(userID int64) string {
+	token, _ := testAuthenticator.Issue(userID, time.Now())
+	return constant.BearerPrefix + token
+}
+
+func TestRequestIDAndActor(t *testing.T) {
+	mockHandler := &Handler{Authenticator: testAuthenticator}
+
+	tests := []struct {
+		name              string
+		header            map[string]string
+		expectedCode      int
+		expectedActor     string
+		expectedRequestID string
+	}{
+		{
+			name:          "success - generated request id and client address actor",
+			expectedCode:  http.StatusOK,
+			expectedActor: "addr:192.0.2.1",
+		},
+		{
+			name:              "success - actor from bearer token and request id from header",
+			header:            map[string]string{constant.HeaderAuthorization: bearer(4), constant.HeaderRequestID: "req-1"},
+			expectedCode:      http.StatusOK,
+			expectedActor:     "user:4",
+			expectedRequestID: "req-1",
+		},
+		{
+			name:          "success - user header is not trusted",
+			header:        map[string]string{"X-User-ID": "4"},
+			expectedCode:  http.StatusOK,
+			expectedActor: "addr:192.0.2.1",
+		},
+		{
+			name:         "error - invalid bearer token",
+			header:       map[string]string{constant.HeaderAuthorization: bearer(4) + "x"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "error - not a bearer token",
+			header:       map[string]string{constant.HeaderAuthorization: "Basic dXNlcjpwYXNz"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "success - invalid request id replaced",
+			header:        map[string]string{constant.HeaderRequestID: "req 1\n"},
+			expectedCode:  http.StatusOK,
+			expectedActor: "addr:192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			var actor, requestID string
+			handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor = audit.ActorFrom(r.Context())
+				requestID = audit.RequestIDFrom(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// main func
+			Chain(handlerFunc, mockHandler.RequestID, mockHandler.Authenticate, mockHandler.Actor).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				assertRegisteredError(t, w)
+				assert.Contains(t, w.Body.String(), `"code":"unauthenticated"`)
+				return
+			}
+			assert.Equal(t, tt.expectedActor, actor)
+			if tt.expectedRequestID != "" {
+				assert.Equal(t, tt.expectedRequestID, requestID)
+			} else {
+				assert.Regexp(t, "^req_[0-9a-f]{32}$", requestID)
+			}
+			assert.Equal(t, requestID, w.Header().Get(constant.HeaderRequestID))
+		})
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	mockHandler := &Handler{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		header       map[string]string
+		expectedAddr string
+	}{
+		{
+			name:         "success - direct client",
+			remoteAddr:   "192.0.2.1:1234",
+			expectedAddr: "192.0.2.1",
+		},
+		{
+			name:         "success - forwarded header of untrusted client ignored",
+			remoteAddr:   "192.0.2.1:1234",
+			header:       map[string]string{constant.HeaderForwardedFor: "198.51.100.7"},
+			expectedAddr: "192.0.2.1",
+		},
+		{
+			name:         "success - client behind trusted proxies",
+			remoteAddr:   "10.0.0.1:1234",
+			header:       map[string]string{constant.HeaderForwardedFor: "203.0.113.9, 198.51.100.7, 10.0.0.2"},
+			expectedAddr: "198.51.100.7",
+		},
+		{
+			name:         "success - forwarded element of trusted proxy",
+			remoteAddr:   "10.0.0.1:1234",
+			header:       map[string]string{constant.HeaderForwarded: `for="[2001:db8::17]:4711";proto=https, for=10.0.0.2`},
+			expectedAddr: "2001:db8::17",
+		},
+		{
+			name:         "success - trusted proxy without forwarded header",
+			remoteAddr:   "10.0.0.1:1234",
+			expectedAddr: "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			// main func
+			addr := mockHandler.clientAddr(r)
+
+			assert.Equal(t, tt.expectedAddr, addr)
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	mockHandler := &Handler{}
+
+	tests := []struct {
+		name         string
+		handlerFunc  http.HandlerFunc
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "error - panic before response",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `"code":"internal_error"`,
+		},
+		{
+			name: "error - panic after response is kept",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("boom")
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "error - panic in timeout",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				mockHandler.Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					panic("boom")
+				})).ServeHTTP(w, r)
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `"code":"internal_error"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Recover(tt.handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	mockHandler := &Handler{}
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		if err != nil {
+			mockHandler.RenderError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name         string
+		body         io.Reader
+		expectedCode int
+	}{
+		{
+			name:         "error - content length too large",
+			body:         strings.NewReader(strings.Repeat("a", 11)),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "error - body of unknown length too large",
+			body:         io.MultiReader(strings.NewReader(strings.Repeat("a", 11))),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "success",
+			body:         strings.NewReader(strings.Repeat("a", 10)),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.BodyLimit(10)(handlerFunc).ServeHTTP(w, httptest.NewRequest("POST", "/test", tt.body))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				assertRegisteredError(t, w)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	mockHandler := &Handler{RateLimitStore: ratelimit.NewMemoryStore(), Authenticator: testAuthenticator}
+	router := mux.NewRouter()
+	router.HandleFunc("/v1/loan/submit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	chain := Chain(router, mockHandler.Authenticate, mockHandler.RateLimit(router, ratelimit.Policies{
+		ratelimit.DefaultRoute: {Limit: 1, Period: time.Hour, Burst: 1},
+		"GET /healthz":         {},
+	}))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		remoteAddr   string
+		userID       int64
+		header       map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "success - first request of the address",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			remoteAddr:   "10.0.0.1:1234",
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "error - address limited on another port",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			remoteAddr:   "10.0.0.1:5678",
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "error - address limited whichever user it claims",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			remoteAddr:   "10.0.0.1:1234",
+			userID:       7,
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "error - user limited from another address",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			remoteAddr:   "10.0.0.2:1234",
+			userID:       7,
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			name:         "success - user header is not limited as the user",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			remoteAddr:   "10.0.0.3:1234",
+			header:       map[string]string{"X-User-ID": "7"},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "success - other route has a bucket of its own",
+			method:       "GET",
+			path:         "/unknown",
+			remoteAddr:   "10.0.0.1:1234",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "success - unlimited route",
+			method:       "GET",
+			path:         "/healthz",
+			remoteAddr:   "10.0.0.1:1234",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.userID != 0 {
+				r.Header.Set(constant.HeaderAuthorization, bearer(tt.userID))
+			}
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			// main func
+			chain.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusTooManyRequests {
+				assertRegisteredError(t, w)
+				assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+				assert.Contains(t, w.Body.String(), `"code":"rate_limited"`)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	mockHandler := &Handler{}
+
+	tests := []struct {
+		name         string
+		handlerFunc  http.HandlerFunc
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "error - not served within timeout",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				w.WriteHeader(http.StatusOK)
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `"code":"request_timeout"`,
+		},
+		{
+			name: "success - served within timeout",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("created"))
+			},
+			expectedCode: http.StatusCreated,
+			expectedBody: "created",
+		},
+		{
+			name: "success - stream keeps running after timeout",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				events := newServerSentEventWriter(w)
+				events.Comment("first")
+				time.Sleep(100 * time.Millisecond)
+				assert.NoError(t, r.Context().Err())
+				events.Comment("second")
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: ": first\n\n: second\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Timeout(50*time.Millisecond)(tt.handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedBody != "", strings.Contains(w.Body.String(), tt.expectedBody))
+		})
+	}
+}
+
+func TestInjectLatency(t *testing.T) {
+	mockHandler := &Handler{}
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+
+	// main func
+	startTime := time.Now()
+	mockHandler.InjectLatency(30*time.Millisecond)(handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.GreaterOrEqual(t, time.Since(startTime), 30*time.Millisecond)
+}
+
+func TestMeasureRequests(t *testing.T) {
+	mockHandler := &Handler{Metrics: metrics.New(func() model.Stats { return model.Stats{} })}
+	router := mux.NewRouter()
+	router.HandleFunc("/v1/loan/{loan_id}/detail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+	chain := Chain(router, mockHandler.MeasureRequests(router))
+
+	// main func
+	chain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/loan/1/detail", nil))
+	chain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/v1/loan/2/detail", nil))
+	chain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+	w := httptest.NewRecorder()
+	mockHandler.Metrics.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `amartha_http_requests_total{method="GET",route="/v1/loan/{loan_id}/detail",status="404"} 2`)
+	assert.Contains(t, w.Body.String(), `amartha_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestTrace(t *testing.T) {
+	mockHandler := &Handler{}
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var handlerSpan trace.SpanContext
+	router := mux.NewRouter()
+	router.HandleFunc("/v1/loan/{loan_id}/invest", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods("POST")
+	r := httptest.NewRequest("POST", "/v1/loan/1/invest", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// main func
+	Chain(router, mockHandler.Trace(router)).ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+	assert.Equal(t, "POST /v1/loan/{loan_id}/invest", spans[0].Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), handlerSpan.SpanID())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestLogger(t *testing.T) {
+	mockHandler := &Handler{}
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, slog.LevelInfo, logging.FormatJSON)
+	assert.NoError(t, err)
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("loan data is not found", "picture_proof", "aW1hZ2U=")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set(constant.HeaderRequestID, "req-1")
+	r = r.WithContext(auth.WithUserID(r.Context(), 4))
+
+	// main func
+	Chain(handlerFunc, mockHandler.RequestID, mockHandler.Logger(logger), mockHandler.AccessLog).ServeHTTP(httptest.NewRecorder(), r)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	assert.Contains(t, lines[0], `"msg":"loan data is not found","request_id":"req-1","actor":"user:4","picture_proof":"[REDACTED]"`)
+	assert.Contains(t, lines[1], `"msg":"access","request_id":"req-1","actor":"user:4","method":"GET","uri":"/test","status":404`)
+}
+
+func TestTimeoutWritesThrough(t *testing.T) {
+	mockHandler := &Handler{}
+	w := httptest.NewRecorder()
+	handlerFunc := http.HandlerFunc(func(tw http.ResponseWriter, r *http.Request) {
+		tw.Header().Set("Content-Type", "application/pdf")
+		tw.Write([]byte("first"))
+		// the started response is not held until the handler returns
+		assert.Equal(t, "first", w.Body.String())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		time.Sleep(100 * time.Millisecond)
+		tw.Write([]byte(" second"))
+	})
+
+	// main func
+	mockHandler.Timeout(50*time.Millisecond)(handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "first second", w.Body.String())
+}
+
+func TestTimeoutWaitsForCancelledHandler(t *testing.T) {
+	mockHandler := &Handler{}
+	var isReturned atomic.Bool
+	handlerFunc := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(20 * time.Millisecond)
+		isReturned.Store(true)
+	})
+	w := httptest.NewRecorder()
+
+	// main func
+	mockHandler.Timeout(50*time.Millisecond)(handlerFunc).ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.True(t, isReturned.Load(), "the cancelled handler is not left running")
+}
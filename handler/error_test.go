@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+)
+
+// assertRegisteredError asserts the response is an error rendered from a registered code with its http status
+func assertRegisteredError(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var response Response
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if !assert.NoError(t, err) || !assert.NotNil(t, response.Error, "error response must carry an error code") {
+		return
+	}
+
+	definition, ok := apperror.Lookup(response.Error.Code)
+	assert.True(t, ok, "error code %q is not registered", response.Error.Code)
+	assert.Equal(t, definition.HTTPStatus, w.Code, "http status of error code %q", response.Error.Code)
+	assert.Equal(t, definition.HTTPStatus, response.Code)
+	assert.Equal(t, definition.Message, response.Error.Message)
+}
+
+func TestRenderError(t *testing.T) {
+	mockHandler := &Handler{}
+
+	tests := []struct {
+		name            string
+		err             error
+		expectedCode    int
+		expectedErrCode apperror.Code
+		expectedDetails map[string]interface{}
+	}{
+		{
+			name:            "coded error",
+			err:             apperror.LoanNotFound.New().WithDetail("loan_id", 3),
+			expectedCode:    http.StatusNotFound,
+			expectedErrCode: "loan_not_found",
+			expectedDetails: map[string]interface{}{"loan_id": float64(3)},
+		},
+		{
+			name:            "wrapped coded error",
+			err:             errors.Join(errors.New("context"), apperror.InvalidLoanStatus.New()),
+			expectedCode:    http.StatusBadRequest,
+			expectedErrCode: "invalid_loan_status",
+		},
+		{
+			name:            "error without code",
+			err:             errors.New("database is down"),
+			expectedCode:    http.StatusInternalServerError,
+			expectedErrCode: "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest("GET", "/test", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r = r.WithContext(context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now()))
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.RenderError(w, r, tt.err)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assertRegisteredError(t, w)
+
+			var response Response
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedErrCode, response.Error.Code)
+			assert.Equal(t, tt.expectedDetails, response.Error.Details)
+			assert.NotContains(t, w.Body.String(), "database is down", "cause must not be rendered")
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"amartha-test/apperror"
+	"amartha-test/logging"
+)
+
+// readyCheckTimeout bounds each readiness check, a hanging backend must not hang the probe
+const readyCheckTimeout = 2 * time.Second
+
+// ProbeStatus is the body of a passing liveness or readiness probe
+type ProbeStatus struct {
+	Status string `json:"status"`
+}
+
+// Drain makes /readyz fail from now on, so the load balancer stops routing requests before the server shuts down
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// Healthz is handler to answer the liveness probe, it passes as long as the server is serving requests
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.RenderResponse(w, r, ProbeStatus{Status: "ok"}, http.StatusOK)
+}
+
+// Readyz is handler to answer the readiness probe, it fails while draining or when the document store is unreachable
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	// 1. check draining
+	if h.draining.Load() {
+		h.RenderError(w, r, apperror.NotReady.New().WithDetail("reason", "draining"))
+		return
+	}
+
+	// 2. check document store
+	if h.DocumentStore != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+		defer cancel()
+
+		err := h.DocumentStore.Ping(ctx)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("failed ping document store", "op", "Readyz", "error", err)
+			h.RenderError(w, r, apperror.NotReady.Wrap(err).WithDetail("reason", "document_store"))
+			return
+		}
+	}
+
+	// 3. render response
+	h.RenderResponse(w, r, ProbeStatus{Status: "ready"}, http.StatusOK)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/storage"
+)
+
+// unreachableDocumentStore fails every ping, like a store whose volume is gone
+type unreachableDocumentStore struct {
+	*storage.MemoryDocumentStore
+}
+
+func (unreachableDocumentStore) Ping(ctx context.Context) error {
+	return errors.New("document dir is gone")
+}
+
+func TestHealthz(t *testing.T) {
+	mockHandler := &Handler{}
+	mockHandler.Drain()
+
+	r := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	// main func
+	mockHandler.Router().ServeHTTP(w, r)
+
+	// a draining server is still alive
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name         string
+		store        storage.IDocumentStore
+		draining     bool
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "success - without document store",
+			expectedCode: http.StatusOK,
+			expectedBody: `"status":"ready"`,
+		},
+		{
+			name:         "success - document store reachable",
+			store:        storage.NewMemoryDocumentStore(),
+			expectedCode: http.StatusOK,
+			expectedBody: `"status":"ready"`,
+		},
+		{
+			name:         "error - document store unreachable",
+			store:        unreachableDocumentStore{storage.NewMemoryDocumentStore()},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `"reason":"document_store"`,
+		},
+		{
+			name:         "error - draining",
+			store:        storage.NewMemoryDocumentStore(),
+			draining:     true,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `"reason":"draining"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandler := &Handler{DocumentStore: tt.store}
+			if tt.draining {
+				mockHandler.Drain()
+			}
+
+			r := httptest.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Router().ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedCode != http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"code":"not_ready"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/idempotency"
+	"amartha-test/logging"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// Idempotent is middleware handler to store the first response per idempotency key and user and replay it on repeated requests
+func (h *Handler) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. requests without key or store are not idempotent
+		key := r.Header.Get(constant.HeaderIdempotencyKey)
+		if key == "" || h.IdempotencyStore == nil {
+			next(w, r)
+			return
+		}
+
+		// 2. sanitize key
+		if len(key) > maxIdempotencyKeyLength {
+			logging.FromContext(r.Context()).Info("idempotency key is too long", "op", "Idempotent", "key_length", len(key), "max_key_length", maxIdempotencyKeyLength)
+			h.RenderError(w, r, apperror.InvalidIdempotencyKey.New().WithDetail("max_length", maxIdempotencyKeyLength))
+			return
+		}
+
+		// 3. hash request, keeping body readable for next handler
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logging.FromContext(r.Context()).Info("failed read body", "op", "Idempotent", "error", err)
+			h.RenderError(w, r, apperror.InvalidRequest.Wrap(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := hashRequest(r, body)
+
+		// 4. reserve key, replay or reject when already used
+		scope := idempotencyScope(r, key)
+		record, exists := h.IdempotencyStore.Begin(scope, requestHash)
+		if exists {
+			switch {
+			case record.RequestHash != requestHash:
+				logging.FromContext(r.Context()).Info("idempotency key is reused with a different request", "op", "Idempotent", "idempotency_key", key)
+				h.RenderError(w, r, apperror.IdempotencyKeyReused.New())
+			case !record.Completed:
+				logging.FromContext(r.Context()).Info("idempotency key is still in progress", "op", "Idempotent", "idempotency_key", key)
+				h.RenderError(w, r, apperror.IdempotencyRequestInProgress.New())
+			default:
+				replayResponse(w, record)
+			}
+			return
+		}
+
+		// 5. serve and store response, server errors and panics are released so the request can be retried
+		completed := false
+		defer func() {
+			if !completed {
+				h.IdempotencyStore.Release(scope)
+			}
+		}()
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next(recorder, r)
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		h.IdempotencyStore.Complete(scope, idempotency.Record{
+			StatusCode: recorder.statusCode,
+			Header:     w.Header().Clone(),
+			Body:       recorder.body.Bytes(),
+		})
+		completed = true
+	}
+}
+
+// idempotencyScope returns the store key of the idempotency key, scoped to the authenticated user. An anonymous key is
+// not scoped to the client address, which changes when a mobile client retries from another network, and the request
+// hash keeps it from replaying another request
+func idempotencyScope(r *http.Request, key string) string {
+	if userID, ok := auth.UserIDFrom(r.Context()); ok {
+		return audit.UserActor(userID) + "|" + key
+	}
+
+	return "anonymous|" + key
+}
+
+// hashRequest hashes the method, path and body, a key reused on another route or body is a conflict. A multipart
+// body is hashed by its fields and files, so a retry with a new random boundary is the same request
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		parts, err := readMultipartParts(body, params["boundary"])
+		if err == nil {
+			for _, v := range parts {
+				fmt.Fprintf(hash, "%s %d\n", v.name, len(v.content))
+				hash.Write(v.content)
+			}
+			return hex.EncodeToString(hash.Sum(nil))
+		}
+		// a malformed body is hashed as is, the handler rejects it
+	}
+
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type multipartPart struct {
+	name    string
+	content []byte
+}
+
+// readMultipartParts reads the form name and content of every part sorted by name, leaving out the boundary and the
+// part headers that clients may generate differently
+func readMultipartParts(body []byte, boundary string) ([]multipartPart, error) {
+	if boundary == "" {
+		return nil, errors.New("multipart boundary is empty")
+	}
+
+	var parts []multipartPart
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, multipartPart{name: part.FormName(), content: content})
+	}
+
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].name < parts[j].name
+	})
+	return parts, nil
+}
+
+func replayResponse(w http.ResponseWriter, record idempotency.Record) {
+	for k, v := range record.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(constant.HeaderIdempotentReplayed, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// responseRecorder writes through to the response writer while keeping a copy of the response
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
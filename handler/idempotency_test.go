@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/idempotency"
+)
+
+func TestIdempotent(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		body          string
+		prepare       func(store *idempotency.MemoryStore)
+		nextCode      int
+		expectedCode  int
+		expectedCalls int
+		replayed      bool
+	}{
+		{
+			name:          "success - without key",
+			body:          `{"borrower_id":1}`,
+			prepare:       func(store *idempotency.MemoryStore) {},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusCreated,
+			expectedCalls: 1,
+		},
+		{
+			name:          "error - key too long",
+			key:           strings.Repeat("k", maxIdempotencyKeyLength+1),
+			body:          `{"borrower_id":1}`,
+			prepare:       func(store *idempotency.MemoryStore) {},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusBadRequest,
+			expectedCalls: 0,
+		},
+		{
+			name:          "success - first request",
+			key:           "key-1",
+			body:          `{"borrower_id":1}`,
+			prepare:       func(store *idempotency.MemoryStore) {},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusCreated,
+			expectedCalls: 1,
+		},
+		{
+			name: "success - replay completed request",
+			key:  "key-1",
+			body: `{"borrower_id":1}`,
+			prepare: func(store *idempotency.MemoryStore) {
+				store.Begin("user:1|key-1", hashRequest(httptest.NewRequest("POST", "/loan/submit", nil), []byte(`{"borrower_id":1}`)))
+				store.Complete("user:1|key-1", idempotency.Record{StatusCode: http.StatusCreated, Body: []byte(`{"code":201}`)})
+			},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusCreated,
+			expectedCalls: 0,
+			replayed:      true,
+		},
+		{
+			name: "error - key reused with different body",
+			key:  "key-1",
+			body: `{"borrower_id":2}`,
+			prepare: func(store *idempotency.MemoryStore) {
+				store.Begin("user:1|key-1", hashRequest(httptest.NewRequest("POST", "/loan/submit", nil), []byte(`{"borrower_id":1}`)))
+				store.Complete("user:1|key-1", idempotency.Record{StatusCode: http.StatusCreated, Body: []byte(`{"code":201}`)})
+			},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedCalls: 0,
+		},
+		{
+			name: "error - request in progress",
+			key:  "key-1",
+			body: `{"borrower_id":1}`,
+			prepare: func(store *idempotency.MemoryStore) {
+				store.Begin("user:1|key-1", hashRequest(httptest.NewRequest("POST", "/loan/submit", nil), []byte(`{"borrower_id":1}`)))
+			},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusConflict,
+			expectedCalls: 0,
+		},
+		{
+			name: "success - same key of other actor",
+			key:  "key-1",
+			body: `{"borrower_id":1}`,
+			prepare: func(store *idempotency.MemoryStore) {
+				store.Begin("user:2|key-1", hashRequest(httptest.NewRequest("POST", "/loan/submit", nil), []byte(`{"borrower_id":1}`)))
+			},
+			nextCode:      http.StatusCreated,
+			expectedCode:  http.StatusCreated,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := idempotency.NewMemoryStore(idempotency.DefaultTTL)
+			tt.prepare(store)
+			mockHandler := &Handler{
+				IdempotencyStore: store,
+			}
+
+			calls := 0
+			next := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+				mockHandler.RenderResponse(w, r, nil, tt.nextCode)
+			}
+
+			r, err := http.NewRequest("POST", "/loan/submit", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.key != "" {
+				r.Header.Set(constant.HeaderIdempotencyKey, tt.key)
+			}
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now())
+			r = r.WithContext(auth.WithUserID(ctx, 1))
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Idempotent(next)(w, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.Equal(t, tt.replayed, w.Header().Get(constant.HeaderIdempotentReplayed) == "true")
+			if tt.expectedCode >= http.StatusBadRequest {
+				assertRegisteredError(t, w)
+			}
+		})
+	}
+}
+
+func TestIdempotentRetryAfterServerError(t *testing.T) {
+	mockHandler := &Handler{
+		IdempotencyStore: idempotency.NewMemoryStore(idempotency.DefaultTTL),
+	}
+
+	codes := []int{http.StatusInternalServerError, http.StatusOK, http.StatusInternalServerError}
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		mockHandler.RenderResponse(w, r, nil, codes[calls])
+		calls++
+	}
+
+	for _, expectedCode := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK} {
+		r := httptest.NewRequest("POST", "/loan/1/invest", strings.NewReader(`{"lender_id":2}`))
+		r.Header.Set(constant.HeaderIdempotencyKey, "key-1")
+		r = r.WithContext(context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now()))
+		w := httptest.NewRecorder()
+
+		mockHandler.Idempotent(next)(w, r)
+
+		assert.Equal(t, expectedCode, w.Code)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotentScope(t *testing.T) {
+	mockHandler := &Handler{
+		IdempotencyStore: idempotency.NewMemoryStore(idempotency.DefaultTTL),
+	}
+
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		mockHandler.RenderResponse(w, r, nil, http.StatusOK)
+	}
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		userID        int64
+		expectedCalls int
+	}{
+		{
+			name:          "success - first anonymous request",
+			remoteAddr:    "192.0.2.1:1234",
+			expectedCalls: 1,
+		},
+		{
+			name:          "success - anonymous retry from another address replayed",
+			remoteAddr:    "198.51.100.7:1234",
+			expectedCalls: 1,
+		},
+		{
+			name:          "success - same key of a user served",
+			remoteAddr:    "192.0.2.1:1234",
+			userID:        2,
+			expectedCalls: 2,
+		},
+		{
+			name:          "success - user retry from another address replayed",
+			remoteAddr:    "198.51.100.7:1234",
+			userID:        2,
+			expectedCalls: 2,
+		},
+		{
+			name:          "success - same key of another user served",
+			remoteAddr:    "198.51.100.7:1234",
+			userID:        3,
+			expectedCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/loan/1/invest", strings.NewReader(`{"lender_id":2}`))
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set(constant.HeaderIdempotencyKey, "key-1")
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now())
+			if tt.userID != 0 {
+				ctx = auth.WithUserID(ctx, tt.userID)
+			}
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Idempotent(next)(w, r.WithContext(ctx))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestIdempotentRetryAfterPanic(t *testing.T) {
+	mockHandler := &Handler{
+		IdempotencyStore: idempotency.NewMemoryStore(idempotency.DefaultTTL),
+	}
+
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("lost connection")
+		}
+		mockHandler.RenderResponse(w, r, nil, http.StatusOK)
+	}
+	handler := mockHandler.Recover(mockHandler.Idempotent(next))
+
+	for _, expectedCode := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK} {
+		r := httptest.NewRequest("POST", "/loan/1/invest", strings.NewReader(`{"lender_id":2}`))
+		r.Header.Set(constant.HeaderIdempotencyKey, "key-1")
+		r = r.WithContext(context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now()))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, expectedCode, w.Code)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotentMultipartRetryWithNewBoundary(t *testing.T) {
+	mockHandler := &Handler{
+		IdempotencyStore: idempotency.NewMemoryStore(idempotency.DefaultTTL),
+	}
+
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		mockHandler.RenderResponse(w, r, nil, http.StatusOK)
+	}
+
+	tests := []struct {
+		name         string
+		boundary     string
+		pictureProof string
+		expectedCode int
+		replayed     bool
+	}{
+		{
+			name:         "success - first request",
+			boundary:     "boundary-first",
+			pictureProof: "picture",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "success - retry with new boundary is replayed",
+			boundary:     "boundary-retry",
+			pictureProof: "picture",
+			expectedCode: http.StatusOK,
+			replayed:     true,
+		},
+		{
+			name:         "error - retry with other file",
+			boundary:     "boundary-other",
+			pictureProof: "other picture",
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			assert.NoError(t, writer.SetBoundary(tt.boundary))
+			assert.NoError(t, writer.WriteField("field_validator_employee_id", "4"))
+			part, err := writer.CreateFormFile("picture_proof", "proof.png")
+			assert.NoError(t, err)
+			part.Write([]byte(tt.pictureProof))
+			assert.NoError(t, writer.Close())
+
+			r := httptest.NewRequest("POST", "/loan/"+loanPublicID+"/approve", &body)
+			r.Header.Set("Content-Type", writer.FormDataContentType())
+			r.Header.Set(constant.HeaderIdempotencyKey, "key-1")
+			r = r.WithContext(context.WithValue(r.Context(), constant.CtxStartTimeKey, time.Now()))
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Idempotent(next)(w, r)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.replayed, w.Header().Get(constant.HeaderIdempotentReplayed) == "true")
+		})
+	}
+	assert.Equal(t, 1, calls)
+}
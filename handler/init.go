@@ -2,36 +2,78 @@ package handler
 
 import (
 	"net/http"
+	"net/netip"
+	"sync"
+	"sync/atomic"
 
-	"amartha-test/helper"
+	"amartha-test/auth"
+	"amartha-test/idempotency"
+	"amartha-test/metrics"
+	"amartha-test/ratelimit"
+	"amartha-test/service"
+	"amartha-test/storage"
 )
 
 type IHandler interface {
 	// handler user
 	ListUser(w http.ResponseWriter, r *http.Request)
 	DetailUser(w http.ResponseWriter, r *http.Request)
+	ListUserNotification(w http.ResponseWriter, r *http.Request)
 
 	// handler loan
 	ListLoan(w http.ResponseWriter, r *http.Request)
 	DetailLoan(w http.ResponseWriter, r *http.Request)
+	StreamLoanEvents(w http.ResponseWriter, r *http.Request)
 	SubmitLoan(w http.ResponseWriter, r *http.Request)
 	ApproveLoan(w http.ResponseWriter, r *http.Request)
+	ViewPictureProof(w http.ResponseWriter, r *http.Request)
 	InvestLoan(w http.ResponseWriter, r *http.Request)
 	DisburseLoan(w http.ResponseWriter, r *http.Request)
 
+	// handler task
+	ListEmployeeTask(w http.ResponseWriter, r *http.Request)
+
 	// handler agreement
 	ListAgreement(w http.ResponseWriter, r *http.Request)
 	DetailAgreement(w http.ResponseWriter, r *http.Request)
 	SignAgreement(w http.ResponseWriter, r *http.Request)
+
+	// handler webhook
+	SubscribeWebhook(w http.ResponseWriter, r *http.Request)
+	ListWebhook(w http.ResponseWriter, r *http.Request)
+	UnsubscribeWebhook(w http.ResponseWriter, r *http.Request)
+	ListWebhookDeadLetter(w http.ResponseWriter, r *http.Request)
+	RetryWebhookDeadLetter(w http.ResponseWriter, r *http.Request)
+
+	// handler audit
+	ListAudit(w http.ResponseWriter, r *http.Request)
+	VerifyAudit(w http.ResponseWriter, r *http.Request)
 }
 
 type Handler struct {
 	IHandler
-	Helper helper.IHelper
+	Service service.IService
+	// IdempotencyStore keeps the responses of requests sent with an idempotency key, nil disables idempotency
+	IdempotencyStore idempotency.IStore
+	// Metrics are served on /metrics and observe every request, nil disables both
+	Metrics *metrics.Metrics
+	// RateLimitStore keeps the token buckets of the rate limited clients, nil disables rate limiting
+	RateLimitStore ratelimit.IStore
+	// DocumentStore is pinged by /readyz, nil leaves it out of the readiness check
+	DocumentStore storage.IDocumentStore
+	// Authenticator verifies the bearer tokens of the requests, nil leaves every request anonymous
+	Authenticator *auth.Authenticator
+	// TrustedProxies are the reverse proxies whose forwarded client address is trusted
+	TrustedProxies []netip.Prefix
+
+	draining         atomic.Bool
+	initStreamsOnce  sync.Once
+	closeStreamsOnce sync.Once
+	streamsDone      chan struct{}
 }
 
-func NewHandler(helper helper.IHelper) *Handler {
+func NewHandler(service service.IService) *Handler {
 	return &Handler{
-		Helper: helper,
+		Service: service,
 	}
 }
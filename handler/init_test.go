@@ -5,13 +5,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"amartha-test/helper/mocks"
+	"amartha-test/service/mocks"
 )
 
 func TestNewHandler(t *testing.T) {
-	mockHelper := new(mocks.IHelper)
-	handler := NewHandler(mockHelper)
+	mockService := new(mocks.IService)
+	handler := NewHandler(mockService)
 
 	assert.NotNil(t, handler)
-	assert.Equal(t, mockHelper, handler.Helper)
+	assert.Equal(t, mockService, handler.Service)
 }
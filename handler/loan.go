@@ -1,381 +1,376 @@
 package handler
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"amartha-test/apperror"
+	"amartha-test/auth"
 	"amartha-test/constant"
+	"amartha-test/logging"
 	"amartha-test/model"
+	"amartha-test/picture"
 )
 
-// ListLoan is handler to get list of loans
-func (h *Handler) ListLoan(w http.ResponseWriter, r *http.Request) {
-	// 1. get loan list
-	loans := h.Helper.GetLoans()
-	if len(loans) == 0 {
-		log.Println("[ListLoan] loan list is empty")
-		h.RenderResponse(w, r, "", http.StatusNotFound, "[ListLoan] loan list is empty")
-		return
-	}
+// multipartMemoryBytes is the part of a multipart body kept in memory, the rest of its files is spooled to disk
+const multipartMemoryBytes = 8 << 20
 
-	// 2. render response
-	h.RenderResponse(w, r, loans, http.StatusOK, "")
+var loanSortKeys = sortKeys[model.Loan]{
+	"created_at":       func(loan model.Loan) float64 { return float64(loan.CreatedAt.UnixMicro()) },
+	"principal_amount": func(loan model.Loan) float64 { return loan.PrincipalAmount },
+	"collected_amount": func(loan model.Loan) float64 { return loan.CollectedAmount },
+	"status":           func(loan model.Loan) float64 { return float64(loan.Status) },
 }
 
-// DetailLoan is handler to get loan detail
-func (h *Handler) DetailLoan(w http.ResponseWriter, r *http.Request) {
-	// 1. get vars
-	vars := mux.Vars(r)
-	loanID, err := strconv.ParseInt(vars["loan_id"], 10, 64)
+// ListLoan is handler to get list of loans, filterable by status, borrower_id, lender_id, created_from, created_to
+// and visit_out_of_range
+func (h *Handler) ListLoan(w http.ResponseWriter, r *http.Request) {
+	// 1. get query params
+	query := r.URL.Query()
+	page, err := parsePageRequest(query, loanSortKeys, "created_at")
 	if err != nil {
-		log.Printf("[DetailLoan] failed parse int, with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DetailLoan] failed parse int, with error: %+v", err))
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListLoan", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
 		return
 	}
 
-	// 2. sanitize payload
-	if loanID == 0 {
-		log.Println("[DetailLoan] loan id is zero")
-		h.RenderResponse(w, r, "", http.StatusBadRequest, "[DetailLoan] loan id is zero")
+	var filter model.LoanFilter
+	if query.Get("status") != "" {
+		filter.Status = constant.GetLoanStatusByDesc(query.Get("status"))
+		if filter.Status == 0 {
+			logging.FromContext(r.Context()).Info("loan status is invalid", "op", "ListLoan", "status", query.Get("status"))
+			h.RenderError(w, r, apperror.InvalidRequest.New().WithDetail("field", "status"))
+			return
+		}
+	}
+	filter.BorrowerID, err = parseIDQuery(query, "borrower_id")
+	if err == nil {
+		filter.LenderID, err = parseIDQuery(query, "lender_id")
+	}
+	if err == nil {
+		filter.CreatedFrom, filter.CreatedTo, err = parseCreatedRangeQuery(query)
+	}
+	if err == nil {
+		filter.IsVisitOutOfRange, err = parseBoolQuery(query, "visit_out_of_range")
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid filter", "op", "ListLoan", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("reason", err.Error()))
 		return
 	}
 
-	// 3. get loan by loan id
-	loan := h.Helper.GetLoanByLoanID(loanID)
-	if loan.LoanID == 0 {
-		log.Printf("[DetailLoan][LoanID: %d] loan data is not found", loanID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[DetailLoan][LoanID: %d] loan data is not found", loanID))
+	// 2. get loan list
+	loans := h.Service.ListLoans(r.Context(), filter)
+
+	// 3. paginate loan list
+	result, meta, err := paginate(loans, page, loanSortKeys, func(loan model.Loan) string { return loan.PublicID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListLoan", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
 		return
 	}
 
 	// 4. render response
-	h.RenderResponse(w, r, loan, http.StatusOK, "")
+	h.RenderListResponse(w, r, result, meta)
 }
 
-// SubmitLoan is handler to create new loan
-func (h *Handler) SubmitLoan(w http.ResponseWriter, r *http.Request) {
-	// 1. decode body
-	var loan model.Loan
-	err := json.NewDecoder(r.Body).Decode(&loan)
+// DetailLoan is handler to get loan detail
+func (h *Handler) DetailLoan(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	loanID := vars["loan_id"]
+
+	// 2. get loan by loan id
+	loan, err := h.Service.GetLoan(r.Context(), loanID)
 	if err != nil {
-		log.Printf("[SubmitLoan] fail decode body with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[SubmitLoan] fail decode body with error: %+v", err))
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 2. sanitize payload
-	if loan.BorrowerID == 0 {
-		log.Println("[SubmitLoan] borrower id is empty")
-		h.RenderResponse(w, r, "", http.StatusBadRequest, "[SubmitLoan] borrower id is empty")
+	// 3. render response
+	h.RenderResponse(w, r, loan, http.StatusOK)
+}
+
+// StreamLoanEvents is handler to stream the loan funding progress as server-sent events, starting with
+// a snapshot and followed by every change of collected amount, status or lender count
+func (h *Handler) StreamLoanEvents(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	loanID := vars["loan_id"]
+
+	// 2. watch loan
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	loan, events, err := h.Service.WatchLoan(ctx, loanID)
+	if err != nil {
+		h.RenderError(w, r, err)
 		return
 	}
-	if loan.PrincipalAmount == 0 {
-		log.Printf("[SubmitLoan][BorrowerID: %d] principal amount is empty", loan.BorrowerID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[SubmitLoan][BorrowerID: %d] principal amount is empty", loan.BorrowerID))
+
+	// 3. send snapshot
+	stream := newServerSentEventWriter(w)
+	lastUpdate := loan.GetUpdate()
+	err = stream.Event("", "snapshot", lastUpdate)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed write snapshot", "op", "StreamLoanEvents", "loan_id", loanID, "error", err)
 		return
 	}
-	if loan.InterestRate < 0 || loan.InterestRate > 1 {
-		log.Printf("[SubmitLoan][BorrowerID: %d][Amount: %.2f] interest rate is invalid", loan.BorrowerID, loan.PrincipalAmount)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[SubmitLoan][BorrowerID: %d][Amount: %.2f] interest rate is invalid", loan.BorrowerID, loan.PrincipalAmount))
-		return
+
+	// 4. send changes until the client leaves or the server shuts down
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-h.streamsClosed():
+			stream.Event("", "close", lastUpdate)
+			return
+		case <-heartbeat.C:
+			err = stream.Comment("heartbeat")
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			update := e.Loan.GetUpdate()
+			if update == lastUpdate {
+				continue
+			}
+			lastUpdate = update
+			err = stream.Event(e.ID, string(e.Type), update)
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).Info("failed write event", "op", "StreamLoanEvents", "loan_id", loanID, "error", err)
+			return
+		}
 	}
+}
 
-	// 3. get borrower by user id
-	borrower := h.Helper.GetUserByUserID(loan.BorrowerID)
-	if borrower.UserID == 0 {
-		log.Printf("[SubmitLoan][BorrowerID: %d][Amount: %.2f][Rate: %.2f] borrower data is not found", loan.BorrowerID, loan.PrincipalAmount, loan.InterestRate)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[SubmitLoan][BorrowerID: %d][Amount: %.2f][Rate: %.2f] borrower data is not found", loan.BorrowerID, loan.PrincipalAmount, loan.InterestRate))
+// SubmitLoan is handler to create new loan
+func (h *Handler) SubmitLoan(w http.ResponseWriter, r *http.Request) {
+	// 1. decode body
+	var body model.Loan
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("fail decode body", "op", "SubmitLoan", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body"))
 		return
 	}
 
-	// 4. check user status
-	if borrower.UserType != constant.UserTypeBorrower {
-		log.Printf("[SubmitLoan][BorrowerID: %d][Amount: %.2f][Rate: %.2f] user type is not borrower", loan.BorrowerID, loan.PrincipalAmount, loan.InterestRate)
-		h.RenderResponse(w, r, "", http.StatusForbidden, fmt.Sprintf("[SubmitLoan][BorrowerID: %d][Amount: %.2f][Rate: %.2f] user type is not borrower", loan.BorrowerID, loan.PrincipalAmount, loan.InterestRate))
+	// 2. submit loan
+	loan, err := h.Service.SubmitLoan(r.Context(), body.BorrowerID, body.PrincipalAmount, body.InterestRate)
+	if err != nil {
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 5. create loan
-	loan.LoanID = h.Helper.GenerateIncrementalLoanID()
-	loan.Status = constant.LoanStatusProposed
-	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
-	h.Helper.UpsertLoan(loan)
-
-	// 6. render response
-	h.RenderResponse(w, r, loan, http.StatusCreated, "")
+	// 3. render response
+	h.RenderResponse(w, r, loan, http.StatusCreated)
 }
 
-// ApproveLoan is handler to approve loan
+// ApproveLoan is handler to approve loan, the picture proof is uploaded as the picture_proof file of a multipart/form-data
+// body, or base64 encoded in the picture_proof field of a json body
 func (h *Handler) ApproveLoan(w http.ResponseWriter, r *http.Request) {
 	// 1. get vars
 	vars := mux.Vars(r)
-	loanID, err := strconv.ParseInt(vars["loan_id"], 10, 64)
-	if err != nil {
-		log.Printf("[ApproveLoan] failed parse int, with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[ApproveLoan] failed parse int, with error: %+v", err))
-		return
-	}
+	loanID := vars["loan_id"]
 
 	// 2. decode body
-	var approvalInfo model.ApprovalInfo
-	err = json.NewDecoder(r.Body).Decode(&approvalInfo)
+	approvalInfo, pictureProof, err := decodeApproval(r)
 	if err != nil {
-		log.Printf("[ApproveLoan][LoanID: %d] fail decode body with error: %+v", loanID, err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[ApproveLoan][LoanID: %d] fail decode body with error: %+v", loanID, err))
-		return
-	}
-
-	// 3. sanitize payload
-	if approvalInfo.PictureProof == "" {
-		log.Printf("[ApproveLoan][LoanID: %d] picture proof is empty", loanID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[ApproveLoan][LoanID: %d] picture proof is empty", loanID))
-		return
-	}
-	if approvalInfo.FieldValidatorEmployeeID == 0 {
-		log.Printf("[ApproveLoan][LoanID: %d] field validator employee id is empty", loanID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[ApproveLoan][LoanID: %d] field validator employee id is empty", loanID))
+		logging.FromContext(r.Context()).Info("fail decode body", "op", "ApproveLoan", "loan_id", loanID, "error", err)
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 4. get loan by loan id
-	loan := h.Helper.GetLoanByLoanID(loanID)
-	if loan.LoanID == 0 {
-		log.Printf("[ApproveLoan][LoanID: %d] loan data is not found", loanID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[ApproveLoan][LoanID: %d] loan data is not found", loanID))
+	// 3. approve loan
+	loan, err := h.Service.ApproveLoan(r.Context(), loanID, approvalInfo, pictureProof)
+	if err != nil {
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 5. check loan status
-	if loan.Status != constant.LoanStatusProposed {
-		log.Printf("[ApproveLoan][LoanID: %d] loan status is invalid, current status is: %s", loanID, constant.GetLoanStatusDesc(loan.Status))
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[ApproveLoan][LoanID: %d] loan status is invalid, current status is: %s", loanID, constant.GetLoanStatusDesc(loan.Status)))
-		return
-	}
+	// 4. render response
+	h.RenderResponse(w, r, loan, http.StatusOK)
+}
 
-	// 6. get field validator employee by user id
-	fieldValidatorEmployee := h.Helper.GetUserByUserID(approvalInfo.FieldValidatorEmployeeID)
-	if fieldValidatorEmployee.UserID == 0 {
-		log.Printf("[ApproveLoan][LoanID: %d][EmployeeID: %d] field validator employee data is not found", loanID, approvalInfo.FieldValidatorEmployeeID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[ApproveLoan][LoanID: %d][EmployeeID: %d] field validator employee data is not found", loanID, approvalInfo.FieldValidatorEmployeeID))
-		return
-	}
+// decodeApproval decodes the approval info and the picture proof of a multipart/form-data or json body
+func decodeApproval(r *http.Request) (model.ApprovalInfo, []byte, error) {
+	var approvalInfo model.ApprovalInfo
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		err := json.NewDecoder(r.Body).Decode(&approvalInfo)
+		if err != nil {
+			return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body")
+		}
 
-	// 7. check user type
-	if fieldValidatorEmployee.UserType != constant.UserTypeFieldValidatorEmployee {
-		log.Printf("[ApproveLoan][LoanID: %d][EmployeeID: %d] user type is not field validator employee", loanID, approvalInfo.FieldValidatorEmployeeID)
-		h.RenderResponse(w, r, "", http.StatusForbidden, fmt.Sprintf("[ApproveLoan][LoanID: %d][EmployeeID: %d] user type is not field validator employee", loanID, approvalInfo.FieldValidatorEmployeeID))
-		return
-	}
+		pictureProof, err := base64.StdEncoding.DecodeString(approvalInfo.PictureProof)
+		if err != nil {
+			return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "picture_proof")
+		}
+		approvalInfo.PictureProof = ""
 
-	// 8. update loan status to approve
-	loan.Status = constant.LoanStatusApproved
-	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
-	loan.ApprovalInfo = &model.ApprovalInfo{
-		PictureProof:             approvalInfo.PictureProof,
-		FieldValidatorEmployeeID: approvalInfo.FieldValidatorEmployeeID,
-		ApprovalDate:             approvalInfo.ApprovalDate,
-	}
-	approvalDate := approvalInfo.ApprovalDate
-	if approvalInfo.ApprovalDate.IsZero() {
-		approvalDate = time.Now()
+		return approvalInfo, pictureProof, nil
 	}
-	loan.ApprovalInfo.ApprovalDate = approvalDate
-	h.Helper.UpsertLoan(loan)
 
-	// 9. render response
-	h.RenderResponse(w, r, loan, http.StatusOK, "")
-}
-
-// InvestLoan is handler to invest loan
-func (h *Handler) InvestLoan(w http.ResponseWriter, r *http.Request) {
-	// 1. get vars
-	vars := mux.Vars(r)
-	loanID, err := strconv.ParseInt(vars["loan_id"], 10, 64)
+	err := r.ParseMultipartForm(multipartMemoryBytes)
 	if err != nil {
-		log.Printf("[InvestLoan] failed parse int, with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan] failed parse int, with error: %+v", err))
-		return
+		return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body")
 	}
+	defer r.MultipartForm.RemoveAll()
 
-	// 2. decode body
-	var lending model.Lending
-	err = json.NewDecoder(r.Body).Decode(&lending)
+	approvalInfo.FieldValidatorEmployeeID, err = strconv.ParseInt(r.FormValue("field_validator_employee_id"), 10, 64)
 	if err != nil {
-		log.Printf("[InvestLoan][LoanID: %d] fail decode body with error: %+v", loanID, err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan][LoanID: %d] fail decode body with error: %+v", loanID, err))
-		return
+		return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "field_validator_employee_id")
 	}
-
-	// 3. sanitize payload
-	if lending.LenderID == 0 {
-		log.Printf("[InvestLoan][LoanID: %d] lender id is empty", loanID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan][LoanID: %d] lender id is empty", loanID))
-		return
+	if v := r.FormValue("approval_date"); v != "" {
+		approvalInfo.ApprovalDate, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "approval_date")
+		}
 	}
-	if lending.InvestedAmount == 0 {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d] invested amount is empty", loanID, lending.LenderID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d] invested amount is empty", loanID, lending.LenderID))
-		return
+	approvalInfo.Visit, err = decodeVisitForm(r)
+	if err != nil {
+		return model.ApprovalInfo{}, nil, err
 	}
 
-	// 4. get loan by loan id
-	loan := h.Helper.GetLoanByLoanID(loanID)
-	if loan.LoanID == 0 {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] loan data not found", loanID, lending.LenderID, lending.InvestedAmount)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] loan data not found", loanID, lending.LenderID, lending.InvestedAmount))
-		return
+	// a missing file is left empty, the service reports it
+	file, _, err := r.FormFile("picture_proof")
+	if errors.Is(err, http.ErrMissingFile) {
+		return approvalInfo, nil, nil
 	}
-
-	// 5. check loan status
-	if loan.Status != constant.LoanStatusApproved {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] loan status invalid, current status is: %s", loanID, lending.LenderID, lending.InvestedAmount, constant.GetLoanStatusDesc(loan.Status))
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] loan status invalid, current status is: %s", loanID, lending.LenderID, lending.InvestedAmount, constant.GetLoanStatusDesc(loan.Status)))
-		return
+	if err != nil {
+		return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "picture_proof")
 	}
+	defer file.Close()
 
-	// 6. get lender by user id
-	lender := h.Helper.GetUserByUserID(lending.LenderID)
-	if lender.UserID == 0 {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] lender data is not found", loanID, lending.LenderID, lending.InvestedAmount)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] lender data is not found", loanID, lending.LenderID, lending.InvestedAmount))
-		return
+	pictureProof, err := io.ReadAll(file)
+	if err != nil {
+		return model.ApprovalInfo{}, nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "picture_proof")
 	}
 
-	// 7. check user type
-	if lender.UserType != constant.UserTypeLender {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] user type is not lender", loanID, lending.LenderID, lending.InvestedAmount)
-		h.RenderResponse(w, r, "", http.StatusForbidden, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] user type is not lender", loanID, lending.LenderID, lending.InvestedAmount))
-		return
-	}
+	return approvalInfo, pictureProof, nil
+}
 
-	// 8. check invested amount
-	if lending.InvestedAmount > loan.GetRemainingRequiredAmount() {
-		log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] invested amount is bigger than remaining required amount: %.2f", loanID, lending.LenderID, lending.InvestedAmount, loan.GetRemainingRequiredAmount())
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] invested amount is bigger than remaining required amount: %.2f", loanID, lending.LenderID, lending.InvestedAmount, loan.GetRemainingRequiredAmount()))
-		return
+// decodeVisitForm reads the optional field visit of a multipart form from visit_latitude, visit_longitude and
+// visited_at (RFC3339), nil when no position is given
+func decodeVisitForm(r *http.Request) (*model.Visit, error) {
+	if r.FormValue("visit_latitude") == "" && r.FormValue("visit_longitude") == "" {
+		return nil, nil
 	}
 
-	// 9. update loan lending data
-	if loan.IsLenderInvested(lender.UserID) {
-		for i := 0; i < len(loan.Lending); i++ {
-			if loan.Lending[i].LenderID == lender.UserID {
-				loan.Lending[i].InvestedAmount += lending.InvestedAmount
-				loan.Lending[i].ReturnAmount = loan.Lending[i].CalculateLenderReturnAmount(loan.InterestRate)
-			}
-		}
-	} else {
-		lendingdata := model.Lending{
-			LenderID:       lending.LenderID,
-			InvestedAmount: lending.InvestedAmount,
-		}
-		lendingdata.ReturnAmount = lendingdata.CalculateLenderReturnAmount(loan.InterestRate)
-		loan.Lending = append(loan.Lending, lendingdata)
+	var visit model.Visit
+	var err error
+	visit.Location.Latitude, err = strconv.ParseFloat(r.FormValue("visit_latitude"), 64)
+	if err != nil {
+		return nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "visit_latitude")
 	}
-	loan.CollectedAmount += lending.InvestedAmount
-
-	// 10. check principal amount is fulfilled?
-	if loan.IsAmountFulfilled() {
-		// 10a. update loan status to invested
-		loan.Status = constant.LoanStatusInvested
-		loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
-
-		// 10b. generate lender agreement pdf
-		err = h.Helper.GenerateLenderAgreementPDF(&loan)
+	visit.Location.Longitude, err = strconv.ParseFloat(r.FormValue("visit_longitude"), 64)
+	if err != nil {
+		return nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "visit_longitude")
+	}
+	if v := r.FormValue("visited_at"); v != "" {
+		visit.VisitedAt, err = time.Parse(time.RFC3339, v)
 		if err != nil {
-			log.Printf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] fail to generate lender agreement pdf with error: %+v", loanID, lending.LenderID, lending.InvestedAmount, err)
-			h.RenderResponse(w, r, "", http.StatusInternalServerError, fmt.Sprintf("[InvestLoan][LoanID: %d][LenderID: %d][Amount: %.2f] fail to generate lender agreement pdf with error: %+v", loanID, lending.LenderID, lending.InvestedAmount, err))
-			return
+			return nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "visited_at")
 		}
 	}
 
-	// 11. update loan lending
-	h.Helper.UpsertLoan(loan)
-
-	// 12. render response
-	h.RenderResponse(w, r, loan, http.StatusOK, "")
+	return &visit, nil
 }
 
-// DisburseLoan is handler to disburse loan
-func (h *Handler) DisburseLoan(w http.ResponseWriter, r *http.Request) {
+// ViewPictureProof is handler to view the picture proof of the approved loan, or its thumbnail with ?size=thumbnail.
+// Only the authenticated borrower of the loan and employees are allowed to see it
+func (h *Handler) ViewPictureProof(w http.ResponseWriter, r *http.Request) {
 	// 1. get vars
 	vars := mux.Vars(r)
-	loanID, err := strconv.ParseInt(vars["loan_id"], 10, 64)
-	if err != nil {
-		log.Printf("[DisburseLoan] failed parse int, with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DisburseLoan] failed parse int, with error: %+v", err))
+	loanID := vars["loan_id"]
+
+	var thumbnail bool
+	switch size := r.URL.Query().Get("size"); size {
+	case "", "original":
+	case "thumbnail":
+		thumbnail = true
+	default:
+		logging.FromContext(r.Context()).Info("picture size is invalid", "op", "ViewPictureProof", "loan_id", loanID, "size", size)
+		h.RenderError(w, r, apperror.InvalidRequest.New().WithDetail("field", "size"))
 		return
 	}
 
-	// 2. decode body
-	var disbursement model.Disbursement
-	err = json.NewDecoder(r.Body).Decode(&disbursement)
+	// 2. get authenticated user, zero when anonymous
+	userID, _ := auth.UserIDFrom(r.Context())
+
+	// 3. open picture proof
+	proof, document, err := h.Service.OpenPictureProof(r.Context(), loanID, userID, thumbnail)
 	if err != nil {
-		log.Printf("[DisburseLoan][LoanID: %d] fail decode body with error: %+v", loanID, err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DisburseLoan][LoanID: %d] fail decode body with error: %+v", loanID, err))
+		h.RenderError(w, r, err)
 		return
 	}
+	defer document.Content.Close()
 
-	// 3. sanitize payload
-	if disbursement.FieldOfficerID == 0 {
-		log.Printf("[DisburseLoan][LoanID: %d] field officer id is empty", loanID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DisburseLoan][LoanID: %d] field officer id is empty", loanID))
-		return
-	}
-	if disbursement.DisbursementDate.IsZero() {
-		log.Printf("[DisburseLoan][LoanID: %d][OfficerID: %d] invalid disbursement date", loanID, disbursement.FieldOfficerID)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DisburseLoan][LoanID: %d][OfficerID: %d] invalid disbursement date", loanID, disbursement.FieldOfficerID))
-		return
+	// 4. render response
+	contentType := proof.ContentType
+	if thumbnail {
+		contentType = picture.ContentTypeJPEG
 	}
+	h.RenderPictureResponse(w, r, document, contentType)
+}
 
-	// 4. get loan by loan id
-	loan := h.Helper.GetLoanByLoanID(loanID)
-	if loan.LoanID == 0 {
-		log.Printf("[DisburseLoan][LoanID: %d][OfficerID: %d] loan data not found", loanID, disbursement.FieldOfficerID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[DisburseLoan][LoanID: %d][OfficerID: %d] loan data not found", loanID, disbursement.FieldOfficerID))
-		return
-	}
+// InvestLoan is handler to invest loan
+func (h *Handler) InvestLoan(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	loanID := vars["loan_id"]
 
-	// 5. check loan status
-	if loan.Status != constant.LoanStatusSigned {
-		log.Printf("[DisburseLoan][LoanID: %d][OfficerID: %d] loan status invalid, current status is: %s", loanID, disbursement.FieldOfficerID, constant.GetLoanStatusDesc(loan.Status))
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DisburseLoan][LoanID: %d][OfficerID: %d] loan status invalid, current status is: %s", loanID, disbursement.FieldOfficerID, constant.GetLoanStatusDesc(loan.Status)))
+	// 2. decode body
+	var lending model.Lending
+	err := json.NewDecoder(r.Body).Decode(&lending)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("fail decode body", "op", "InvestLoan", "loan_id", loanID, "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body"))
 		return
 	}
 
-	// 6. get field officer employee by user id
-	fieldOfficerEmployee := h.Helper.GetUserByUserID(disbursement.FieldOfficerID)
-	if fieldOfficerEmployee.UserID == 0 {
-		log.Printf("[DisburseLoan][LoanID: %d][OfficerID: %d] field officer employee data is not found", loanID, disbursement.FieldOfficerID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[DisburseLoan][LoanID: %d][OfficerID: %d] field officer employee data is not found", loanID, disbursement.FieldOfficerID))
+	// 3. invest loan
+	loan, err := h.Service.Invest(r.Context(), loanID, lending.LenderID, lending.InvestedAmount)
+	if err != nil {
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 7. check user type
-	if fieldOfficerEmployee.UserType != constant.UserTypeFieldOfficerEmployee {
-		log.Printf("[DisburseLoan][LoanID: %d][OfficerID: %d] user type is not field officer employee", loanID, disbursement.FieldOfficerID)
-		h.RenderResponse(w, r, "", http.StatusForbidden, fmt.Sprintf("[DisburseLoan][LoanID: %d][OfficerID: %d] user type is not field officer employee", loanID, disbursement.FieldOfficerID))
+	// 4. render response
+	h.RenderResponse(w, r, loan, http.StatusOK)
+}
+
+// DisburseLoan is handler to disburse loan
+func (h *Handler) DisburseLoan(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	loanID := vars["loan_id"]
+
+	// 2. decode body
+	var disbursement model.Disbursement
+	err := json.NewDecoder(r.Body).Decode(&disbursement)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("fail decode body", "op", "DisburseLoan", "loan_id", loanID, "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body"))
 		return
 	}
 
-	// 8. update loan disbursement and status
-	loan.Status = constant.LoanStatusDisbursed
-	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
-	loan.DisbursementInfo.FieldOfficerID = disbursement.FieldOfficerID
-	disbursementDate := disbursement.DisbursementDate
-	if disbursement.DisbursementDate.IsZero() {
-		disbursementDate = time.Now()
+	// 3. disburse loan
+	loan, err := h.Service.Disburse(r.Context(), loanID, disbursement)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
 	}
-	loan.DisbursementInfo.DisbursementDate = disbursementDate
 
-	// 9. upsert loan
-	h.Helper.UpsertLoan(loan)
-
-	// 10. render response
-	h.RenderResponse(w, r, loan, http.StatusOK, "")
+	// 4. render response
+	h.RenderResponse(w, r, loan, http.StatusOK)
 }
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,28 +22,104 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"amartha-test/constant"
+	"amartha-test/event"
 	"amartha-test/helper/mocks"
 	"amartha-test/model"
+	"amartha-test/service"
+	"amartha-test/storage"
+)
+
+const (
+	loanPublicID        = "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24"
+	unknownLoanPublicID = "b8e1f4a2-6c3d-4f9e-8a1b-7d2c5e9f0a13"
 )
 
 func TestListLoan(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
 		name         string
+		query        string
 		isError      bool
 		expectedCode int
 		mocks        func()
 	}{
 		{
-			name:         "error - list empty",
+			name:         "error - invalid limit",
+			query:        "?limit=0",
 			isError:      true,
-			expectedCode: http.StatusNotFound,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid sort",
+			query:        "?sort=unknown",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid status",
+			query:        "?status=unknown",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid borrower id",
+			query:        "?borrower_id=?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid created from",
+			query:        "?created_from=yesterday",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid visit out of range",
+			query:        "?visit_out_of_range=maybe",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid cursor",
+			query:        "?cursor=invalid",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetLoansByFilter", mock.Anything, mock.Anything).Return([]model.Loan{{LoanID: 1}}).Once()
+			},
+		},
+		{
+			name:         "success - with filter",
+			query:        "?status=approved&borrower_id=1&lender_id=2&created_from=2026-01-01&created_to=2026-01-31&visit_out_of_range=true&sort=-principal_amount",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetLoansByFilter", mock.Anything, model.LoanFilter{
+					Status:            constant.LoanStatusApproved,
+					BorrowerID:        1,
+					LenderID:          2,
+					CreatedFrom:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+					CreatedTo:         time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+					IsVisitOutOfRange: true,
+				}).Return([]model.Loan{{LoanID: 1}}).Once()
+			},
+		},
+		{
+			name:         "success - list empty",
+			isError:      false,
+			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoans").Return([]model.Loan{}).Once()
+				mockHelper.On("GetLoansByFilter", mock.Anything, mock.Anything).Return([]model.Loan{}).Once()
 			},
 		},
 		{
@@ -44,9 +127,13 @@ func TestListLoan(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoans").Return([]model.Loan{
+				mockHelper.On("GetLoansByFilter", mock.Anything, mock.Anything).Return([]model.Loan{
 					{
-						LoanID: 1,
+						LoanID:                        1,
+						PublicID:                      loanPublicID,
+						OrganizerBorrowerAggrementURL: "http://localhost:8080/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view",
+						Lending:                       []model.Lending{{LenderID: 2, OrganizerLenderAggrementURL: "http://localhost:8080/v1/agreement/9c2b5f6d-7e8a-4b0c-8b7e-6a4c3d1f4e8a/view"}},
+						DisbursementInfo:              model.DisbursementInfo{AgreementSignedURLs: []string{"http://localhost:8080/v1/agreement/7a3e9c1b-4d2f-4b8a-a6e0-2c9f5b1d8e47/view"}},
 					},
 				}).Once()
 			},
@@ -57,12 +144,11 @@ func TestListLoan(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mocks()
 
-			r, err := http.NewRequest("GET", "/loans/list", nil)
+			r, err := http.NewRequest("GET", "/loans/list"+tt.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -77,6 +163,12 @@ func TestListLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				// only the public loan id is served
+				assert.NotContains(t, w.Body.String(), `"loan_id":1`)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -85,7 +177,7 @@ func TestListLoan(t *testing.T) {
 func TestDetailLoan(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
@@ -95,36 +187,29 @@ func TestDetailLoan(t *testing.T) {
 		expectedCode int
 		mocks        func()
 	}{
-		{
-			name:         "error - convert string to int64",
-			vars:         "?",
-			isError:      true,
-			expectedCode: http.StatusBadRequest,
-			mocks:        func() {},
-		},
 		{
 			name:         "error - sanitize payload",
-			vars:         "0",
+			vars:         "",
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
 			name:         "error - loan data not found",
-			vars:         "1",
+			vars:         loanPublicID,
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
 			},
 		},
 		{
 			name:         "success",
-			vars:         "1",
+			vars:         loanPublicID,
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{
 					LoanID: 1,
 				}).Once()
 			},
@@ -142,7 +227,6 @@ func TestDetailLoan(t *testing.T) {
 			vars := map[string]string{"loan_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -157,15 +241,121 @@ func TestDetailLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
 }
 
+// readServerSentEvent reads the next event of the stream, skipping comments
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) (string, string, model.LoanUpdate) {
+	var id, name string
+	var update model.LoanUpdate
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed read stream: %+v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && name != "":
+			return id, name, update
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &update))
+		}
+	}
+}
+
+func TestStreamLoanEvents(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := service.NewService(mockHelper)
+	mockHandler := &Handler{
+		Service: svc,
+	}
+	// served through the middleware chain, the stream outlives the request timeout
+	server := httptest.NewServer(Chain(mockHandler.Router(),
+		mockHandler.MeasureLatency,
+		mockHandler.RequestID,
+		mockHandler.AccessLog,
+		mockHandler.Recover,
+		mockHandler.BodyLimit(1024),
+		mockHandler.Timeout(50*time.Millisecond),
+	))
+	defer server.Close()
+
+	t.Run("error - loan data not found", func(t *testing.T) {
+		mockHelper.On("GetLoanByPublicID", mock.Anything, unknownLoanPublicID).Return(model.Loan{}).Once()
+
+		resp, err := http.Get(server.URL + "/v1/loan/" + unknownLoanPublicID + "/events")
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("success - snapshot, changes and close on shutdown", func(t *testing.T) {
+		loan := model.Loan{LoanID: 1, PublicID: loanPublicID, PrincipalAmount: 1000, Status: constant.LoanStatusApproved, StatusDesc: "approved"}
+		mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loan).Once()
+
+		resp, err := http.Get(server.URL + "/v1/loan/" + loanPublicID + "/events")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		reader := bufio.NewReader(resp.Body)
+
+		_, name, update := readServerSentEvent(t, reader)
+		assert.Equal(t, "snapshot", name)
+		assert.Equal(t, loan.GetUpdate(), update)
+
+		// the first lender invests
+		loan.CollectedAmount = 400
+		loan.Lending = []model.Lending{{LenderID: 2, InvestedAmount: 400}}
+		invested := event.New(event.LoanInvestmentAdded, 2, loan)
+		svc.Events.Publish(context.Background(), invested)
+		// a signature changes neither amount, status nor lenders so it is not sent
+		svc.Events.Publish(context.Background(), event.New(event.AgreementSigned, 2, loan))
+		// the second lender fulfils the loan
+		loan.CollectedAmount = 1000
+		loan.Lending = append(loan.Lending, model.Lending{LenderID: 3, InvestedAmount: 600})
+		loan.Status = constant.LoanStatusInvested
+		loan.StatusDesc = "invested"
+		fulfilled := event.New(event.LoanInvested, 3, loan)
+		svc.Events.Publish(context.Background(), fulfilled)
+
+		id, name, update := readServerSentEvent(t, reader)
+		assert.Equal(t, invested.ID, id)
+		assert.Equal(t, string(event.LoanInvestmentAdded), name)
+		assert.Equal(t, model.LoanUpdate{LoanID: loanPublicID, Status: constant.LoanStatusApproved, StatusDesc: "approved", PrincipalAmount: 1000, CollectedAmount: 400, RemainingAmount: 600, LenderCount: 1}, update)
+
+		id, name, update = readServerSentEvent(t, reader)
+		assert.Equal(t, fulfilled.ID, id)
+		assert.Equal(t, string(event.LoanInvested), name)
+		assert.Equal(t, 2, update.LenderCount)
+		assert.Equal(t, constant.LoanStatusInvested, update.Status)
+
+		mockHandler.CloseStreams()
+		_, name, _ = readServerSentEvent(t, reader)
+		assert.Equal(t, "close", name)
+		_, err = reader.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	mockHelper.AssertExpectations(t)
+}
+
 func TestSubmitLoan(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
@@ -219,7 +409,7 @@ func TestSubmitLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
@@ -232,7 +422,7 @@ func TestSubmitLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusForbidden,
 			mocks: func() {
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 1, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 1, UserType: constant.UserTypeLender}).Once()
 			},
 		},
 		{
@@ -245,9 +435,9 @@ func TestSubmitLoan(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusCreated,
 			mocks: func() {
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
-				mockHelper.On("GenerateIncrementalLoanID").Return(int64(1)).Once()
-				mockHelper.On("UpsertLoan", mock.Anything).Return().Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GenerateIncrementalLoanID", mock.Anything).Return(int64(1)).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
 			},
 		},
 	}
@@ -266,7 +456,6 @@ func TestSubmitLoan(t *testing.T) {
 				t.Fatal(err)
 			}
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -281,6 +470,9 @@ func TestSubmitLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -289,8 +481,9 @@ func TestSubmitLoan(t *testing.T) {
 func TestApproveLoan(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
+	pictureProof := base64.StdEncoding.EncodeToString(newPictureProof(t))
 
 	tests := []struct {
 		name         string
@@ -301,106 +494,124 @@ func TestApproveLoan(t *testing.T) {
 		mocks        func()
 	}{
 		{
-			name:         "error - get loan id from vars",
-			vars:         "?",
+			name:         "error - decode body",
+			vars:         loanPublicID,
 			requestBody:  "invalid body",
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
-			name:         "error - decode body",
-			vars:         "1",
-			requestBody:  "invalid body",
+			name:         "error - picture of proof is empty",
+			vars:         loanPublicID,
+			requestBody:  model.ApprovalInfo{},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
-			name:         "error - picture of proof is empty",
-			vars:         "1",
-			requestBody:  model.ApprovalInfo{},
+			name: "error - employee id is empty",
+			vars: loanPublicID,
+			requestBody: model.ApprovalInfo{
+				PictureProof: pictureProof,
+			},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
 		{
-			name: "error - employee id is empty",
-			vars: "1",
+			name: "error - picture proof is not base64",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof: "test",
+				PictureProof:             "not base64!",
+				FieldValidatorEmployeeID: 4,
 			},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
+		{
+			name: "error - picture proof is not a picture",
+			vars: loanPublicID,
+			requestBody: model.ApprovalInfo{
+				PictureProof:             "aW1hZ2U=",
+				FieldValidatorEmployeeID: 4,
+			},
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+			},
+		},
 		{
 			name: "error - loan data not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof:             "test",
+				PictureProof:             pictureProof,
 				FieldValidatorEmployeeID: 4,
 			},
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
 			},
 		},
 		{
 			name: "error - loan status not proposed",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof:             "test",
+				PictureProof:             pictureProof,
 				FieldValidatorEmployeeID: 4,
 			},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
 			},
 		},
 		{
 			name: "error - user id not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof:             "test",
+				PictureProof:             pictureProof,
 				FieldValidatorEmployeeID: 4,
 			},
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
 			name: "error - user type is not field validator employee",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof:             "test",
+				PictureProof:             pictureProof,
 				FieldValidatorEmployeeID: 4,
 			},
 			isError:      true,
 			expectedCode: http.StatusForbidden,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeBorrower}).Once()
 			},
 		},
 		{
 			name: "success",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.ApprovalInfo{
-				PictureProof:             "test",
+				PictureProof:             pictureProof,
 				FieldValidatorEmployeeID: 4,
 			},
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
-				mockHelper.On("UpsertLoan", mock.Anything).Return().Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("PutPictureProof", mock.Anything, mock.Anything).Return(model.PictureProof{DocumentKey: "document"}, nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
 			},
 		},
 	}
@@ -421,7 +632,6 @@ func TestApproveLoan(t *testing.T) {
 			vars := map[string]string{"loan_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -436,36 +646,302 @@ func TestApproveLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
 }
 
-func TestInvestLoan(t *testing.T) {
+// newPictureProof returns a png picture big enough to prove a visit
+func newPictureProof(t *testing.T) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 400, 300)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// newApprovalForm returns a multipart/form-data approval body and its content type, the file is left out when empty
+func newApprovalForm(t *testing.T, fields map[string]string, pictureProof []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(pictureProof) > 0 {
+		part, err := writer.CreateFormFile("picture_proof", "visit.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(pictureProof)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &body, writer.FormDataContentType()
+}
+
+func TestApproveLoanMultipart(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
+	pictureProof := newPictureProof(t)
 
 	tests := []struct {
 		name         string
-		vars         string
-		requestBody  interface{}
+		fields       map[string]string
+		pictureProof []byte
 		isError      bool
 		expectedCode int
 		mocks        func()
 	}{
 		{
-			name:         "error - get loan id from vars",
-			vars:         "?",
-			requestBody:  "invalid body",
+			name:         "error - employee id is not a number",
+			fields:       map[string]string{"field_validator_employee_id": "four"},
+			pictureProof: pictureProof,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - approval date is invalid",
+			fields:       map[string]string{"field_validator_employee_id": "4", "approval_date": "yesterday"},
+			pictureProof: pictureProof,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - visit latitude is not a number",
+			fields:       map[string]string{"field_validator_employee_id": "4", "visit_latitude": "north", "visit_longitude": "106.8137"},
+			pictureProof: pictureProof,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - picture proof is missing",
+			fields:       map[string]string{"field_validator_employee_id": "4"},
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "success",
+			fields:       map[string]string{"field_validator_employee_id": "4", "approval_date": "2026-10-01T10:00:00Z"},
+			pictureProof: pictureProof,
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("PutPictureProof", mock.Anything, mock.Anything).Return(model.PictureProof{DocumentKey: "document"}, nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.MatchedBy(func(loan model.Loan) bool {
+					return loan.ApprovalInfo.ApprovalDate.Equal(time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC))
+				})).Return().Once()
+			},
+		},
+		{
+			name: "success - with visit",
+			fields: map[string]string{
+				"field_validator_employee_id": "4",
+				"visit_latitude":              "-6.2606",
+				"visit_longitude":             "106.8137",
+				"visited_at":                  "2026-10-01T09:30:00Z",
+			},
+			pictureProof: pictureProof,
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Location: &model.GeoPoint{Latitude: -6.2607, Longitude: 106.8137}}).Once()
+				mockHelper.On("PutPictureProof", mock.Anything, mock.Anything).Return(model.PictureProof{DocumentKey: "document"}, nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.MatchedBy(func(loan model.Loan) bool {
+					visit := loan.ApprovalInfo.Visit
+					return visit != nil && visit.Location.Latitude == -6.2606 && visit.DistanceMeters != nil && !visit.IsOutOfRange
+				})).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			body, contentType := newApprovalForm(t, tt.fields, tt.pictureProof)
+			r := httptest.NewRequest("POST", "/v1/loan/"+loanPublicID+"/approve", body)
+			r.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.Router().ServeHTTP(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				assert.Contains(t, w.Body.String(), `"document_key":"document"`)
+				assert.NotContains(t, w.Body.String(), `"picture_proof"`)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestViewPictureProof(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service:       service.NewService(mockHelper),
+		Authenticator: testAuthenticator,
+	}
+	content := newPictureProof(t)
+	approvedLoan := model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusApproved, ApprovalInfo: &model.ApprovalInfo{
+		Proof: &model.PictureProof{DocumentKey: "document", ThumbnailKey: "thumbnail", ContentType: "image/png"},
+	}}
+	document := func(key string) storage.Document {
+		return storage.Document{Key: key, Size: int64(len(content)), Content: nopReadSeekCloser{bytes.NewReader(content)}}
+	}
+
+	tests := []struct {
+		name                string
+		path                string
+		userID              int64
+		header              map[string]string
+		isError             bool
+		expectedCode        int
+		expectedContentType string
+		mocks               func()
+	}{
+		{
+			name:         "error - size is invalid",
+			path:         "/v1/loan/" + loanPublicID + "/proof?size=huge",
+			userID:       1,
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks:        func() {},
 		},
+		{
+			name:         "error - user is not identified",
+			path:         "/v1/loan/" + loanPublicID + "/proof",
+			isError:      true,
+			expectedCode: http.StatusUnauthorized,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - user header is not trusted",
+			path:         "/v1/loan/" + loanPublicID + "/proof",
+			header:       map[string]string{"X-User-ID": "1"},
+			isError:      true,
+			expectedCode: http.StatusUnauthorized,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - lender is not allowed",
+			path:         "/v1/loan/" + loanPublicID + "/proof",
+			userID:       2,
+			isError:      true,
+			expectedCode: http.StatusForbidden,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+			},
+		},
+		{
+			name:                "success - borrower",
+			path:                "/v1/loan/" + loanPublicID + "/proof",
+			userID:              1,
+			isError:             false,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "image/png",
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+				mockHelper.On("OpenPictureProof", mock.Anything, approvedLoan, false).Return(document("document"), nil).Once()
+			},
+		},
+		{
+			name:                "success - thumbnail",
+			path:                "/v1/loan/" + loanPublicID + "/proof?size=thumbnail",
+			userID:              4,
+			isError:             false,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "image/jpeg",
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+				mockHelper.On("OpenPictureProof", mock.Anything, approvedLoan, true).Return(document("thumbnail"), nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r := httptest.NewRequest("GET", tt.path, nil)
+			if tt.userID != 0 {
+				r.Header.Set(constant.HeaderAuthorization, bearer(tt.userID))
+			}
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			// main func
+			Chain(mockHandler.Router(), mockHandler.Authenticate).ServeHTTP(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+				assert.NotEmpty(t, w.Header().Get("ETag"))
+				assert.Equal(t, content, w.Body.Bytes())
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInvestLoan(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		vars         string
+		requestBody  interface{}
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
 		{
 			name:         "error - decode body",
-			vars:         "1",
+			vars:         loanPublicID,
 			requestBody:  "invalid body",
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
@@ -473,7 +949,7 @@ func TestInvestLoan(t *testing.T) {
 		},
 		{
 			name:         "error - lender id is empty",
-			vars:         "1",
+			vars:         loanPublicID,
 			requestBody:  model.Lending{},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
@@ -481,7 +957,7 @@ func TestInvestLoan(t *testing.T) {
 		},
 		{
 			name: "error - invested amount is empty",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID: 2,
 			},
@@ -491,7 +967,7 @@ func TestInvestLoan(t *testing.T) {
 		},
 		{
 			name: "error - loan data not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -499,12 +975,12 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
 			},
 		},
 		{
 			name: "error - loan status is not approved",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -512,12 +988,12 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
 			},
 		},
 		{
 			name: "error - user data not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -525,13 +1001,13 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
 			name: "error - user type is not lender",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -539,13 +1015,13 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusForbidden,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeBorrower}).Once()
 			},
 		},
 		{
 			name: "error - invested amount is bigger than remaining required amount",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -553,13 +1029,13 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1000000, CollectedAmount: 500000}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1000000, CollectedAmount: 500000}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
 			},
 		},
 		{
 			name: "error - fail generate lender agreements",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -567,14 +1043,14 @@ func TestInvestLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusInternalServerError,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1000000, CollectedAmount: 0}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
-				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything).Return(errors.New("fail")).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1000000, CollectedAmount: 0}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything, mock.Anything).Return(errors.New("fail")).Once()
 			},
 		},
 		{
 			name: "success",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Lending{
 				LenderID:       2,
 				InvestedAmount: 1000000,
@@ -582,10 +1058,10 @@ func TestInvestLoan(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1500000, CollectedAmount: 500000, Lending: []model.Lending{{LenderID: 2, InvestedAmount: 500000}}}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
-				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything).Return(nil).Once()
-				mockHelper.On("UpsertLoan", mock.Anything).Return().Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved, PrincipalAmount: 1500000, CollectedAmount: 500000, Lending: []model.Lending{{LenderID: 2, InvestedAmount: 500000}}}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
 			},
 		},
 	}
@@ -606,7 +1082,6 @@ func TestInvestLoan(t *testing.T) {
 			vars := map[string]string{"loan_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -621,6 +1096,9 @@ func TestInvestLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -629,7 +1107,7 @@ func TestInvestLoan(t *testing.T) {
 func TestDisburseLoan(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
@@ -640,17 +1118,9 @@ func TestDisburseLoan(t *testing.T) {
 		expectedCode int
 		mocks        func()
 	}{
-		{
-			name:         "error - get loan id from vars",
-			vars:         "?",
-			requestBody:  "invalid body",
-			isError:      true,
-			expectedCode: http.StatusBadRequest,
-			mocks:        func() {},
-		},
 		{
 			name:         "error - decode body",
-			vars:         "1",
+			vars:         loanPublicID,
 			requestBody:  "invalid body",
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
@@ -658,7 +1128,7 @@ func TestDisburseLoan(t *testing.T) {
 		},
 		{
 			name:         "error - field officer id is empty",
-			vars:         "1",
+			vars:         loanPublicID,
 			requestBody:  model.Disbursement{},
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
@@ -666,7 +1136,7 @@ func TestDisburseLoan(t *testing.T) {
 		},
 		{
 			name: "error - disbursement date is invalid",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID: 5,
 			},
@@ -676,7 +1146,7 @@ func TestDisburseLoan(t *testing.T) {
 		},
 		{
 			name: "error - loan data not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID:   5,
 				DisbursementDate: time.Now(),
@@ -684,12 +1154,12 @@ func TestDisburseLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
 			},
 		},
 		{
 			name: "error - loan status is not signed",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID:   5,
 				DisbursementDate: time.Now(),
@@ -697,12 +1167,12 @@ func TestDisburseLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
 			},
 		},
 		{
 			name: "error - user data not found",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID:   5,
 				DisbursementDate: time.Now(),
@@ -710,13 +1180,13 @@ func TestDisburseLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
 			name: "error - user type is not field officer employee",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID:   5,
 				DisbursementDate: time.Now(),
@@ -724,13 +1194,13 @@ func TestDisburseLoan(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusForbidden,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
 			},
 		},
 		{
 			name: "success",
-			vars: "1",
+			vars: loanPublicID,
 			requestBody: model.Disbursement{
 				FieldOfficerID:   5,
 				DisbursementDate: time.Now(),
@@ -738,9 +1208,9 @@ func TestDisburseLoan(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetLoanByLoanID", mock.Anything).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
-				mockHelper.On("UpsertLoan", mock.Anything).Return().Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
 			},
 		},
 	}
@@ -761,7 +1231,6 @@ func TestDisburseLoan(t *testing.T) {
 			vars := map[string]string{"loan_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -776,6 +1245,9 @@ func TestDisburseLoan(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -1,65 +1,95 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
+	"amartha-test/apperror"
 	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/storage"
 )
 
-// Middleware is middleware handler to initialize start time
-func (h *Handler) Middleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		startTime := time.Now()
-		time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
-		ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
-		next(w, r.WithContext(ctx))
-	}
+type Response struct {
+	Code    int             `json:"code"`
+	Latency string          `json:"latency"`
+	Data    interface{}     `json:"data,omitempty"`
+	Meta    *Meta           `json:"meta,omitempty"`
+	Error   *apperror.Error `json:"error,omitempty"`
+	// ErrorMessage is the user-safe message of Error, kept for clients reading the plain message
+	ErrorMessage string `json:"error_message,omitempty"`
 }
 
-type Response struct {
-	Code         int         `json:"code"`
-	Latency      string      `json:"latency"`
-	Data         interface{} `json:"data,omitempty"`
-	ErrorMessage string      `json:"error_message,omitempty"`
+func (h *Handler) RenderResponse(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
+	h.renderResponse(w, r, Response{
+		Code: statusCode,
+		Data: data,
+	})
 }
 
-func (h *Handler) RenderResponse(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int, errMsg string) {
-	startTime, ok := r.Context().Value(constant.CtxStartTimeKey).(time.Time)
-	if !ok {
-		log.Println("[RenderResponse] error retrieving start time")
-		http.Error(w, "error retrieving start time", http.StatusInternalServerError)
-		return
+// RenderListResponse renders a page of list data with its pagination meta
+func (h *Handler) RenderListResponse(w http.ResponseWriter, r *http.Request, data interface{}, meta Meta) {
+	h.renderResponse(w, r, Response{
+		Code: http.StatusOK,
+		Data: data,
+		Meta: &meta,
+	})
+}
+
+// RenderError renders the coded error of err with its registered http status, errors without code render as internal error.
+// A body cut by the body limit renders as request too large whichever error wraps it
+func (h *Handler) RenderError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = apperror.RequestTooLarge.Wrap(err).WithDetail("max_bytes", maxBytesErr.Limit)
 	}
 
-	latency := time.Since(startTime).Milliseconds()
+	appErr := apperror.From(err)
+	h.renderResponse(w, r, Response{
+		Code:         appErr.HTTPStatus,
+		Error:        appErr,
+		ErrorMessage: appErr.Message,
+	})
+}
 
-	response := Response{
-		Code:         statusCode,
-		Latency:      fmt.Sprintf("%dms", latency),
-		Data:         data,
-		ErrorMessage: errMsg,
+func (h *Handler) renderResponse(w http.ResponseWriter, r *http.Request, response Response) {
+	// latency is zero when the request did not go through MeasureLatency
+	var latency time.Duration
+	startTime, ok := r.Context().Value(constant.CtxStartTimeKey).(time.Time)
+	if ok {
+		latency = time.Since(startTime)
 	}
+	response.Latency = fmt.Sprintf("%dms", latency.Milliseconds())
 
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		log.Println("[RenderResponse] error marshalling JSON:", err)
+		logging.FromContext(r.Context()).Error("failed marshal response", "op", "RenderResponse", "error", err)
 		http.Error(w, "error marshalling JSON", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	w.WriteHeader(response.Code)
 	w.Write(jsonResponse)
 }
 
-func (h *Handler) RenderPDFResponse(w http.ResponseWriter, pdfData []byte, statusCode int) {
+// RenderPDFResponse streams the pdf document, supporting range requests and conditional requests by ETag
+func (h *Handler) RenderPDFResponse(w http.ResponseWriter, r *http.Request, document storage.Document) {
 	w.Header().Set("Content-Type", "application/pdf")
 	w.Header().Set("Content-Disposition", "inline; filename=agreement.pdf")
-	w.WriteHeader(statusCode)
-	w.Write(pdfData)
+	w.Header().Set("ETag", fmt.Sprintf("%q", document.Key))
+	http.ServeContent(w, r, "agreement.pdf", document.ModTime, document.Content)
+}
+
+// RenderPictureResponse streams the picture document like RenderPDFResponse, the content type is set from the stored picture
+// and never sniffed, and the picture is kept out of shared caches
+func (h *Handler) RenderPictureResponse(w http.ResponseWriter, r *http.Request, document storage.Document, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", fmt.Sprintf("%q", document.Key))
+	http.ServeContent(w, r, "", document.ModTime, document.Content)
 }
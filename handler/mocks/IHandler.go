@@ -48,6 +48,16 @@ func (_m *IHandler) ListAgreement(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
 }
 
+// ListAudit provides a mock function with given fields: w, r
+func (_m *IHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// ListEmployeeTask provides a mock function with given fields: w, r
+func (_m *IHandler) ListEmployeeTask(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
 // ListLoan provides a mock function with given fields: w, r
 func (_m *IHandler) ListLoan(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
@@ -58,16 +68,61 @@ func (_m *IHandler) ListUser(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
 }
 
+// ListUserNotification provides a mock function with given fields: w, r
+func (_m *IHandler) ListUserNotification(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// ListWebhook provides a mock function with given fields: w, r
+func (_m *IHandler) ListWebhook(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// ListWebhookDeadLetter provides a mock function with given fields: w, r
+func (_m *IHandler) ListWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// RetryWebhookDeadLetter provides a mock function with given fields: w, r
+func (_m *IHandler) RetryWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
 // SignAgreement provides a mock function with given fields: w, r
 func (_m *IHandler) SignAgreement(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
 }
 
+// StreamLoanEvents provides a mock function with given fields: w, r
+func (_m *IHandler) StreamLoanEvents(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
 // SubmitLoan provides a mock function with given fields: w, r
 func (_m *IHandler) SubmitLoan(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
 }
 
+// SubscribeWebhook provides a mock function with given fields: w, r
+func (_m *IHandler) SubscribeWebhook(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// UnsubscribeWebhook provides a mock function with given fields: w, r
+func (_m *IHandler) UnsubscribeWebhook(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// VerifyAudit provides a mock function with given fields: w, r
+func (_m *IHandler) VerifyAudit(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// ViewPictureProof provides a mock function with given fields: w, r
+func (_m *IHandler) ViewPictureProof(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
 // NewIHandler creates a new instance of IHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIHandler(t interface {
@@ -0,0 +1,582 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/openapi"
+	"amartha-test/service"
+	"amartha-test/storage"
+)
+
+func loadOpenAPISpec(t *testing.T) (*openapi3.T, routers.Router) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(openapi.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = doc.Validate(loader.Context); err != nil {
+		t.Fatal(err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc, router
+}
+
+func TestOpenAPI(t *testing.T) {
+	mockHandler := &Handler{}
+
+	r := httptest.NewRequest("GET", "/openapi.json", nil)
+	w := httptest.NewRecorder()
+
+	// main func
+	mockHandler.Router().ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, openapi.Spec, w.Body.Bytes())
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	doc, _ := loadOpenAPISpec(t)
+	mockHandler := &Handler{}
+
+	err := mockHandler.Router().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || path == "/openapi.json" || !strings.HasPrefix(path, constant.APIVersionPrefix+"/") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		pathItem := doc.Paths.Find(strings.TrimPrefix(path, constant.APIVersionPrefix))
+		if !assert.NotNil(t, pathItem, "route %s is not described", path) {
+			return nil
+		}
+		for _, method := range methods {
+			assert.NotNil(t, pathItem.GetOperation(method), "route %s %s is not described", method, path)
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+// requireBearer checks the bearer token required by the operation is sent, it is verified by the Authenticate middleware
+func requireBearer(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	if input.RequestValidationInput.Request.Header.Get(constant.HeaderAuthorization) == "" {
+		return errors.New("bearer token is missing")
+	}
+
+	return nil
+}
+
+func TestOpenAPIContract(t *testing.T) {
+	_, specRouter := loadOpenAPISpec(t)
+	openapi3filter.RegisterBodyDecoder("application/pdf", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("image/png", openapi3filter.FileBodyDecoder)
+
+	now := time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+	borrower := model.User{UserID: 1, UserName: "Septian", UserType: constant.UserTypeBorrower, Locale: constant.LocaleIndonesian, Email: "septian@example.com", PhoneNumber: "+6281200000001"}
+	lender := model.User{UserID: 2, UserName: "Pratama", UserType: constant.UserTypeLender, Locale: constant.LocaleEnglish}
+	validator := model.User{UserID: 3, UserName: "Validator", UserType: constant.UserTypeFieldValidatorEmployee, Locale: constant.LocaleIndonesian}
+	officer := model.User{UserID: 4, UserName: "Officer", UserType: constant.UserTypeFieldOfficerEmployee, Locale: constant.LocaleIndonesian}
+	loanWithStatus := func(status int) model.Loan {
+		return model.Loan{
+			LoanID:          1,
+			PublicID:        loanPublicID,
+			BorrowerID:      borrower.UserID,
+			PrincipalAmount: 1000000,
+			InterestRate:    0.1,
+			Status:          status,
+			StatusDesc:      constant.GetLoanStatusDesc(status),
+			CreatedAt:       now,
+		}
+	}
+	agreement := model.Aggrement{
+		AggrementID:       5,
+		PublicID:          "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c",
+		LoanID:            1,
+		LoanPublicID:      loanPublicID,
+		AgreementType:     constant.AgreementTypeOrganizerLender,
+		AgreementTypeDesc: constant.GetAgreementTypeDesc(constant.AgreementTypeOrganizerLender),
+		DocumentKey:       "0a1b",
+		UserID:            lender.UserID,
+		TemplateVersion:   "v2",
+		Locale:            constant.LocaleEnglish,
+		CreatedAt:         now,
+	}
+	pictureProof := newPictureProof(t)
+	proof := model.PictureProof{DocumentKey: "0c1d", ThumbnailKey: "0e1f", ContentType: "image/png", Width: 400, Height: 300, SizeBytes: len(pictureProof)}
+	approvedLoan := loanWithStatus(constant.LoanStatusApproved)
+	approvedLoan.ApprovalInfo = &model.ApprovalInfo{FieldValidatorEmployeeID: validator.UserID, ApprovalDate: now, Proof: &proof}
+	webhookSubscription := model.WebhookSubscription{
+		SubscriptionID: 1,
+		URL:            "https://receiver.example.com/events",
+		EventTypes:     []string{"loan.invested"},
+		CreatedAt:      now,
+	}
+	payload, err := json.Marshal(event.New(event.LoanInvested, lender.UserID, loanWithStatus(constant.LoanStatusInvested)))
+	assert.NoError(t, err)
+	deadLetter := model.WebhookDelivery{
+		DeliveryID:     1,
+		SubscriptionID: 1,
+		EventID:        "evt_0a1b",
+		EventType:      "loan.invested",
+		Payload:        payload,
+		Attempts:       5,
+		LastStatusCode: http.StatusServiceUnavailable,
+		LastError:      "receiver responded with status 503",
+		FailedAt:       now,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		userID         int64
+		invalidRequest bool
+		expectedCode   int
+		mocks          func(mockHelper *mocks.IHelper)
+	}{
+		{
+			name:         "list user",
+			method:       "GET",
+			path:         "/v1/user/list?user_type=1&limit=1",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUsersByFilter", mock.Anything, mock.Anything).Return([]model.User{borrower, {UserID: 6, UserType: constant.UserTypeBorrower, Locale: constant.LocaleIndonesian}})
+			},
+		},
+		{
+			name:           "list user - invalid limit",
+			method:         "GET",
+			path:           "/v1/user/list?limit=0",
+			invalidRequest: true,
+			expectedCode:   http.StatusBadRequest,
+			mocks:          func(mockHelper *mocks.IHelper) {},
+		},
+		{
+			name:         "detail user",
+			method:       "GET",
+			path:         "/v1/user/1/detail",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower)
+			},
+		},
+		{
+			name:         "detail user - not found",
+			method:       "GET",
+			path:         "/v1/user/9/detail",
+			expectedCode: http.StatusNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{})
+			},
+		},
+		{
+			name:         "list user notification",
+			method:       "GET",
+			path:         "/v1/user/1/notifications?limit=1",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower)
+				mockHelper.On("GetNotificationsByUserID", mock.Anything, int64(1)).Return([]model.Notification{{
+					NotificationID: 1,
+					UserID:         1,
+					EventID:        "evt_0a1b",
+					EventType:      "loan.approved",
+					Subject:        "Pinjaman #1 disetujui",
+					Body:           "Halo Septian",
+					CreatedAt:      now,
+				}})
+			},
+		},
+		{
+			name:         "list employee task",
+			method:       "GET",
+			path:         "/v1/employee/3/tasks?status=open&limit=1",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(3)).Return(validator)
+				mockHelper.On("GetTasksByFilter", mock.Anything, mock.Anything).Return([]model.Task{{
+					TaskID:       1,
+					LoanID:       1,
+					LoanPublicID: loanPublicID,
+					TaskType:     constant.TaskTypeValidationVisit,
+					TaskTypeDesc: constant.GetTaskTypeDesc(constant.TaskTypeValidationVisit),
+					Status:       constant.TaskStatusOpen,
+					StatusDesc:   constant.GetTaskStatusDesc(constant.TaskStatusOpen),
+					AssigneeID:   validator.UserID,
+					Region:       "jakarta-selatan",
+					CreatedAt:    now,
+				}})
+			},
+		},
+		{
+			name:         "list loan",
+			method:       "GET",
+			path:         "/v1/loan/list?status=proposed&sort=-created_at",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoansByFilter", mock.Anything, mock.Anything).Return([]model.Loan{loanWithStatus(constant.LoanStatusProposed)})
+			},
+		},
+		{
+			name:         "detail loan",
+			method:       "GET",
+			path:         "/v1/loan/" + loanPublicID + "/detail",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusProposed))
+			},
+		},
+		{
+			name:         "stream loan events - not found",
+			method:       "GET",
+			path:         "/v1/loan/" + unknownLoanPublicID + "/events",
+			expectedCode: http.StatusNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, unknownLoanPublicID).Return(model.Loan{})
+			},
+		},
+		{
+			name:         "submit loan",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			body:         `{"borrower_id":1,"principal_amount":1000000,"interest_rate":0.1}`,
+			expectedCode: http.StatusCreated,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower)
+				mockHelper.On("GenerateIncrementalLoanID", mock.Anything).Return(int64(1))
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:         "submit loan - wrong user type",
+			method:       "POST",
+			path:         "/v1/loan/submit",
+			body:         `{"borrower_id":2,"principal_amount":1000000,"interest_rate":0.1}`,
+			expectedCode: http.StatusForbidden,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender)
+			},
+		},
+		{
+			name:         "approve loan",
+			method:       "POST",
+			path:         "/v1/loan/" + loanPublicID + "/approve",
+			body:         fmt.Sprintf(`{"picture_proof":%q,"field_validator_employee_id":3,"approval_date":"2026-10-01T10:00:00Z"}`, base64.StdEncoding.EncodeToString(pictureProof)),
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusProposed))
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(3)).Return(validator)
+				mockHelper.On("PutPictureProof", mock.Anything, mock.Anything).Return(proof, nil)
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:         "approve loan - invalid picture proof",
+			method:       "POST",
+			path:         "/v1/loan/" + loanPublicID + "/approve",
+			body:         `{"picture_proof":"aW1hZ2U=","field_validator_employee_id":3}`,
+			expectedCode: http.StatusBadRequest,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusProposed))
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(3)).Return(validator)
+			},
+		},
+		{
+			name:         "view picture proof",
+			method:       "GET",
+			path:         "/v1/loan/" + loanPublicID + "/proof",
+			userID:       1,
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower)
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan)
+				mockHelper.On("OpenPictureProof", mock.Anything, approvedLoan, false).Return(storage.Document{
+					Key:     proof.DocumentKey,
+					ModTime: now,
+					Content: nopReadSeekCloser{bytes.NewReader(pictureProof)},
+				}, nil)
+			},
+		},
+		{
+			name:         "view picture proof - not allowed",
+			method:       "GET",
+			path:         "/v1/loan/" + loanPublicID + "/proof?size=thumbnail",
+			userID:       2,
+			expectedCode: http.StatusForbidden,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender)
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan)
+			},
+		},
+		{
+			name:           "view picture proof - unauthenticated",
+			method:         "GET",
+			path:           "/v1/loan/" + loanPublicID + "/proof",
+			invalidRequest: true,
+			expectedCode:   http.StatusUnauthorized,
+			mocks:          func(mockHelper *mocks.IHelper) {},
+		},
+		{
+			name:         "invest loan",
+			method:       "POST",
+			path:         "/v1/loan/" + loanPublicID + "/invest",
+			body:         `{"lender_id":2,"invested_amount":500000}`,
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusApproved))
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender)
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:         "invest loan - invalid loan status",
+			method:       "POST",
+			path:         "/v1/loan/" + loanPublicID + "/invest",
+			body:         `{"lender_id":2,"invested_amount":500000}`,
+			expectedCode: http.StatusBadRequest,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusProposed))
+			},
+		},
+		{
+			name:         "disburse loan",
+			method:       "POST",
+			path:         "/v1/loan/" + loanPublicID + "/disburse",
+			body:         `{"field_officer_id":4,"disbursement_date":"2026-10-02T10:00:00Z"}`,
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusSigned))
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(officer)
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:         "list agreement",
+			method:       "GET",
+			path:         "/v1/agreement/list?loan_id=" + loanPublicID + "&type=organizer-lender",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetAgreementsByFilter", mock.Anything, mock.Anything).Return([]model.Aggrement{agreement})
+			},
+		},
+		{
+			name:         "view agreement",
+			method:       "GET",
+			path:         "/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view",
+			userID:       lender.UserID,
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, lender.UserID).Return(lender)
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreement.PublicID).Return(agreement)
+				mockHelper.On("OpenAgreementDocument", mock.Anything, agreement).Return(storage.Document{
+					Key:     agreement.DocumentKey,
+					ModTime: now,
+					Content: nopReadSeekCloser{bytes.NewReader([]byte("%PDF-1.3"))},
+				}, nil)
+			},
+		},
+		{
+			name:         "view agreement - document not found",
+			method:       "GET",
+			path:         "/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view",
+			userID:       lender.UserID,
+			expectedCode: http.StatusNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, lender.UserID).Return(lender)
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreement.PublicID).Return(agreement)
+				mockHelper.On("OpenAgreementDocument", mock.Anything, agreement).Return(storage.Document{}, apperror.DocumentNotFound.New())
+			},
+		},
+		{
+			name:         "sign agreement - wrong signer",
+			method:       "POST",
+			path:         "/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/sign",
+			body:         `{"loan_id":"` + loanPublicID + `"}`,
+			userID:       1,
+			expectedCode: http.StatusForbidden,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(loanWithStatus(constant.LoanStatusInvested))
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower)
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreement.PublicID).Return(agreement)
+			},
+		},
+		{
+			name:         "subscribe webhook",
+			method:       "POST",
+			path:         "/v1/webhook/subscribe",
+			body:         `{"url":"https://93.184.215.14/events","event_types":["loan.invested","loan.disbursed"]}`,
+			userID:       5,
+			expectedCode: http.StatusCreated,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5})
+				mockHelper.On("GenerateIncrementalWebhookSubscriptionID", mock.Anything).Return(int64(1))
+				mockHelper.On("UpsertWebhookSubscription", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "subscribe webhook - unknown event type",
+			method:         "POST",
+			path:           "/v1/webhook/subscribe",
+			body:           `{"url":"https://93.184.215.14/events","event_types":["loan.deleted"]}`,
+			userID:         5,
+			invalidRequest: true,
+			expectedCode:   http.StatusBadRequest,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5})
+			},
+		},
+		{
+			name:           "subscribe webhook - unauthenticated",
+			method:         "POST",
+			path:           "/v1/webhook/subscribe",
+			body:           `{"url":"https://93.184.215.14/events"}`,
+			invalidRequest: true,
+			expectedCode:   http.StatusUnauthorized,
+			mocks:          func(mockHelper *mocks.IHelper) {},
+		},
+		{
+			name:         "list webhook",
+			method:       "GET",
+			path:         "/v1/webhook/list?sort=-created_at",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookSubscriptions", mock.Anything).Return([]model.WebhookSubscription{webhookSubscription})
+			},
+		},
+		{
+			name:         "unsubscribe webhook - not found",
+			method:       "POST",
+			path:         "/v1/webhook/9/unsubscribe",
+			expectedCode: http.StatusNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(9)).Return(model.WebhookSubscription{})
+			},
+		},
+		{
+			name:         "list webhook dead letter",
+			method:       "GET",
+			path:         "/v1/webhook/dead-letter/list",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetters", mock.Anything).Return([]model.WebhookDelivery{deadLetter})
+			},
+		},
+		{
+			name:         "retry webhook dead letter - not found",
+			method:       "POST",
+			path:         "/v1/webhook/dead-letter/9/retry",
+			expectedCode: http.StatusNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(9)).Return(model.WebhookDelivery{})
+			},
+		},
+		{
+			name:         "list audit",
+			method:       "GET",
+			path:         "/v1/audit/list?target_type=loan&target_id=" + loanPublicID,
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				entry, _ := audit.NewEntry(context.Background(), "loan", loanPublicID, nil, model.Loan{LoanID: 1, PublicID: loanPublicID})
+				mockHelper.On("GetAuditEntriesByFilter", mock.Anything, model.AuditFilter{TargetType: "loan", TargetID: loanPublicID}).Return([]model.AuditEntry{audit.Chain(entry, 1, audit.GenesisHash)})
+			},
+		},
+		{
+			name:         "verify audit",
+			method:       "GET",
+			path:         "/v1/audit/verify",
+			expectedCode: http.StatusOK,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetAuditEntries", mock.Anything).Return([]model.AuditEntry{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			mockHandler := &Handler{
+				Service:       service.NewService(mockHelper),
+				Authenticator: testAuthenticator,
+			}
+
+			newRequest := func() *http.Request {
+				r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				if tt.body != "" {
+					r.Header.Set("Content-Type", "application/json")
+				}
+				if tt.userID != 0 {
+					r.Header.Set(constant.HeaderAuthorization, bearer(tt.userID))
+				}
+				return r
+			}
+			w := httptest.NewRecorder()
+
+			// main func
+			Chain(mockHandler.Router(), mockHandler.Authenticate).ServeHTTP(w, newRequest())
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			// validate request and response against the specification
+			ctx := context.Background()
+			r := newRequest()
+			route, pathParams, err := specRouter.FindRoute(r)
+			if !assert.NoError(t, err) {
+				return
+			}
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &openapi3filter.Options{AuthenticationFunc: requireBearer},
+			}
+			err = openapi3filter.ValidateRequest(ctx, requestInput)
+			assert.Equal(t, tt.invalidRequest, err != nil, "request validation error: %v", err)
+
+			responseInput := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			}
+			responseInput.SetBodyBytes(w.Body.Bytes())
+			assert.NoError(t, openapi3filter.ValidateResponse(ctx, responseInput))
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+var errInvalidCursor = errors.New("cursor is invalid")
+
+// Meta is pagination info of list response
+type Meta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// sortKeys maps sort field name to the value to sort by, ties are broken by id
+type sortKeys[T any] map[string]func(item T) float64
+
+// cursorID is the id breaking the ties of the sort, the id the api serves the item by, so the cursor
+// never carries the sequential id of a loan or an agreement
+type cursorID interface {
+	int64 | string
+}
+
+// pageRequest is pagination query params: limit, cursor and sort ("field" ascending or "-field" descending)
+type pageRequest struct {
+	Limit      int
+	Cursor     string
+	SortField  string
+	Descending bool
+}
+
+func (p pageRequest) sort() string {
+	if p.Descending {
+		return "-" + p.SortField
+	}
+
+	return p.SortField
+}
+
+func parsePageRequest[T any](query url.Values, keys sortKeys[T], defaultSort string) (pageRequest, error) {
+	page := pageRequest{
+		Limit:  defaultPageLimit,
+		Cursor: query.Get("cursor"),
+	}
+
+	if query.Get("limit") != "" {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 || limit > maxPageLimit {
+			return pageRequest{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	sortParam := query.Get("sort")
+	if sortParam == "" {
+		sortParam = defaultSort
+	}
+	page.Descending = strings.HasPrefix(sortParam, "-")
+	page.SortField = strings.TrimPrefix(sortParam, "-")
+	if _, ok := keys[page.SortField]; !ok {
+		return pageRequest{}, fmt.Errorf("sort field %q is not supported", page.SortField)
+	}
+
+	return page, nil
+}
+
+// paginate sorts the items and returns the page after the cursor, the cursor holds the sort value and id
+// of the last returned item so pages stay stable while items are added
+func paginate[T any, K cursorID](items []T, page pageRequest, keys sortKeys[T], id func(item T) K) ([]T, Meta, error) {
+	key := keys[page.SortField]
+	less := func(aKey float64, aID K, bKey float64, bID K) bool {
+		if page.Descending {
+			aKey, aID, bKey, bID = bKey, bID, aKey, aID
+		}
+		if aKey != bKey {
+			return aKey < bKey
+		}
+
+		return aID < bID
+	}
+
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(key(sorted[i]), id(sorted[i]), key(sorted[j]), id(sorted[j]))
+	})
+
+	start := 0
+	if page.Cursor != "" {
+		cursorKey, cursorID, err := decodeCursor[K](page.Cursor, page.sort())
+		if err != nil {
+			return nil, Meta{}, err
+		}
+
+		start = sort.Search(len(sorted), func(i int) bool {
+			return less(cursorKey, cursorID, key(sorted[i]), id(sorted[i]))
+		})
+	}
+
+	end := start + page.Limit
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+
+	meta := Meta{
+		Total: len(sorted),
+		Limit: page.Limit,
+	}
+	if end < len(sorted) {
+		last := sorted[end-1]
+		meta.NextCursor = encodeCursor(page.sort(), key(last), id(last))
+	}
+
+	return append(make([]T, 0, end-start), sorted[start:end]...), meta, nil
+}
+
+func encodeCursor[K cursorID](sort string, key float64, id K) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s|%s|%v", sort, strconv.FormatFloat(key, 'g', -1, 64), id)))
+}
+
+func decodeCursor[K cursorID](cursor string, sort string) (float64, K, error) {
+	var id K
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, id, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[0] != sort {
+		return 0, id, errInvalidCursor
+	}
+
+	key, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, id, errInvalidCursor
+	}
+
+	switch v := any(&id).(type) {
+	case *int64:
+		*v, err = strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return 0, id, errInvalidCursor
+		}
+	case *string:
+		if parts[2] == "" {
+			return 0, id, errInvalidCursor
+		}
+		*v = parts[2]
+	}
+
+	return key, id, nil
+}
+
+// parseTimeParam parses RFC3339 or date only (2006-01-02) query param, date only "to" param covers the whole day
+func parseTimeParam(value string, isEnd bool) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time %q must be RFC3339 or YYYY-MM-DD", value)
+	}
+	if isEnd {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	return t, nil
+}
+
+// parseIDQuery parses optional int64 query param, zero if empty
+func parseIDQuery(query url.Values, name string) (int64, error) {
+	if query.Get(name) == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(query.Get(name), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+
+	return id, nil
+}
+
+// parseBoolQuery parses optional bool query param, false if empty
+func parseBoolQuery(query url.Values, name string) (bool, error) {
+	if query.Get(name) == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(query.Get(name))
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+
+	return value, nil
+}
+
+// parseCreatedRangeQuery parses optional created_from and created_to query params
+func parseCreatedRangeQuery(query url.Values) (time.Time, time.Time, error) {
+	var createdFrom, createdTo time.Time
+	var err error
+	if query.Get("created_from") != "" {
+		createdFrom, err = parseTimeParam(query.Get("created_from"), false)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if query.Get("created_to") != "" {
+		createdTo, err = parseTimeParam(query.Get("created_to"), true)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	return createdFrom, createdTo, nil
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/model"
+)
+
+// pageLoan is a loan created in loan id order whose public id sorts the other way round
+func pageLoan(loanID int64, principal float64) model.Loan {
+	return model.Loan{
+		LoanID:          loanID,
+		PublicID:        fmt.Sprintf("00000000-0000-4000-8000-%012d", 10-loanID),
+		PrincipalAmount: principal,
+		CreatedAt:       time.Date(2024, 1, 1, 0, 0, int(loanID), 0, time.UTC),
+	}
+}
+
+func publicIDOf(loan model.Loan) string { return loan.PublicID }
+
+func TestPaginate(t *testing.T) {
+	loans := []model.Loan{
+		pageLoan(4, 2000),
+		pageLoan(1, 1000),
+		pageLoan(3, 2000),
+		pageLoan(2, 3000),
+		pageLoan(5, 1000),
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedPages   [][]int64
+		expectedTotal   int
+		isErrorRequest  bool
+		isErrorPaginate bool
+	}{
+		{
+			name:          "default sort by created at",
+			query:         "limit=2",
+			expectedPages: [][]int64{{1, 2}, {3, 4}, {5}},
+			expectedTotal: 5,
+		},
+		{
+			name:          "sort descending by created at",
+			query:         "limit=3&sort=-created_at",
+			expectedPages: [][]int64{{5, 4, 3}, {2, 1}},
+			expectedTotal: 5,
+		},
+		{
+			name:          "ties broken by public id",
+			query:         "limit=2&sort=principal_amount",
+			expectedPages: [][]int64{{5, 1}, {4, 3}, {2}},
+			expectedTotal: 5,
+		},
+		{
+			name:          "ties broken by public id descending",
+			query:         "limit=2&sort=-principal_amount",
+			expectedPages: [][]int64{{2, 3}, {4, 1}, {5}},
+			expectedTotal: 5,
+		},
+		{
+			name:           "error - unknown sort field",
+			query:          "sort=borrower_name",
+			isErrorRequest: true,
+		},
+		{
+			name:           "error - sort by loan id",
+			query:          "sort=loan_id",
+			isErrorRequest: true,
+		},
+		{
+			name:           "error - limit too big",
+			query:          "limit=101",
+			isErrorRequest: true,
+		},
+		{
+			name:            "error - cursor without id",
+			query:           "cursor=" + encodeCursor("created_at", 1, ""),
+			isErrorPaginate: true,
+		},
+		{
+			name:            "error - cursor of another sort",
+			query:           "sort=-created_at&cursor=" + encodeCursor("created_at", 1, pageLoan(1, 1000).PublicID),
+			isErrorPaginate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			page, err := parsePageRequest(query, loanSortKeys, "created_at")
+			assert.Equal(t, tt.isErrorRequest, err != nil)
+			if tt.isErrorRequest {
+				return
+			}
+
+			var pages [][]int64
+			for {
+				result, meta, err := paginate(loans, page, loanSortKeys, publicIDOf)
+				assert.Equal(t, tt.isErrorPaginate, err != nil)
+				if tt.isErrorPaginate {
+					return
+				}
+				assert.Equal(t, tt.expectedTotal, meta.Total)
+
+				var ids []int64
+				for _, v := range result {
+					ids = append(ids, v.LoanID)
+				}
+				pages = append(pages, ids)
+
+				if meta.NextCursor == "" {
+					break
+				}
+				page.Cursor = meta.NextCursor
+			}
+
+			assert.Equal(t, tt.expectedPages, pages)
+		})
+	}
+}
+
+func TestPaginateStableOnInsert(t *testing.T) {
+	page := pageRequest{Limit: 2, SortField: "created_at"}
+
+	_, meta, err := paginate([]model.Loan{pageLoan(1, 0), pageLoan(2, 0), pageLoan(3, 0)}, page, loanSortKeys, publicIDOf)
+	assert.NoError(t, err)
+
+	// a loan created between the two requests does not shift the next page
+	page.Cursor = meta.NextCursor
+	result, _, err := paginate([]model.Loan{pageLoan(1, 0), pageLoan(2, 0), pageLoan(3, 0), pageLoan(4, 0)}, page, loanSortKeys, publicIDOf)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Loan{pageLoan(3, 0), pageLoan(4, 0)}, result)
+}
+
+func TestPaginateCursorHidesLoanID(t *testing.T) {
+	loan := pageLoan(7, 0)
+	_, meta, err := paginate([]model.Loan{loan, pageLoan(8, 0)}, pageRequest{Limit: 1, SortField: "created_at"}, loanSortKeys, publicIDOf)
+	assert.NoError(t, err)
+
+	key, id, err := decodeCursor[string](meta.NextCursor, "created_at")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(loan.CreatedAt.UnixMicro()), key)
+	assert.Equal(t, loan.PublicID, id)
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	result, meta, err := paginate([]model.Loan(nil), pageRequest{Limit: 20, SortField: "created_at"}, loanSortKeys, publicIDOf)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+	assert.Equal(t, Meta{Total: 0, Limit: 20}, meta)
+}
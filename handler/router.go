@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"amartha-test/constant"
+	"amartha-test/openapi"
+)
+
+// Router returns the router of every REST route, versioned under constant.APIVersionPrefix.
+// The request-wide middlewares are not part of it, they are chained around the router in main.go
+func (h *Handler) Router() *mux.Router {
+	router := mux.NewRouter()
+
+	// api specification
+	router.HandleFunc("/openapi.json", h.OpenAPI).Methods("GET")
+
+	// liveness and readiness probes
+	router.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.Readyz).Methods("GET")
+
+	// prometheus metrics
+	if h.Metrics != nil {
+		router.Handle("/metrics", h.Metrics.Handler()).Methods("GET")
+	}
+
+	v1 := router.PathPrefix(constant.APIVersionPrefix).Subrouter()
+
+	// list of user routes
+	v1.HandleFunc("/user/list", h.ListUser).Methods("GET")
+	v1.HandleFunc("/user/{user_id}/detail", h.DetailUser).Methods("GET")
+	v1.HandleFunc("/user/{user_id}/notifications", h.ListUserNotification).Methods("GET")
+
+	// list of loan routes
+	v1.HandleFunc("/loan/list", h.ListLoan).Methods("GET")
+	v1.HandleFunc("/loan/{loan_id}/detail", h.DetailLoan).Methods("GET")
+	v1.HandleFunc("/loan/{loan_id}/events", h.StreamLoanEvents).Methods("GET")
+	v1.HandleFunc("/loan/submit", h.Idempotent(h.SubmitLoan)).Methods("POST")
+	v1.HandleFunc("/loan/{loan_id}/approve", h.Idempotent(h.ApproveLoan)).Methods("POST")
+	v1.HandleFunc("/loan/{loan_id}/proof", h.ViewPictureProof).Methods("GET")
+	v1.HandleFunc("/loan/{loan_id}/invest", h.Idempotent(h.InvestLoan)).Methods("POST")
+	v1.HandleFunc("/loan/{loan_id}/disburse", h.Idempotent(h.DisburseLoan)).Methods("POST")
+
+	// list of employee routes
+	v1.HandleFunc("/employee/{user_id}/tasks", h.ListEmployeeTask).Methods("GET")
+
+	// list of agreement routes
+	v1.HandleFunc("/agreement/list", h.ListAgreement).Methods("GET")
+	v1.HandleFunc("/agreement/{agreement_id}/view", h.ViewAgreement).Methods("GET")
+	v1.HandleFunc("/agreement/{agreement_id}/sign", h.Idempotent(h.SignAgreement)).Methods("POST")
+
+	// list of webhook routes
+	v1.HandleFunc("/webhook/subscribe", h.Idempotent(h.SubscribeWebhook)).Methods("POST")
+	v1.HandleFunc("/webhook/list", h.ListWebhook).Methods("GET")
+	v1.HandleFunc("/webhook/{subscription_id}/unsubscribe", h.UnsubscribeWebhook).Methods("POST")
+	v1.HandleFunc("/webhook/dead-letter/list", h.ListWebhookDeadLetter).Methods("GET")
+	v1.HandleFunc("/webhook/dead-letter/{delivery_id}/retry", h.RetryWebhookDeadLetter).Methods("POST")
+
+	// list of audit routes
+	v1.HandleFunc("/audit/list", h.ListAudit).Methods("GET")
+	v1.HandleFunc("/audit/verify", h.VerifyAudit).Methods("GET")
+
+	return router
+}
+
+// OpenAPI is handler to serve the OpenAPI specification of the REST API
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.Spec)
+}
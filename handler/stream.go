@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// streamHeartbeatInterval is how often an idle stream sends a comment, keeping proxies from closing it
+	streamHeartbeatInterval = 15 * time.Second
+	// streamWriteTimeout is how long a write may wait on a client not reading, the stream is closed after
+	streamWriteTimeout = 10 * time.Second
+)
+
+// CloseStreams ends every open server-sent event stream, it is registered to run on server shutdown
+// since the server does not wait for long-lived responses by itself
+func (h *Handler) CloseStreams() {
+	h.streamsClosed()
+	h.closeStreamsOnce.Do(func() {
+		close(h.streamsDone)
+	})
+}
+
+// streamsClosed returns the channel closed by CloseStreams
+func (h *Handler) streamsClosed() <-chan struct{} {
+	h.initStreamsOnce.Do(func() {
+		h.streamsDone = make(chan struct{})
+	})
+
+	return h.streamsDone
+}
+
+// serverSentEventWriter writes server-sent events, each flushed to the client within streamWriteTimeout
+type serverSentEventWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+func newServerSentEventWriter(w http.ResponseWriter) *serverSentEventWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	return &serverSentEventWriter{
+		w:          w,
+		controller: http.NewResponseController(w),
+	}
+}
+
+// Event writes the event with its json data, the id line is left out when id is empty
+func (s *serverSentEventWriter) Event(id string, name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("event: %s\ndata: %s\n\n", name, payload)
+	if id != "" {
+		content = "id: " + id + "\n" + content
+	}
+
+	return s.write(content)
+}
+
+// Comment writes a comment line, ignored by clients
+func (s *serverSentEventWriter) Comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+func (s *serverSentEventWriter) write(content string) error {
+	err := s.controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	_, err = s.w.Write([]byte(content))
+	if err != nil {
+		return err
+	}
+
+	return s.controller.Flush()
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+var taskSortKeys = sortKeys[model.Task]{
+	"task_id":    func(task model.Task) float64 { return float64(task.TaskID) },
+	"created_at": func(task model.Task) float64 { return float64(task.CreatedAt.UnixMicro()) },
+}
+
+// ListEmployeeTask is handler to get list of field visit tasks assigned to the employee, filterable by status, type and loan_id.
+// The oldest tasks come first by default, they are the longest waiting visits
+func (h *Handler) ListEmployeeTask(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	userID, err := strconv.ParseInt(vars["user_id"], 10, 64)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed parse int", "op", "ListEmployeeTask", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "user_id"))
+		return
+	}
+
+	// 2. get query params
+	query := r.URL.Query()
+	page, err := parsePageRequest(query, taskSortKeys, "created_at")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListEmployeeTask", "user_id", userID, "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	var filter model.TaskFilter
+	if query.Get("status") != "" {
+		filter.Status = constant.GetTaskStatusByDesc(query.Get("status"))
+		if filter.Status == 0 {
+			logging.FromContext(r.Context()).Info("task status is invalid", "op", "ListEmployeeTask", "user_id", userID, "status", query.Get("status"))
+			h.RenderError(w, r, apperror.InvalidRequest.New().WithDetail("field", "status"))
+			return
+		}
+	}
+	if query.Get("type") != "" {
+		filter.TaskType = constant.GetTaskTypeByDesc(query.Get("type"))
+		if filter.TaskType == 0 {
+			logging.FromContext(r.Context()).Info("task type is invalid", "op", "ListEmployeeTask", "user_id", userID, "type", query.Get("type"))
+			h.RenderError(w, r, apperror.InvalidRequest.New().WithDetail("field", "type"))
+			return
+		}
+	}
+	filter.LoanPublicID = query.Get("loan_id")
+
+	// 3. get task list
+	tasks, err := h.Service.ListEmployeeTasks(r.Context(), userID, filter)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
+	}
+
+	// 4. paginate task list
+	result, meta, err := paginate(tasks, page, taskSortKeys, func(task model.Task) int64 { return task.TaskID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListEmployeeTask", "user_id", userID, "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 5. render response
+	h.RenderListResponse(w, r, result, meta)
+}
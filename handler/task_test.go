@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/service"
+)
+
+func TestListEmployeeTask(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		vars         string
+		query        string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - convert string to int64",
+			vars:         "?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid sort",
+			vars:         "4",
+			query:        "?sort=region",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid status",
+			vars:         "4",
+			query:        "?status=done",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid type",
+			vars:         "4",
+			query:        "?type=survey",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - user data not found",
+			vars:         "9",
+			isError:      true,
+			expectedCode: http.StatusNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:         "error - borrower has no tasks",
+			vars:         "1",
+			isError:      true,
+			expectedCode: http.StatusForbidden,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+			},
+		},
+		{
+			name:         "success",
+			vars:         "4",
+			query:        "?status=open&type=validation-visit&loan_id=" + loanPublicID + "&limit=1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetTasksByFilter", mock.Anything, mock.MatchedBy(func(filter model.TaskFilter) bool {
+					return filter.AssigneeID == 4 && filter.Status == constant.TaskStatusOpen && filter.TaskType == constant.TaskTypeValidationVisit && filter.LoanPublicID == loanPublicID
+				})).Return([]model.Task{
+					{TaskID: 2, LoanID: 1, LoanPublicID: loanPublicID, AssigneeID: 4, CreatedAt: time.Now()},
+					{TaskID: 1, LoanID: 1, LoanPublicID: loanPublicID, AssigneeID: 4, CreatedAt: time.Now().Add(-time.Hour)},
+				}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("GET", "employee/4/tasks"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars := map[string]string{"user_id": tt.vars}
+			r = mux.SetURLVars(r, vars)
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.ListEmployeeTask(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				assert.Contains(t, w.Body.String(), `"data":[{"task_id":1,`)
+				// open tasks have no completion time
+				assert.NotContains(t, w.Body.String(), "completed_at")
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -1,26 +1,59 @@
 package handler
 
 import (
-	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+
+	"amartha-test/apperror"
+	"amartha-test/logging"
+	"amartha-test/model"
 )
 
-// ListUser is handler to get list of users
+var userSortKeys = sortKeys[model.User]{
+	"user_id":   func(user model.User) float64 { return float64(user.UserID) },
+	"user_type": func(user model.User) float64 { return float64(user.UserType) },
+}
+
+var notificationSortKeys = sortKeys[model.Notification]{
+	"notification_id": func(notification model.Notification) float64 { return float64(notification.NotificationID) },
+	"created_at":      func(notification model.Notification) float64 { return float64(notification.CreatedAt.UnixMicro()) },
+}
+
+// ListUser is handler to get list of users, filterable by user_type
 func (h *Handler) ListUser(w http.ResponseWriter, r *http.Request) {
-	// 1. get user list
-	users := h.Helper.GetUsers()
-	if len(users) == 0 {
-		log.Println("[ListUser] user list is empty")
-		h.RenderResponse(w, r, "", http.StatusNotFound, "[ListUser] user list is empty")
+	// 1. get query params
+	query := r.URL.Query()
+	page, err := parsePageRequest(query, userSortKeys, "user_id")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListUser", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	var filter model.UserFilter
+	userType, err := parseIDQuery(query, "user_type")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid filter", "op", "ListUser", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("reason", err.Error()))
 		return
 	}
+	filter.UserType = int(userType)
+
+	// 2. get user list
+	users := h.Service.ListUsers(r.Context(), filter)
 
-	// 2. render response
-	h.RenderResponse(w, r, users, http.StatusOK, "")
+	// 3. paginate user list
+	result, meta, err := paginate(users, page, userSortKeys, func(user model.User) int64 { return user.UserID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListUser", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 4. render response
+	h.RenderListResponse(w, r, result, meta)
 }
 
 // DetailUser is handler to get user detail
@@ -29,26 +62,56 @@ func (h *Handler) DetailUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := strconv.ParseInt(vars["user_id"], 10, 64)
 	if err != nil {
-		log.Printf("[DetailUser] failed parse int, with error: %+v", err)
-		h.RenderResponse(w, r, "", http.StatusBadRequest, fmt.Sprintf("[DetailUser] failed parse int, with error: %+v", err))
+		logging.FromContext(r.Context()).Info("failed parse int", "op", "DetailUser", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "user_id"))
+		return
+	}
+
+	// 2. get user by user id
+	user, err := h.Service.GetUser(r.Context(), userID)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
+	}
+
+	// 3. render response
+	h.RenderResponse(w, r, user, http.StatusOK)
+}
+
+// ListUserNotification is handler to get list of notifications in the in-app inbox of the user, newest first by default
+func (h *Handler) ListUserNotification(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	userID, err := strconv.ParseInt(vars["user_id"], 10, 64)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed parse int", "op", "ListUserNotification", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "user_id"))
 		return
 	}
 
-	// 2. sanitize payload
-	if userID == 0 {
-		log.Println("[DetailUser] user id is zero")
-		h.RenderResponse(w, r, "", http.StatusBadRequest, "[DetailUser] user id is zero")
+	// 2. get query params
+	page, err := parsePageRequest(r.URL.Query(), notificationSortKeys, "-created_at")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListUserNotification", "user_id", userID, "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
 		return
 	}
 
-	// 3. get user by user id
-	user := h.Helper.GetUserByUserID(userID)
-	if user.UserID == 0 {
-		log.Printf("[DetailUser][UserID: %d] user data is not found", userID)
-		h.RenderResponse(w, r, "", http.StatusNotFound, fmt.Sprintf("[DetailUser][UserID: %d] user data is not found", userID))
+	// 3. get notification list
+	notifications, err := h.Service.ListNotifications(r.Context(), userID)
+	if err != nil {
+		h.RenderError(w, r, err)
 		return
 	}
 
-	// 4. render response
-	h.RenderResponse(w, r, user, http.StatusOK, "")
+	// 4. paginate notification list
+	result, meta, err := paginate(notifications, page, notificationSortKeys, func(notification model.Notification) int64 { return notification.NotificationID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListUserNotification", "user_id", userID, "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 5. render response
+	h.RenderListResponse(w, r, result, meta)
 }
@@ -14,26 +14,67 @@ import (
 	"amartha-test/constant"
 	"amartha-test/helper/mocks"
 	"amartha-test/model"
+	"amartha-test/service"
 )
 
 func TestListUser(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
 		name         string
+		query        string
 		isError      bool
 		expectedCode int
 		mocks        func()
 	}{
 		{
-			name:         "error - list empty",
+			name:         "error - invalid limit",
+			query:        "?limit=0",
 			isError:      true,
-			expectedCode: http.StatusNotFound,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid sort",
+			query:        "?sort=unknown",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid user type",
+			query:        "?user_type=?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid cursor",
+			query:        "?cursor=invalid",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
 			mocks: func() {
-				mockHelper.On("GetUsers").Return([]model.User{}).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, mock.Anything).Return([]model.User{{UserID: 1}}).Once()
+			},
+		},
+		{
+			name:         "success - with filter",
+			query:        "?user_type=2&sort=-user_id&limit=1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetUsersByFilter", mock.Anything, model.UserFilter{UserType: constant.UserTypeLender}).Return([]model.User{{UserID: 2}, {UserID: 3}}).Once()
+			},
+		},
+		{
+			name:         "success - list empty",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetUsersByFilter", mock.Anything, mock.Anything).Return([]model.User{}).Once()
 			},
 		},
 		{
@@ -41,7 +82,7 @@ func TestListUser(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetUsers").Return([]model.User{
+				mockHelper.On("GetUsersByFilter", mock.Anything, mock.Anything).Return([]model.User{
 					{
 						UserID:   1,
 						UserName: "Septian",
@@ -56,12 +97,11 @@ func TestListUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mocks()
 
-			r, err := http.NewRequest("GET", "/users/list", nil)
+			r, err := http.NewRequest("GET", "/users/list"+tt.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -76,6 +116,9 @@ func TestListUser(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -84,7 +127,7 @@ func TestListUser(t *testing.T) {
 func TestDetailUser(t *testing.T) {
 	mockHelper := new(mocks.IHelper)
 	mockHandler := &Handler{
-		Helper: mockHelper,
+		Service: service.NewService(mockHelper),
 	}
 
 	tests := []struct {
@@ -114,7 +157,7 @@ func TestDetailUser(t *testing.T) {
 			isError:      true,
 			expectedCode: http.StatusNotFound,
 			mocks: func() {
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
 			},
 		},
 		{
@@ -123,7 +166,7 @@ func TestDetailUser(t *testing.T) {
 			isError:      false,
 			expectedCode: http.StatusOK,
 			mocks: func() {
-				mockHelper.On("GetUserByUserID", mock.Anything).Return(model.User{
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{
 					UserID:   1,
 					UserName: "Septian",
 					UserType: constant.UserTypeBorrower,
@@ -143,7 +186,6 @@ func TestDetailUser(t *testing.T) {
 			vars := map[string]string{"user_id": tt.vars}
 			r = mux.SetURLVars(r, vars)
 			startTime := time.Now()
-			time.Sleep(123 * time.Millisecond) // add sleep for simulate latency
 			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
 			r = r.WithContext(ctx)
 			w := httptest.NewRecorder()
@@ -158,6 +200,98 @@ func TestDetailUser(t *testing.T) {
 
 			assert.Equal(t, tt.expectedCode, w.Code)
 			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListUserNotification(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		vars         string
+		query        string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - convert string to int64",
+			vars:         "?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid sort",
+			vars:         "1",
+			query:        "?sort=subject",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - user data not found",
+			vars:         "9",
+			isError:      true,
+			expectedCode: http.StatusNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:         "success",
+			vars:         "1",
+			query:        "?limit=1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1}).Once()
+				mockHelper.On("GetNotificationsByUserID", mock.Anything, int64(1)).Return([]model.Notification{
+					{NotificationID: 1, UserID: 1, CreatedAt: time.Now().Add(-time.Hour)},
+					{NotificationID: 2, UserID: 1, CreatedAt: time.Now()},
+				}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("GET", "user/1/notifications"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars := map[string]string{"user_id": tt.vars}
+			r = mux.SetURLVars(r, vars)
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.ListUserNotification(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			} else {
+				assert.Contains(t, w.Body.String(), `"data":[{"notification_id":2,`)
+			}
 			mockHelper.AssertExpectations(t)
 		})
 	}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"amartha-test/apperror"
+	"amartha-test/auth"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+var webhookSubscriptionSortKeys = sortKeys[model.WebhookSubscription]{
+	"subscription_id": func(subscription model.WebhookSubscription) float64 { return float64(subscription.SubscriptionID) },
+	"created_at": func(subscription model.WebhookSubscription) float64 {
+		return float64(subscription.CreatedAt.UnixMicro())
+	},
+}
+
+var webhookDeliverySortKeys = sortKeys[model.WebhookDelivery]{
+	"delivery_id": func(delivery model.WebhookDelivery) float64 { return float64(delivery.DeliveryID) },
+	"failed_at":   func(delivery model.WebhookDelivery) float64 { return float64(delivery.FailedAt.UnixMicro()) },
+}
+
+// SubscribeWebhook is handler to subscribe an url to events, the response carries the signing secret, only for an authenticated user
+func (h *Handler) SubscribeWebhook(w http.ResponseWriter, r *http.Request) {
+	// 1. decode body
+	var body model.WebhookSubscription
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("fail decode body", "op", "SubscribeWebhook", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "body"))
+		return
+	}
+
+	// 2. subscribe webhook of authenticated user, zero when anonymous
+	userID, _ := auth.UserIDFrom(r.Context())
+	subscription, err := h.Service.Subscribe(r.Context(), userID, body.URL, body.EventTypes, body.Secret)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
+	}
+
+	// 3. render response
+	h.RenderResponse(w, r, subscription, http.StatusCreated)
+}
+
+// ListWebhook is handler to get list of webhook subscriptions
+func (h *Handler) ListWebhook(w http.ResponseWriter, r *http.Request) {
+	// 1. get query params
+	page, err := parsePageRequest(r.URL.Query(), webhookSubscriptionSortKeys, "subscription_id")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListWebhook", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	// 2. get subscription list
+	subscriptions := h.Service.ListSubscriptions(r.Context())
+
+	// 3. paginate subscription list
+	result, meta, err := paginate(subscriptions, page, webhookSubscriptionSortKeys, func(subscription model.WebhookSubscription) int64 { return subscription.SubscriptionID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListWebhook", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 4. render response
+	h.RenderListResponse(w, r, result, meta)
+}
+
+// UnsubscribeWebhook is handler to delete webhook subscription
+func (h *Handler) UnsubscribeWebhook(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	subscriptionID, err := strconv.ParseInt(vars["subscription_id"], 10, 64)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed parse int", "op", "UnsubscribeWebhook", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "subscription_id"))
+		return
+	}
+
+	// 2. unsubscribe webhook
+	subscription, err := h.Service.Unsubscribe(r.Context(), subscriptionID)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
+	}
+
+	// 3. render response
+	h.RenderResponse(w, r, subscription, http.StatusOK)
+}
+
+// ListWebhookDeadLetter is handler to get list of webhook deliveries that failed every attempt
+func (h *Handler) ListWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	// 1. get query params
+	page, err := parsePageRequest(r.URL.Query(), webhookDeliverySortKeys, "delivery_id")
+	if err != nil {
+		logging.FromContext(r.Context()).Info("invalid pagination", "op", "ListWebhookDeadLetter", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("reason", err.Error()))
+		return
+	}
+
+	// 2. get dead letter list
+	deliveries := h.Service.ListDeadLetters(r.Context())
+
+	// 3. paginate dead letter list
+	result, meta, err := paginate(deliveries, page, webhookDeliverySortKeys, func(delivery model.WebhookDelivery) int64 { return delivery.DeliveryID })
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed paginate", "op", "ListWebhookDeadLetter", "error", err)
+		h.RenderError(w, r, apperror.InvalidPagination.Wrap(err).WithDetail("field", "cursor"))
+		return
+	}
+
+	// 4. render response
+	h.RenderListResponse(w, r, result, meta)
+}
+
+// RetryWebhookDeadLetter is handler to queue the dead-lettered webhook delivery again
+func (h *Handler) RetryWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	// 1. get vars
+	vars := mux.Vars(r)
+	deliveryID, err := strconv.ParseInt(vars["delivery_id"], 10, 64)
+	if err != nil {
+		logging.FromContext(r.Context()).Info("failed parse int", "op", "RetryWebhookDeadLetter", "error", err)
+		h.RenderError(w, r, apperror.InvalidRequest.Wrap(err).WithDetail("field", "delivery_id"))
+		return
+	}
+
+	// 2. retry dead letter
+	delivery, err := h.Service.RetryDeadLetter(r.Context(), deliveryID)
+	if err != nil {
+		h.RenderError(w, r, err)
+		return
+	}
+
+	// 3. render response
+	h.RenderResponse(w, r, delivery, http.StatusAccepted)
+}
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/auth"
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/service"
+	"amartha-test/webhook"
+)
+
+func TestSubscribeWebhook(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		userID       int64
+		body         string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - decode body",
+			body:         `{`,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - user is not identified",
+			body:         `{"url":"https://93.184.215.14/events"}`,
+			isError:      true,
+			expectedCode: http.StatusUnauthorized,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - invalid url",
+			userID:       5,
+			body:         `{"url":"receiver.example.com"}`,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5}).Once()
+			},
+		},
+		{
+			name:         "error - private url",
+			userID:       5,
+			body:         `{"url":"http://169.254.169.254/latest/meta-data/"}`,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5}).Once()
+			},
+		},
+		{
+			name:         "error - unknown event type",
+			userID:       5,
+			body:         `{"url":"https://93.184.215.14/events","event_types":["loan.deleted"]}`,
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5}).Once()
+			},
+		},
+		{
+			name:         "success",
+			userID:       5,
+			body:         `{"url":"https://93.184.215.14/events","event_types":["loan.invested"]}`,
+			isError:      false,
+			expectedCode: http.StatusCreated,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5}).Once()
+				mockHelper.On("GenerateIncrementalWebhookSubscriptionID", mock.Anything).Return(int64(1)).Once()
+				mockHelper.On("UpsertWebhookSubscription", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("POST", "/webhook/subscribe", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			if tt.userID != 0 {
+				ctx = auth.WithUserID(ctx, tt.userID)
+			}
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.SubscribeWebhook(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListWebhook(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - invalid sort",
+			query:        "?sort=url",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "success",
+			query:        "?sort=-subscription_id&limit=1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetWebhookSubscriptions", mock.Anything).Return([]model.WebhookSubscription{{SubscriptionID: 1}, {SubscriptionID: 2}}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("GET", "/webhook/list"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.ListWebhook(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnsubscribeWebhook(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHandler := &Handler{
+		Service: service.NewService(mockHelper),
+	}
+
+	tests := []struct {
+		name         string
+		vars         string
+		isError      bool
+		expectedCode int
+		mocks        func()
+	}{
+		{
+			name:         "error - convert string to int64",
+			vars:         "?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - subscription data not found",
+			vars:         "9",
+			isError:      true,
+			expectedCode: http.StatusNotFound,
+			mocks: func() {
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(9)).Return(model.WebhookSubscription{}).Once()
+			},
+		},
+		{
+			name:         "success",
+			vars:         "1",
+			isError:      false,
+			expectedCode: http.StatusOK,
+			mocks: func() {
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1}).Once()
+				mockHelper.On("DeleteWebhookSubscription", mock.Anything, int64(1)).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			r, err := http.NewRequest("POST", "/webhook/1/unsubscribe", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars := map[string]string{"subscription_id": tt.vars}
+			r = mux.SetURLVars(r, vars)
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.UnsubscribeWebhook(w, r)
+
+			isErr := false
+			if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetryWebhookDeadLetter(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := service.NewService(mockHelper)
+	mockHandler := &Handler{
+		Service: svc,
+	}
+
+	tests := []struct {
+		name         string
+		vars         string
+		isError      bool
+		expectedCode int
+		dispatcher   webhook.IDispatcher
+		mocks        func()
+	}{
+		{
+			name:         "error - convert string to int64",
+			vars:         "?",
+			isError:      true,
+			expectedCode: http.StatusBadRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - dead letter data not found",
+			vars:         "9",
+			isError:      true,
+			expectedCode: http.StatusNotFound,
+			mocks: func() {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(9)).Return(model.WebhookDelivery{}).Once()
+			},
+		},
+		{
+			name:         "error - dispatcher not configured",
+			vars:         "3",
+			isError:      true,
+			expectedCode: http.StatusInternalServerError,
+			mocks: func() {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(3)).Return(model.WebhookDelivery{DeliveryID: 3, SubscriptionID: 1}).Once()
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1}).Once()
+			},
+		},
+		{
+			name:         "success",
+			vars:         "3",
+			isError:      false,
+			expectedCode: http.StatusAccepted,
+			dispatcher:   webhook.NewDispatcher(mockHelper),
+			mocks: func() {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(3)).Return(model.WebhookDelivery{DeliveryID: 3, SubscriptionID: 1}).Once()
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1}).Twice()
+				mockHelper.On("DeleteWebhookDeadLetter", mock.Anything, int64(3)).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+			svc.Webhooks = tt.dispatcher
+
+			r, err := http.NewRequest("POST", "/webhook/dead-letter/3/retry", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars := map[string]string{"delivery_id": tt.vars}
+			r = mux.SetURLVars(r, vars)
+			startTime := time.Now()
+			ctx := context.WithValue(r.Context(), constant.CtxStartTimeKey, startTime)
+			r = r.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			// main func
+			mockHandler.RetryWebhookDeadLetter(w, r)
+
+			isErr := false
+			if w.Code != http.StatusAccepted {
+				isErr = true
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.isError, isErr)
+			if tt.isError {
+				assertRegisteredError(t, w)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -1,19 +1,21 @@
 package helper
 
 import (
-	"bytes"
-	"encoding/base64"
+	"context"
 	"errors"
-	"fmt"
-	"log"
-	"strconv"
-	"strings"
 	"sync"
+	"time"
 
-	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 
+	"amartha-test/apperror"
 	"amartha-test/constant"
+	"amartha-test/document"
+	"amartha-test/logging"
 	"amartha-test/model"
+	"amartha-test/storage"
+	"amartha-test/tracing"
 )
 
 var (
@@ -23,18 +25,26 @@ var (
 	agreements = make(map[int64]*model.Aggrement)
 )
 
-func (h *Helper) GenerateIncrementalAgreementID() int64 {
+func (h *Helper) GenerateIncrementalAgreementID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalAgreementID")
+	defer span.End()
 	mutexAgreement.Lock()
 	defer mutexAgreement.Unlock()
 	agreementIDCounter++
 	return agreementIDCounter
 }
 
-func (h *Helper) UpsertAgreement(agreement model.Aggrement) {
+func (h *Helper) UpsertAgreement(ctx context.Context, agreement model.Aggrement) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertAgreement", attribute.Int64("agreement.id", agreement.AggrementID))
+	defer span.End()
+	before := agreements[agreement.AggrementID]
 	agreements[agreement.AggrementID] = &agreement
+	h.recordAudit(ctx, AuditTargetAgreement, agreement.PublicID, before, agreement)
 }
 
-func (h *Helper) GetAgreements() []model.Aggrement {
+func (h *Helper) GetAgreements(ctx context.Context) []model.Aggrement {
+	_, span := tracing.Start(ctx, "helper.GetAgreements")
+	defer span.End()
 	var listAgreement []model.Aggrement
 	for _, v := range agreements {
 		listAgreement = append(listAgreement, *v)
@@ -43,7 +53,22 @@ func (h *Helper) GetAgreements() []model.Aggrement {
 	return listAgreement
 }
 
-func (h *Helper) GetAgreementByAgreementID(agreementID int64) model.Aggrement {
+func (h *Helper) GetAgreementsByFilter(ctx context.Context, filter model.AgreementFilter) []model.Aggrement {
+	_, span := tracing.Start(ctx, "helper.GetAgreementsByFilter")
+	defer span.End()
+	var listAgreement []model.Aggrement
+	for _, v := range agreements {
+		if filter.Match(*v) {
+			listAgreement = append(listAgreement, *v)
+		}
+	}
+
+	return listAgreement
+}
+
+func (h *Helper) GetAgreementByAgreementID(ctx context.Context, agreementID int64) model.Aggrement {
+	_, span := tracing.Start(ctx, "helper.GetAgreementByAgreementID", attribute.Int64("agreement.id", agreementID))
+	defer span.End()
 	agreement, exists := agreements[agreementID]
 	if exists {
 		return *agreement
@@ -52,239 +77,259 @@ func (h *Helper) GetAgreementByAgreementID(agreementID int64) model.Aggrement {
 	return model.Aggrement{}
 }
 
-func (h *Helper) GenerateBorrowerAgreementPDF(loan *model.Loan) error {
-	borrower := h.GetUserByUserID(loan.BorrowerID)
+// GetAgreementByPublicID returns the agreement of the public id the api identifies it by
+func (h *Helper) GetAgreementByPublicID(ctx context.Context, publicID string) model.Aggrement {
+	_, span := tracing.Start(ctx, "helper.GetAgreementByPublicID")
+	defer span.End()
+	for _, v := range agreements {
+		if v.PublicID == publicID {
+			return *v
+		}
+	}
+
+	return model.Aggrement{}
+}
+
+func (h *Helper) GenerateBorrowerAgreementPDF(ctx context.Context, loan *model.Loan) (err error) {
+	ctx, span := tracing.Start(ctx, "helper.GenerateBorrowerAgreementPDF", attribute.Int64("loan.id", loan.LoanID))
+	defer func() { tracing.End(span, err) }()
+
+	borrower := h.GetUserByUserID(ctx, loan.BorrowerID)
 	if borrower.UserID == 0 {
-		log.Println("[GenerateAgreementPDF] borrower is not found")
-		return errors.New("borrower is not found")
+		logging.FromContext(ctx).Warn("borrower is not found", "op", "GenerateAgreementPDF")
+		return apperror.UserNotFound.New().WithDetail("user_id", loan.BorrowerID)
 	}
 
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(40, 10, fmt.Sprintf("ORGANIZER-BORROWER AGREEMENT [Loan ID: %d]", loan.LoanID))
-	pdf.Ln(20)
-	pdf.Cell(40, 10, fmt.Sprintf("Borrower ID: %d", borrower.UserID))
-	pdf.Ln(5)
-	pdf.Cell(40, 10, fmt.Sprintf("Borrower Name: %s", borrower.UserName))
-	pdf.Ln(5)
-	pdf.Cell(40, 10, fmt.Sprintf("Principal Amount: Rp %.2f", loan.PrincipalAmount))
-	pdf.Ln(5)
-	pdf.Cell(40, 10, fmt.Sprintf("Interest Rate: %.2f%%", loan.InterestRate*100))
-	pdf.Ln(5)
-	pdf.Cell(40, 10, fmt.Sprintf("Amount of debt: Rp %.2f", loan.CalculateReturnAmount()))
-	pdf.Ln(20)
-	pdf.Cell(40, 10, "Sign: UNSIGNED")
-	pdf.Ln(5)
-
-	var buf bytes.Buffer
-	err := pdf.Output(&buf)
+	organizerBorrowerAgreement, err := h.createAgreement(ctx, model.Aggrement{
+		LoanID:        loan.LoanID,
+		LoanPublicID:  loan.PublicID,
+		UserID:        borrower.UserID,
+		AgreementType: constant.AgreementTypeOrganizerBorrower,
+	}, document.TemplateOrganizerBorrower, document.AgreementData{
+		Loan:     *loan,
+		Borrower: borrower,
+		Locale:   borrower.Locale,
+	})
 	if err != nil {
-		log.Printf("[GenerateAgreementPDF] failed generate organizer-borrower agreement with error: %+v", err)
+		logging.FromContext(ctx).Error("failed generate organizer-borrower agreement", "op", "GenerateAgreementPDF", "error", err)
 		return err
 	}
 
-	organizerBorrowerAgreement := model.Aggrement{
-		AggrementID:  h.GenerateIncrementalAgreementID(),
-		DocumentData: base64.StdEncoding.EncodeToString(buf.Bytes()),
-		UserID:       borrower.UserID,
-	}
-	h.UpsertAgreement(organizerBorrowerAgreement)
-
-	loan.OrganizerBorrowerAggrementURL = fmt.Sprintf(constant.AgreementPrefix, organizerBorrowerAgreement.AggrementID)
-	h.UpsertLoan(*loan)
+	loan.OrganizerBorrowerAggrementURL = h.Config.AgreementURL(organizerBorrowerAgreement.PublicID)
+	h.UpsertLoan(ctx, *loan)
 
 	return nil
 }
 
-func (h *Helper) GenerateLenderAgreementPDF(loan *model.Loan) error {
-	borrower := h.GetUserByUserID(loan.BorrowerID)
+// GenerateLenderAgreementPDF renders one organizer-lender agreement per lender, the span tells how long a heavily
+// syndicated loan takes
+func (h *Helper) GenerateLenderAgreementPDF(ctx context.Context, loan *model.Loan) (err error) {
+	ctx, span := tracing.Start(ctx, "helper.GenerateLenderAgreementPDF", attribute.Int64("loan.id", loan.LoanID),
+		attribute.Int("loan.lender_count", len(loan.Lending)))
+	defer func() { tracing.End(span, err) }()
+
+	borrower := h.GetUserByUserID(ctx, loan.BorrowerID)
 	if borrower.UserID == 0 {
-		log.Println("[GenerateAgreementPDF] borrower is not found")
-		return errors.New("borrower is not found")
+		logging.FromContext(ctx).Warn("borrower is not found", "op", "GenerateAgreementPDF")
+		return apperror.UserNotFound.New().WithDetail("user_id", loan.BorrowerID)
 	}
 
 	for i := 0; i < len(loan.Lending); i++ {
-		lender := h.GetUserByUserID(loan.Lending[i].LenderID)
+		lender := h.GetUserByUserID(ctx, loan.Lending[i].LenderID)
 		if lender.UserID == 0 {
-			log.Println("[GenerateAgreementPDF] lender is not found")
-			return errors.New("lender is not found")
+			logging.FromContext(ctx).Warn("lender is not found", "op", "GenerateAgreementPDF")
+			return apperror.UserNotFound.New().WithDetail("user_id", loan.Lending[i].LenderID)
 		}
 
-		pdf := gofpdf.New("P", "mm", "A4", "")
-		pdf.AddPage()
-		pdf.SetFont("Arial", "B", 16)
-		pdf.Cell(40, 10, fmt.Sprintf("ORGANIZER-LENDER AGREEMENT [Loan ID: %d]", loan.LoanID))
-		pdf.Ln(20)
-		pdf.Cell(40, 10, fmt.Sprintf("Borrower ID: %d", loan.BorrowerID))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Borrower Name: %s", borrower.UserName))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Principal Amount: Rp %.2f", loan.PrincipalAmount))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Interest Rate: %.2f%%", loan.InterestRate*100))
-		pdf.Ln(10)
-		pdf.Cell(40, 10, fmt.Sprintf("Lender ID: %d", lender.UserID))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Lender Name: %s", lender.UserName))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Invested Amount: Rp %.2f", loan.Lending[i].InvestedAmount))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Return Amountt: Rp %.2f", loan.Lending[i].ReturnAmount))
-		pdf.Ln(20)
-		pdf.Cell(40, 10, "Sign: UNSIGNED")
-		pdf.Ln(5)
-
-		var buf bytes.Buffer
-		err := pdf.Output(&buf)
+		organizerLenderAgreement, err := h.createAgreement(ctx, model.Aggrement{
+			LoanID:        loan.LoanID,
+			LoanPublicID:  loan.PublicID,
+			UserID:        lender.UserID,
+			AgreementType: constant.AgreementTypeOrganizerLender,
+		}, document.TemplateOrganizerLender, document.AgreementData{
+			Loan:     *loan,
+			Borrower: borrower,
+			Lender:   lender,
+			Lending:  loan.Lending[i],
+			Locale:   lender.Locale,
+		})
 		if err != nil {
-			log.Printf("[GenerateAgreementPDF] failed generate organizer-lender agreement with error: %+v", err)
+			logging.FromContext(ctx).Error("failed generate organizer-lender agreement", "op", "GenerateAgreementPDF", "error", err)
 			return err
 		}
 
-		organizerLenderAgreement := model.Aggrement{
-			AggrementID:  h.GenerateIncrementalAgreementID(),
-			DocumentData: base64.StdEncoding.EncodeToString(buf.Bytes()),
-			UserID:       lender.UserID,
-		}
-		h.UpsertAgreement(organizerLenderAgreement)
-
-		loan.Lending[i].OrganizerLenderAggrementURL = fmt.Sprintf(constant.AgreementPrefix, organizerLenderAgreement.AggrementID)
+		loan.Lending[i].OrganizerLenderAggrementURL = h.Config.AgreementURL(organizerLenderAgreement.PublicID)
 	}
 
-	h.UpsertLoan(*loan)
+	h.UpsertLoan(ctx, *loan)
 
 	return nil
 }
 
-func (h *Helper) GenerateSignedAgreementPDF(loan *model.Loan, userID int64) error {
-	tmpURL := ""
+func (h *Helper) GenerateSignedAgreementPDF(ctx context.Context, loan *model.Loan, agreement model.Aggrement) (err error) {
+	ctx, span := tracing.Start(ctx, "helper.GenerateSignedAgreementPDF", attribute.Int64("loan.id", loan.LoanID),
+		attribute.Int64("agreement.id", agreement.AggrementID))
+	defer func() { tracing.End(span, err) }()
 
-	borrower := h.GetUserByUserID(loan.BorrowerID)
+	borrower := h.GetUserByUserID(ctx, loan.BorrowerID)
 	if borrower.UserID == 0 {
-		log.Println("[GenerateSignedAgreementPDF] borrower is not found")
-		return errors.New("borrower is not found")
+		logging.FromContext(ctx).Warn("borrower is not found", "op", "GenerateSignedAgreementPDF")
+		return apperror.UserNotFound.New().WithDetail("user_id", loan.BorrowerID)
 	}
 
-	if userID == loan.BorrowerID {
+	signedAgreement := model.Aggrement{
+		LoanID:        loan.LoanID,
+		LoanPublicID:  loan.PublicID,
+		UserID:        agreement.UserID,
+		AgreementType: constant.AgreementTypeSignedCopy,
+		SupersedesID:  agreement.AggrementID,
+		// served in place of the sequential id
+		SupersedesPublicID: agreement.PublicID,
+	}
+
+	switch agreement.AgreementType {
+	case constant.AgreementTypeOrganizerBorrower:
 		// 1. organizer borrower agreement signed
-		pdf := gofpdf.New("P", "mm", "A4", "")
-		pdf.AddPage()
-		pdf.SetFont("Arial", "B", 16)
-		pdf.Cell(40, 10, fmt.Sprintf("ORGANIZER-BORROWER AGREEMENT [Loan ID: %d]", loan.LoanID))
-		pdf.Ln(20)
-		pdf.Cell(40, 10, fmt.Sprintf("Borrower ID: %d", borrower.UserID))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Borrower Name: %s", borrower.UserName))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Principal Amount: Rp %.2f", loan.PrincipalAmount))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Interest Rate: %.2f%%", loan.InterestRate*100))
-		pdf.Ln(5)
-		pdf.Cell(40, 10, fmt.Sprintf("Amount of debt: Rp %.2f", loan.CalculateReturnAmount()))
-		pdf.Ln(20)
-		pdf.Cell(40, 10, "Sign: SIGNED")
-		pdf.Ln(5)
-
-		var buf bytes.Buffer
-		err := pdf.Output(&buf)
+		signedAgreement, err = h.createAgreement(ctx, signedAgreement, document.TemplateOrganizerBorrower, document.AgreementData{
+			Loan:     *loan,
+			Borrower: borrower,
+			Signed:   true,
+			Locale:   borrower.Locale,
+		})
 		if err != nil {
-			log.Printf("[GenerateSignedAgreementPDF] failed generate organizer-borrower agreement signed with error: %+v", err)
+			logging.FromContext(ctx).Error("failed generate organizer-borrower agreement signed", "op", "GenerateSignedAgreementPDF", "error", err)
 			return err
 		}
-
-		organizerBorrowerAgreement := model.Aggrement{
-			AggrementID:  h.GenerateIncrementalAgreementID(),
-			DocumentData: base64.StdEncoding.EncodeToString(buf.Bytes()),
-			UserID:       borrower.UserID,
-			IsSigned:     true,
+	case constant.AgreementTypeOrganizerLender:
+		// 2. organizer lender agreement signed
+		lender := h.GetUserByUserID(ctx, agreement.UserID)
+		if lender.UserID == 0 {
+			logging.FromContext(ctx).Warn("lender is not found", "op", "GenerateSignedAgreementPDF")
+			return apperror.UserNotFound.New().WithDetail("user_id", agreement.UserID)
 		}
-		h.UpsertAgreement(organizerBorrowerAgreement)
-
-		tmpURL = fmt.Sprintf(constant.AgreementPrefix, organizerBorrowerAgreement.AggrementID)
-	} else {
-		// 2. organizer lender agreement
-		for i := 0; i < len(loan.Lending); i++ {
-			if userID == loan.Lending[i].LenderID {
-				lender := h.GetUserByUserID(loan.Lending[i].LenderID)
-				if lender.UserID == 0 {
-					log.Println("[GenerateSignedAgreementPDF] lender is not found")
-					return errors.New("lender is not found")
-				}
-
-				pdf := gofpdf.New("P", "mm", "A4", "")
-				pdf.AddPage()
-				pdf.SetFont("Arial", "B", 16)
-				pdf.Cell(40, 10, fmt.Sprintf("ORGANIZER-LENDER AGREEMENT [Loan ID: %d]", loan.LoanID))
-				pdf.Ln(20)
-				pdf.Cell(40, 10, fmt.Sprintf("Borrower ID: %d", loan.BorrowerID))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Borrower Name: %s", borrower.UserName))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Principal Amount: Rp %.2f", loan.PrincipalAmount))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Interest Rate: %.2f%%", loan.InterestRate*100))
-				pdf.Ln(10)
-				pdf.Cell(40, 10, fmt.Sprintf("Lender ID: %d", lender.UserID))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Lender Name: %s", lender.UserName))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Invested Amount: Rp %.2f", loan.Lending[i].InvestedAmount))
-				pdf.Ln(5)
-				pdf.Cell(40, 10, fmt.Sprintf("Return Amountt: Rp %.2f", loan.Lending[i].ReturnAmount))
-				pdf.Ln(20)
-				pdf.Cell(40, 10, "Sign: SIGNED")
-				pdf.Ln(5)
-
-				var buf bytes.Buffer
-				err := pdf.Output(&buf)
-				if err != nil {
-					log.Printf("[GenerateSignedAgreementPDF] failed generate organizer-lender agreement signed with error: %+v", err)
-					return err
-				}
-
-				organizerLenderAgreement := model.Aggrement{
-					AggrementID:  h.GenerateIncrementalAgreementID(),
-					DocumentData: base64.StdEncoding.EncodeToString(buf.Bytes()),
-					UserID:       lender.UserID,
-					IsSigned:     true,
-				}
-				h.UpsertAgreement(organizerLenderAgreement)
-
-				tmpURL = fmt.Sprintf(constant.AgreementPrefix, organizerLenderAgreement.AggrementID)
+
+		var lending model.Lending
+		for _, v := range loan.Lending {
+			if v.LenderID == lender.UserID {
+				lending = v
 			}
 		}
-	}
 
-	if tmpURL != "" {
-		loan.DisbursementInfo.AgreementSignedURLs = append(loan.DisbursementInfo.AgreementSignedURLs, tmpURL)
-		h.UpsertLoan(*loan)
+		signedAgreement, err = h.createAgreement(ctx, signedAgreement, document.TemplateOrganizerLender, document.AgreementData{
+			Loan:     *loan,
+			Borrower: borrower,
+			Lender:   lender,
+			Lending:  lending,
+			Signed:   true,
+			Locale:   lender.Locale,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("failed generate organizer-lender agreement signed", "op", "GenerateSignedAgreementPDF", "error", err)
+			return err
+		}
+	default:
+		logging.FromContext(ctx).Info("agreement type can not be signed", "op", "GenerateSignedAgreementPDF", "agreement_type", constant.GetAgreementTypeDesc(agreement.AgreementType))
+		return apperror.AgreementNotSignable.New().WithDetail("agreement_id", agreement.PublicID)
 	}
 
+	loan.DisbursementInfo.AgreementSignedURLs = append(loan.DisbursementInfo.AgreementSignedURLs, h.Config.AgreementURL(signedAgreement.PublicID))
+	h.UpsertLoan(ctx, *loan)
+
 	return nil
 }
 
-func (h *Helper) CheckAgreementCompletelySignedByLender(loan model.Loan) (bool, error) {
+// createAgreement renders the current version of the named template in the signer's locale and stores it as a new agreement
+func (h *Helper) createAgreement(ctx context.Context, agreement model.Aggrement, templateName string, data document.AgreementData) (model.Aggrement, error) {
+	data.Locale = document.NormalizeLocale(data.Locale)
+	if data.Date.IsZero() {
+		data.Date = time.Now()
+	}
+
+	pdfData, err := renderPDF(ctx, templateName, data)
+	if err != nil {
+		return model.Aggrement{}, err
+	}
+
+	documentKey, err := h.putDocument(ctx, pdfData)
+	if err != nil {
+		return model.Aggrement{}, err
+	}
+
+	agreement.AggrementID = h.GenerateIncrementalAgreementID(ctx)
+	agreement.PublicID = uuid.NewString()
+	agreement.AgreementTypeDesc = constant.GetAgreementTypeDesc(agreement.AgreementType)
+	agreement.DocumentKey = documentKey
+	agreement.IsSigned = data.Signed
+	agreement.TemplateVersion = document.CurrentVersion
+	agreement.Locale = data.Locale
+	agreement.CreatedAt = data.Date
+	if data.Signed {
+		signedAt := data.Date
+		agreement.SignedAt = &signedAt
+	}
+	h.UpsertAgreement(ctx, agreement)
+
+	return agreement, nil
+}
+
+// renderPDF renders the current version of the named template in a span of its own
+func renderPDF(ctx context.Context, templateName string, data document.AgreementData) ([]byte, error) {
+	_, span := tracing.Start(ctx, "document.RenderPDF",
+		attribute.String("document.template", templateName),
+		attribute.String("document.version", document.CurrentVersion),
+		attribute.String("document.locale", data.Locale))
+
+	pdfData, err := document.RenderPDF(templateName, document.CurrentVersion, data)
+	span.SetAttributes(attribute.Int("document.size_bytes", len(pdfData)))
+	tracing.End(span, err)
+
+	return pdfData, err
+}
+
+// putDocument stores the rendered document in a span of its own
+func (h *Helper) putDocument(ctx context.Context, pdfData []byte) (string, error) {
+	ctx, span := tracing.Start(ctx, "storage.Put", attribute.Int("document.size_bytes", len(pdfData)))
+
+	documentKey, err := h.DocumentStore.Put(ctx, pdfData)
+	tracing.End(span, err)
+
+	return documentKey, err
+}
+
+func (h *Helper) CheckAgreementCompletelySignedByLender(ctx context.Context, loan model.Loan) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "helper.CheckAgreementCompletelySignedByLender", attribute.Int64("loan.id", loan.LoanID))
+	defer func() { tracing.End(span, err) }()
+
 	for _, v := range loan.Lending {
-		organizerLenderAgreementID, err := h.GetAgreementIDByAgreementURL(v.OrganizerLenderAggrementURL)
-		if err != nil {
-			log.Printf("[CheckLoanCompletelySigned] failed get organizer-lender agreement id with error: %+v", err)
-			return false, err
+		organizerLenderAgreements := h.GetAgreementsByFilter(ctx, model.AgreementFilter{
+			LoanID:        loan.LoanID,
+			UserID:        v.LenderID,
+			AgreementType: constant.AgreementTypeOrganizerLender,
+		})
+		if len(organizerLenderAgreements) == 0 {
+			logging.FromContext(ctx).Info("organizer-lender agreement is not found", "op", "CheckLoanCompletelySigned", "loan_id", loan.LoanID, "lender_id", v.LenderID)
+			return false, apperror.AgreementNotFound.New().WithDetail("loan_id", loan.PublicID).WithDetail("user_id", v.LenderID)
 		}
 
-		organizerLenderAgreement := h.GetAgreementByAgreementID(organizerLenderAgreementID)
-		if !organizerLenderAgreement.IsSigned {
-			log.Printf("[CheckLoanCompletelySigned] agremeent with agreement id: %d in loan id: %d is still unsigned", organizerLenderAgreement.AggrementID, loan.LoanID)
-			return false, nil
+		for _, organizerLenderAgreement := range organizerLenderAgreements {
+			if !organizerLenderAgreement.IsSigned {
+				logging.FromContext(ctx).Info("agreement is still unsigned", "op", "CheckLoanCompletelySigned", "loan_id", loan.LoanID, "agreement_id", organizerLenderAgreement.AggrementID)
+				return false, nil
+			}
 		}
 	}
 
 	return true, nil
 }
 
-func (h *Helper) GetAgreementIDByAgreementURL(url string) (int64, error) {
-	parts := strings.Split(url, "/")
-	agreementIDString := parts[len(parts)-2]
+func (h *Helper) OpenAgreementDocument(ctx context.Context, agreement model.Aggrement) (_ storage.Document, err error) {
+	ctx, span := tracing.Start(ctx, "helper.OpenAgreementDocument", attribute.Int64("agreement.id", agreement.AggrementID))
+	defer func() { tracing.End(span, err) }()
+
+	document, err := h.DocumentStore.Open(ctx, agreement.DocumentKey)
+	if errors.Is(err, storage.ErrDocumentNotFound) || errors.Is(err, storage.ErrInvalidDocumentKey) {
+		return storage.Document{}, apperror.DocumentNotFound.Wrap(err).WithDetail("agreement_id", agreement.PublicID)
+	}
 
-	return strconv.ParseInt(agreementIDString, 10, 64)
+	return document, err
 }
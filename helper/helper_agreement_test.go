@@ -0,0 +1,188 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"amartha-test/config"
+	"amartha-test/constant"
+	"amartha-test/document"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+func TestGetAgreementsByFilter(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("get agreements by filter", func(t *testing.T) {
+		loanID := int64(9001)
+		helper.UpsertAgreement(context.Background(), model.Aggrement{AggrementID: helper.GenerateIncrementalAgreementID(context.Background()), LoanID: loanID, UserID: 9002, AgreementType: constant.AgreementTypeOrganizerLender})
+		helper.UpsertAgreement(context.Background(), model.Aggrement{AggrementID: helper.GenerateIncrementalAgreementID(context.Background()), LoanID: loanID, UserID: 9003, AgreementType: constant.AgreementTypeOrganizerLender})
+		helper.UpsertAgreement(context.Background(), model.Aggrement{AggrementID: helper.GenerateIncrementalAgreementID(context.Background()), LoanID: loanID, UserID: 9001, AgreementType: constant.AgreementTypeOrganizerBorrower})
+
+		if got := len(helper.GetAgreementsByFilter(context.Background(), model.AgreementFilter{LoanID: loanID})); got != 3 {
+			t.Errorf("expected 3 agreements of loan, got %d", got)
+		}
+		if got := len(helper.GetAgreementsByFilter(context.Background(), model.AgreementFilter{LoanID: loanID, AgreementType: constant.AgreementTypeOrganizerLender})); got != 2 {
+			t.Errorf("expected 2 organizer-lender agreements, got %d", got)
+		}
+		if got := len(helper.GetAgreementsByFilter(context.Background(), model.AgreementFilter{LoanID: loanID, UserID: 9003})); got != 1 {
+			t.Errorf("expected 1 agreement of user, got %d", got)
+		}
+	})
+}
+
+func TestCheckAgreementCompletelySignedByLender(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	// fixtures are inserted directly to keep the incremental counters untouched for other tests
+	fixtureUsers := []model.User{
+		{UserID: 9001, UserName: "Borrower", UserType: constant.UserTypeBorrower, Locale: constant.LocaleIndonesian},
+		{UserID: 9002, UserName: "Lender One", UserType: constant.UserTypeLender, Locale: constant.LocaleEnglish},
+		{UserID: 9003, UserName: "Lender Two", UserType: constant.UserTypeLender},
+	}
+	for i := range fixtureUsers {
+		users[fixtureUsers[i].UserID] = &fixtureUsers[i]
+	}
+	t.Cleanup(func() {
+		for _, v := range fixtureUsers {
+			delete(users, v.UserID)
+		}
+		delete(loans, 9002)
+	})
+
+	t.Run("check agreement completely signed by lender", func(t *testing.T) {
+		loan := model.Loan{
+			LoanID:          9002,
+			BorrowerID:      9001,
+			PrincipalAmount: 1000,
+			CollectedAmount: 1000,
+			InterestRate:    0.1,
+			Lending: []model.Lending{
+				{LenderID: 9002, InvestedAmount: 400, ReturnAmount: 440},
+				{LenderID: 9003, InvestedAmount: 600, ReturnAmount: 660},
+			},
+		}
+
+		_, err := helper.CheckAgreementCompletelySignedByLender(context.Background(), loan)
+		if err == nil {
+			t.Errorf("expected error when organizer-lender agreements are not generated yet")
+		}
+
+		err = helper.GenerateLenderAgreementPDF(context.Background(), &loan)
+		if err != nil {
+			t.Fatalf("expected no error generating lender agreements, got %+v", err)
+		}
+
+		for _, v := range loan.Lending {
+			if !strings.HasPrefix(v.OrganizerLenderAggrementURL, config.Default().PublicBaseURL+constant.APIVersionPrefix+"/agreement/") {
+				t.Errorf("expected agreement url built from configured base url, got %s", v.OrganizerLenderAggrementURL)
+			}
+		}
+
+		lenderAgreements := helper.GetAgreementsByFilter(context.Background(), model.AgreementFilter{LoanID: loan.LoanID, AgreementType: constant.AgreementTypeOrganizerLender})
+		if len(lenderAgreements) != 2 {
+			t.Fatalf("expected 2 organizer-lender agreements, got %d", len(lenderAgreements))
+		}
+		for _, v := range lenderAgreements {
+			if _, err := uuid.Parse(v.PublicID); err != nil {
+				t.Errorf("expected agreement public id to be a uuid, got %q", v.PublicID)
+			}
+			if got := helper.GetAgreementByPublicID(context.Background(), v.PublicID); got.AggrementID != v.AggrementID {
+				t.Errorf("expected agreement %d by public id, got %d", v.AggrementID, got.AggrementID)
+			}
+			if url := config.Default().AgreementURL(v.PublicID); url != loan.Lending[0].OrganizerLenderAggrementURL && url != loan.Lending[1].OrganizerLenderAggrementURL {
+				t.Errorf("expected agreement url by public id, got %s", url)
+			}
+		}
+
+		isSigned, err := helper.CheckAgreementCompletelySignedByLender(context.Background(), loan)
+		if err != nil || isSigned {
+			t.Errorf("expected unsigned without error, got %v, %+v", isSigned, err)
+		}
+
+		for _, v := range lenderAgreements {
+			v.IsSigned = true
+			helper.UpsertAgreement(context.Background(), v)
+
+			err = helper.GenerateSignedAgreementPDF(context.Background(), &loan, v)
+			if err != nil {
+				t.Fatalf("expected no error generating signed agreement, got %+v", err)
+			}
+		}
+
+		isSigned, err = helper.CheckAgreementCompletelySignedByLender(context.Background(), loan)
+		if err != nil || !isSigned {
+			t.Errorf("expected signed without error, got %v, %+v", isSigned, err)
+		}
+
+		signedCopies := helper.GetAgreementsByFilter(context.Background(), model.AgreementFilter{LoanID: loan.LoanID, AgreementType: constant.AgreementTypeSignedCopy})
+		if len(signedCopies) != 2 {
+			t.Fatalf("expected 2 signed copies, got %d", len(signedCopies))
+		}
+		for _, v := range signedCopies {
+			if v.SupersedesID == 0 || v.SignedAt == nil {
+				t.Errorf("expected signed copy to supersede original agreement, got %+v", v)
+			}
+		}
+		if len(loan.DisbursementInfo.AgreementSignedURLs) != 2 {
+			t.Errorf("expected 2 signed agreement urls, got %d", len(loan.DisbursementInfo.AgreementSignedURLs))
+		}
+	})
+}
+
+func TestCreateAgreementSpans(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	t.Run("render and store in spans of the parent", func(t *testing.T) {
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+		_, err := helper.createAgreement(ctx, model.Aggrement{LoanID: 9100, UserID: 9101, AgreementType: constant.AgreementTypeOrganizerBorrower},
+			document.TemplateOrganizerBorrower, document.AgreementData{
+				Loan:     model.Loan{LoanID: 9100, BorrowerID: 9101, PrincipalAmount: 1000000, InterestRate: 0.1},
+				Borrower: model.User{UserID: 9101, UserName: "Borrower"},
+			})
+		parent.End()
+		if err != nil {
+			t.Fatalf("expected agreement created, got error: %+v", err)
+		}
+
+		var names []string
+		for _, span := range recorder.Ended() {
+			if span.Parent().SpanID() == parent.SpanContext().SpanID() {
+				names = append(names, span.Name())
+			}
+		}
+		if strings.Join(names, ",") != "document.RenderPDF,storage.Put,helper.GenerateIncrementalAgreementID,helper.UpsertAgreement" {
+			t.Errorf("expected render, store, id and upsert spans, got %v", names)
+		}
+	})
+
+	t.Run("read in a span of the parent", func(t *testing.T) {
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+		helper.GetAgreementByAgreementID(ctx, 9102)
+		parent.End()
+
+		var found bool
+		for _, span := range recorder.Ended() {
+			if span.Parent().SpanID() == parent.SpanContext().SpanID() && span.Name() == "helper.GetAgreementByAgreementID" {
+				found = true
+				for _, attr := range span.Attributes() {
+					if attr.Key == "agreement.id" && attr.Value.AsInt64() != 9102 {
+						t.Errorf("expected agreement.id 9102, got %v", attr.Value.AsInt64())
+					}
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected helper.GetAgreementByAgreementID span of the parent")
+		}
+	})
+}
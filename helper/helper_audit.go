@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"context"
+	"sync"
+
+	"amartha-test/audit"
+	"amartha-test/logging"
+	"amartha-test/model"
+	"amartha-test/tracing"
+)
+
+const (
+	AuditTargetUser                = "user"
+	AuditTargetLoan                = "loan"
+	AuditTargetAgreement           = "agreement"
+	AuditTargetWebhookSubscription = "webhook_subscription"
+	AuditTargetWebhookDeadLetter   = "webhook_dead_letter"
+	AuditTargetNotification        = "notification"
+	AuditTargetTask                = "task"
+)
+
+// the audit log is append-only, entries are only added by recordAudit and every access holds mutexAudit
+var (
+	mutexAudit sync.RWMutex
+
+	auditEntries []model.AuditEntry
+)
+
+// recordAudit appends the write of the target to the audit log, chained after the last entry. The target is recorded
+// by the id the api serves it by, so the sequential loan and agreement ids stay inside the service.
+// Writes changing nothing are not recorded
+func (h *Helper) recordAudit(ctx context.Context, targetType string, targetID string, before interface{}, after interface{}) {
+	entry, err := audit.NewEntry(ctx, targetType, targetID, before, after)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed create audit entry", "op", "RecordAudit", "target", targetType, "target_id", targetID, "error", err)
+		return
+	}
+	if len(entry.Changes) == 0 {
+		return
+	}
+
+	mutexAudit.Lock()
+	defer mutexAudit.Unlock()
+
+	prevHash := audit.GenesisHash
+	if len(auditEntries) > 0 {
+		prevHash = auditEntries[len(auditEntries)-1].Hash
+	}
+	auditEntries = append(auditEntries, audit.Chain(entry, int64(len(auditEntries)+1), prevHash))
+}
+
+// GetAuditEntries returns the audit log ordered by sequence
+func (h *Helper) GetAuditEntries(ctx context.Context) []model.AuditEntry {
+	_, span := tracing.Start(ctx, "helper.GetAuditEntries")
+	defer span.End()
+	mutexAudit.RLock()
+	defer mutexAudit.RUnlock()
+
+	return append([]model.AuditEntry(nil), auditEntries...)
+}
+
+func (h *Helper) GetAuditEntriesByFilter(ctx context.Context, filter model.AuditFilter) []model.AuditEntry {
+	_, span := tracing.Start(ctx, "helper.GetAuditEntriesByFilter")
+	defer span.End()
+	mutexAudit.RLock()
+	defer mutexAudit.RUnlock()
+
+	var listEntry []model.AuditEntry
+	for _, v := range auditEntries {
+		if filter.Match(v) {
+			listEntry = append(listEntry, v)
+		}
+	}
+
+	return listEntry
+}
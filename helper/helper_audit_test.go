@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"amartha-test/audit"
+	"amartha-test/config"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+func TestAudit(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+	ctx := audit.WithRequestID(audit.WithActor(context.Background(), audit.UserActor(4)), "req-1")
+
+	t.Run("record every write chained", func(t *testing.T) {
+		loan := model.Loan{LoanID: 900, PublicID: "0f3a9d2c-5b7e-4c1a-8d6f-2e9b4a7c1d58", Status: 1}
+		helper.UpsertLoan(ctx, loan)
+		loan.Status = 2
+		helper.UpsertLoan(ctx, loan)
+		// unchanged loan is not recorded
+		helper.UpsertLoan(ctx, loan)
+
+		entries := helper.GetAuditEntriesByFilter(ctx, model.AuditFilter{TargetType: AuditTargetLoan, TargetID: loan.PublicID})
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 audit entries, got %d", len(entries))
+		}
+		if entries[0].Action != "loan.created" || entries[1].Action != "loan.updated" {
+			t.Errorf("expected loan.created and loan.updated, got %s and %s", entries[0].Action, entries[1].Action)
+		}
+		if entries[1].Actor != "user:4" || entries[1].RequestID != "req-1" {
+			t.Errorf("expected actor user:4 of request req-1, got %s of request %s", entries[1].Actor, entries[1].RequestID)
+		}
+		if len(entries[1].Changes) != 1 || entries[1].Changes[0].Field != "status" {
+			t.Errorf("expected status change, got %+v", entries[1].Changes)
+		}
+
+		verification := audit.Verify(helper.GetAuditEntries(ctx))
+		if !verification.IsValid {
+			t.Errorf("expected valid audit log, broken at sequence %d", verification.BrokenSequence)
+		}
+	})
+
+	t.Run("keep webhook secret out", func(t *testing.T) {
+		subscription := model.WebhookSubscription{
+			SubscriptionID: 900,
+			URL:            "https://partner.example.com/hook",
+			Secret:         "whsec_secret",
+		}
+		helper.UpsertWebhookSubscription(ctx, subscription)
+		helper.DeleteWebhookSubscription(ctx, subscription.SubscriptionID)
+
+		entries := helper.GetAuditEntriesByFilter(ctx, model.AuditFilter{TargetType: AuditTargetWebhookSubscription, TargetID: strconv.FormatInt(subscription.SubscriptionID, 10)})
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 audit entries, got %d", len(entries))
+		}
+		for _, v := range entries {
+			for _, change := range v.Changes {
+				if change.Field == "secret" {
+					t.Errorf("expected secret left out of %s", v.Action)
+				}
+			}
+		}
+		if entries[1].Action != "webhook_subscription.deleted" {
+			t.Errorf("expected webhook_subscription.deleted, got %s", entries[1].Action)
+		}
+	})
+	t.Run("record agreement by its public id", func(t *testing.T) {
+		agreement := model.Aggrement{
+			AggrementID:   900,
+			PublicID:      "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c",
+			LoanID:        900,
+			LoanPublicID:  "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+			UserID:        4,
+			AgreementType: 1,
+		}
+		helper.UpsertAgreement(ctx, agreement)
+
+		entries := helper.GetAuditEntriesByFilter(ctx, model.AuditFilter{TargetType: AuditTargetAgreement, TargetID: agreement.PublicID})
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 audit entry, got %d", len(entries))
+		}
+		if !strings.Contains(string(entries[0].After), `"agreement_id":"`+agreement.PublicID+`"`) {
+			t.Errorf("expected agreement public id in %s, got %s", entries[0].Action, entries[0].After)
+		}
+		if strings.Contains(string(entries[0].After), "aggrement_id") {
+			t.Errorf("expected sequential agreement id left out of %s, got %s", entries[0].Action, entries[0].After)
+		}
+	})
+}
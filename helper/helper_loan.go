@@ -1,9 +1,17 @@
 package helper
 
 import (
+	"context"
+	"errors"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"amartha-test/apperror"
 	"amartha-test/model"
+	"amartha-test/picture"
+	"amartha-test/storage"
+	"amartha-test/tracing"
 )
 
 var (
@@ -13,18 +21,26 @@ var (
 	loans = make(map[int64]*model.Loan)
 )
 
-func (h *Helper) GenerateIncrementalLoanID() int64 {
+func (h *Helper) GenerateIncrementalLoanID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalLoanID")
+	defer span.End()
 	mutexLoan.Lock()
 	defer mutexLoan.Unlock()
 	loanIDCounter++
 	return loanIDCounter
 }
 
-func (h *Helper) UpsertLoan(loan model.Loan) {
+func (h *Helper) UpsertLoan(ctx context.Context, loan model.Loan) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertLoan", attribute.Int64("loan.id", loan.LoanID))
+	defer span.End()
+	before := loans[loan.LoanID]
 	loans[loan.LoanID] = &loan
+	h.recordAudit(ctx, AuditTargetLoan, loan.PublicID, before, loan)
 }
 
-func (h *Helper) GetLoans() []model.Loan {
+func (h *Helper) GetLoans(ctx context.Context) []model.Loan {
+	_, span := tracing.Start(ctx, "helper.GetLoans")
+	defer span.End()
 	var listLoan []model.Loan
 	for _, v := range loans {
 		listLoan = append(listLoan, *v)
@@ -33,7 +49,22 @@ func (h *Helper) GetLoans() []model.Loan {
 	return listLoan
 }
 
-func (h *Helper) GetLoanByLoanID(loanID int64) model.Loan {
+func (h *Helper) GetLoansByFilter(ctx context.Context, filter model.LoanFilter) []model.Loan {
+	_, span := tracing.Start(ctx, "helper.GetLoansByFilter")
+	defer span.End()
+	var listLoan []model.Loan
+	for _, v := range loans {
+		if filter.Match(*v) {
+			listLoan = append(listLoan, *v)
+		}
+	}
+
+	return listLoan
+}
+
+func (h *Helper) GetLoanByLoanID(ctx context.Context, loanID int64) model.Loan {
+	_, span := tracing.Start(ctx, "helper.GetLoanByLoanID", attribute.Int64("loan.id", loanID))
+	defer span.End()
 	loan, exists := loans[loanID]
 	if exists {
 		return *loan
@@ -41,3 +72,65 @@ func (h *Helper) GetLoanByLoanID(loanID int64) model.Loan {
 
 	return model.Loan{}
 }
+
+// GetLoanByPublicID returns the loan of the public id the api identifies it by
+func (h *Helper) GetLoanByPublicID(ctx context.Context, publicID string) model.Loan {
+	_, span := tracing.Start(ctx, "helper.GetLoanByPublicID")
+	defer span.End()
+	for _, v := range loans {
+		if v.PublicID == publicID {
+			return *v
+		}
+	}
+
+	return model.Loan{}
+}
+
+// PutPictureProof stores the processed picture proof and its thumbnail in the document store
+func (h *Helper) PutPictureProof(ctx context.Context, proof picture.Picture) (_ model.PictureProof, err error) {
+	ctx, span := tracing.Start(ctx, "helper.PutPictureProof", attribute.String("picture.content_type", proof.ContentType),
+		attribute.Int("picture.width", proof.Width), attribute.Int("picture.height", proof.Height))
+	defer func() { tracing.End(span, err) }()
+
+	documentKey, err := h.putDocument(ctx, proof.Data)
+	if err != nil {
+		return model.PictureProof{}, err
+	}
+
+	thumbnailKey, err := h.putDocument(ctx, proof.Thumbnail)
+	if err != nil {
+		return model.PictureProof{}, err
+	}
+
+	return model.PictureProof{
+		DocumentKey:  documentKey,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  proof.ContentType,
+		Width:        proof.Width,
+		Height:       proof.Height,
+		SizeBytes:    len(proof.Data),
+	}, nil
+}
+
+// OpenPictureProof opens the picture proof of the loan, or its jpeg thumbnail
+func (h *Helper) OpenPictureProof(ctx context.Context, loan model.Loan, thumbnail bool) (_ storage.Document, err error) {
+	ctx, span := tracing.Start(ctx, "helper.OpenPictureProof", attribute.Int64("loan.id", loan.LoanID),
+		attribute.Bool("picture.thumbnail", thumbnail))
+	defer func() { tracing.End(span, err) }()
+
+	if loan.ApprovalInfo == nil || loan.ApprovalInfo.Proof == nil {
+		return storage.Document{}, apperror.ProofNotFound.New().WithDetail("loan_id", loan.PublicID)
+	}
+
+	key := loan.ApprovalInfo.Proof.DocumentKey
+	if thumbnail {
+		key = loan.ApprovalInfo.Proof.ThumbnailKey
+	}
+
+	document, err := h.DocumentStore.Open(ctx, key)
+	if errors.Is(err, storage.ErrDocumentNotFound) || errors.Is(err, storage.ErrInvalidDocumentKey) {
+		return storage.Document{}, apperror.DocumentNotFound.Wrap(err).WithDetail("loan_id", loan.PublicID)
+	}
+
+	return document, err
+}
@@ -1,20 +1,30 @@
 package helper
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
 	"testing"
 	"time"
 
+	"amartha-test/apperror"
+	"amartha-test/config"
 	"amartha-test/model"
+	"amartha-test/picture"
+	"amartha-test/storage"
 )
 
 func TestGenerateIncrementalLoanID(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("generate incremental loan id", func(t *testing.T) {
 		expectedIDs := []int64{1, 2, 3, 4, 5}
 
 		for i := 0; i < len(expectedIDs); i++ {
-			actualID := helper.GenerateIncrementalLoanID()
+			actualID := helper.GenerateIncrementalLoanID(context.Background())
 			if actualID != expectedIDs[i] {
 				t.Errorf("expected loan id %d, got %d", expectedIDs[i], actualID)
 			}
@@ -23,12 +33,12 @@ func TestGenerateIncrementalLoanID(t *testing.T) {
 }
 
 func TestGetLoans(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("get loans", func(t *testing.T) {
 		for i := 0; i < 5; i++ {
 			loan := model.Loan{
-				LoanID:          helper.GenerateIncrementalLoanID(),
+				LoanID:          helper.GenerateIncrementalLoanID(context.Background()),
 				TrxID:           int64(1000 + i),
 				BorrowerID:      int64(2000 + i),
 				PrincipalAmount: 20000 + float64(i)*5000,
@@ -37,10 +47,10 @@ func TestGetLoans(t *testing.T) {
 				Status:          1,
 				StatusDesc:      "disbursed",
 			}
-			helper.UpsertLoan(loan)
+			helper.UpsertLoan(context.Background(), loan)
 		}
 
-		loansList := helper.GetLoans()
+		loansList := helper.GetLoans(context.Background())
 		if len(loansList) < 0 {
 			t.Errorf("expected, got %d", len(loansList))
 		}
@@ -48,11 +58,11 @@ func TestGetLoans(t *testing.T) {
 }
 
 func TestGetLoanByLoanID(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("get loan by loan id", func(t *testing.T) {
 		loan := model.Loan{
-			LoanID:          helper.GenerateIncrementalLoanID(),
+			LoanID:          helper.GenerateIncrementalLoanID(context.Background()),
 			TrxID:           1001,
 			BorrowerID:      2001,
 			PrincipalAmount: 500000,
@@ -61,9 +71,9 @@ func TestGetLoanByLoanID(t *testing.T) {
 			Status:          1,
 			StatusDesc:      "disbursed",
 		}
-		helper.UpsertLoan(loan)
+		helper.UpsertLoan(context.Background(), loan)
 
-		storedLoan := helper.GetLoanByLoanID(loan.LoanID)
+		storedLoan := helper.GetLoanByLoanID(context.Background(), loan.LoanID)
 		if storedLoan.LoanID != loan.LoanID {
 			t.Errorf("expected loan id %d, got %d", loan.LoanID, storedLoan.LoanID)
 		}
@@ -72,7 +82,7 @@ func TestGetLoanByLoanID(t *testing.T) {
 		}
 
 		nonExistentLoanID := int64(999)
-		storedLoanNotFound := helper.GetLoanByLoanID(nonExistentLoanID)
+		storedLoanNotFound := helper.GetLoanByLoanID(context.Background(), nonExistentLoanID)
 		if storedLoanNotFound.LoanID != 0 {
 			t.Errorf("expected non-existing loan to return empty loan, got %+v", storedLoanNotFound)
 		}
@@ -80,11 +90,11 @@ func TestGetLoanByLoanID(t *testing.T) {
 }
 
 func TestUpsertLoan(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("upsert loan", func(t *testing.T) {
 		loan := model.Loan{
-			LoanID:          helper.GenerateIncrementalLoanID(),
+			LoanID:          helper.GenerateIncrementalLoanID(context.Background()),
 			TrxID:           1001,
 			BorrowerID:      2001,
 			PrincipalAmount: 10000,
@@ -112,9 +122,9 @@ func TestUpsertLoan(t *testing.T) {
 			},
 		}
 
-		helper.UpsertLoan(loan)
+		helper.UpsertLoan(context.Background(), loan)
 
-		storedLoan := helper.GetLoanByLoanID(loan.LoanID)
+		storedLoan := helper.GetLoanByLoanID(context.Background(), loan.LoanID)
 		if storedLoan.LoanID != loan.LoanID {
 			t.Errorf("expected loan id %d, got %d", loan.LoanID, storedLoan.LoanID)
 		}
@@ -126,3 +136,57 @@ func TestUpsertLoan(t *testing.T) {
 		}
 	})
 }
+
+func TestPictureProof(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 400, 300))); err != nil {
+		t.Fatal(err)
+	}
+	processed, err := picture.Process(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("put and open picture proof", func(t *testing.T) {
+		proof, err := helper.PutPictureProof(context.Background(), processed)
+		if err != nil {
+			t.Fatalf("expected picture proof stored, got error: %+v", err)
+		}
+		if proof.DocumentKey != storage.DocumentKey(processed.Data) || proof.ThumbnailKey != storage.DocumentKey(processed.Thumbnail) {
+			t.Errorf("expected content addressed keys, got %+v", proof)
+		}
+		if proof.ContentType != picture.ContentTypePNG || proof.Width != 400 || proof.Height != 300 || proof.SizeBytes != len(processed.Data) {
+			t.Errorf("expected picture metadata, got %+v", proof)
+		}
+
+		loan := model.Loan{LoanID: 9201, ApprovalInfo: &model.ApprovalInfo{Proof: &proof}}
+		for thumbnail, expected := range map[bool][]byte{false: processed.Data, true: processed.Thumbnail} {
+			document, err := helper.OpenPictureProof(context.Background(), loan, thumbnail)
+			if err != nil {
+				t.Fatalf("expected picture proof opened, got error: %+v", err)
+			}
+			content, _ := io.ReadAll(document.Content)
+			document.Content.Close()
+			if !bytes.Equal(content, expected) {
+				t.Errorf("expected content of thumbnail %t stored, got %d bytes", thumbnail, len(content))
+			}
+		}
+	})
+
+	t.Run("open picture proof of loan without proof", func(t *testing.T) {
+		_, err := helper.OpenPictureProof(context.Background(), model.Loan{LoanID: 9202}, false)
+		if !errors.Is(err, apperror.ProofNotFound) {
+			t.Errorf("expected proof not found, got %v", err)
+		}
+	})
+
+	t.Run("open picture proof missing from document store", func(t *testing.T) {
+		loan := model.Loan{LoanID: 9203, ApprovalInfo: &model.ApprovalInfo{Proof: &model.PictureProof{DocumentKey: storage.DocumentKey([]byte("gone"))}}}
+		_, err := helper.OpenPictureProof(context.Background(), loan, false)
+		if !errors.Is(err, apperror.DocumentNotFound) {
+			t.Errorf("expected document not found, got %v", err)
+		}
+	})
+}
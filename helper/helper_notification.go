@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"amartha-test/model"
+	"amartha-test/tracing"
+)
+
+// notifications are written by the notification worker while the api reads them, so every access holds mutexNotification
+var (
+	notificationIDCounter int64
+	mutexNotification     sync.RWMutex
+
+	notifications = make(map[int64]*model.Notification)
+)
+
+func (h *Helper) GenerateIncrementalNotificationID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalNotificationID")
+	defer span.End()
+	mutexNotification.Lock()
+	defer mutexNotification.Unlock()
+	notificationIDCounter++
+	return notificationIDCounter
+}
+
+func (h *Helper) UpsertNotification(ctx context.Context, notification model.Notification) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertNotification", attribute.Int64("notification.id", notification.NotificationID))
+	defer span.End()
+	mutexNotification.Lock()
+	defer mutexNotification.Unlock()
+	before := notifications[notification.NotificationID]
+	notifications[notification.NotificationID] = &notification
+	h.recordAudit(ctx, AuditTargetNotification, strconv.FormatInt(notification.NotificationID, 10), before, notification)
+}
+
+func (h *Helper) GetNotificationsByUserID(ctx context.Context, userID int64) []model.Notification {
+	_, span := tracing.Start(ctx, "helper.GetNotificationsByUserID", attribute.Int64("user.id", userID))
+	defer span.End()
+	mutexNotification.RLock()
+	defer mutexNotification.RUnlock()
+
+	var listNotification []model.Notification
+	for _, v := range notifications {
+		if v.UserID == userID {
+			listNotification = append(listNotification, *v)
+		}
+	}
+
+	return listNotification
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"amartha-test/config"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+func TestNotification(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("upsert and get notifications by user id", func(t *testing.T) {
+		for _, userID := range []int64{901, 902, 901} {
+			helper.UpsertNotification(context.Background(), model.Notification{
+				NotificationID: helper.GenerateIncrementalNotificationID(context.Background()),
+				UserID:         userID,
+				EventType:      "loan.approved",
+			})
+		}
+
+		if len(helper.GetNotificationsByUserID(context.Background(), 901)) != 2 {
+			t.Errorf("expected 2 notifications of user, got %d", len(helper.GetNotificationsByUserID(context.Background(), 901)))
+		}
+		if len(helper.GetNotificationsByUserID(context.Background(), 903)) != 0 {
+			t.Errorf("expected no notification of user, got %d", len(helper.GetNotificationsByUserID(context.Background(), 903)))
+		}
+	})
+}
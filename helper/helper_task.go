@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"amartha-test/model"
+	"amartha-test/tracing"
+)
+
+// tasks are written by the event handlers of concurrent requests, so every access holds mutexTask
+var (
+	taskIDCounter int64
+	mutexTask     sync.RWMutex
+
+	tasks = make(map[int64]*model.Task)
+)
+
+func (h *Helper) GenerateIncrementalTaskID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalTaskID")
+	defer span.End()
+	mutexTask.Lock()
+	defer mutexTask.Unlock()
+	taskIDCounter++
+	return taskIDCounter
+}
+
+func (h *Helper) UpsertTask(ctx context.Context, task model.Task) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertTask", attribute.Int64("task.id", task.TaskID))
+	defer span.End()
+	mutexTask.Lock()
+	defer mutexTask.Unlock()
+	before := tasks[task.TaskID]
+	tasks[task.TaskID] = &task
+	h.recordAudit(ctx, AuditTargetTask, strconv.FormatInt(task.TaskID, 10), before, task)
+}
+
+func (h *Helper) GetTasksByFilter(ctx context.Context, filter model.TaskFilter) []model.Task {
+	_, span := tracing.Start(ctx, "helper.GetTasksByFilter")
+	defer span.End()
+	mutexTask.RLock()
+	defer mutexTask.RUnlock()
+
+	var listTask []model.Task
+	for _, v := range tasks {
+		if filter.Match(*v) {
+			listTask = append(listTask, *v)
+		}
+	}
+
+	return listTask
+}
@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"amartha-test/config"
+	"amartha-test/constant"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+func TestTask(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("upsert and get tasks by filter", func(t *testing.T) {
+		task := model.Task{
+			TaskID:     helper.GenerateIncrementalTaskID(context.Background()),
+			LoanID:     901,
+			TaskType:   constant.TaskTypeValidationVisit,
+			Status:     constant.TaskStatusOpen,
+			AssigneeID: 902,
+		}
+		helper.UpsertTask(context.Background(), task)
+		helper.UpsertTask(context.Background(), model.Task{
+			TaskID:     helper.GenerateIncrementalTaskID(context.Background()),
+			LoanID:     901,
+			TaskType:   constant.TaskTypeDisbursementVisit,
+			Status:     constant.TaskStatusOpen,
+			AssigneeID: 903,
+		})
+
+		if len(helper.GetTasksByFilter(context.Background(), model.TaskFilter{LoanID: 901})) != 2 {
+			t.Errorf("expected 2 tasks of loan, got %d", len(helper.GetTasksByFilter(context.Background(), model.TaskFilter{LoanID: 901})))
+		}
+
+		task.Status = constant.TaskStatusCompleted
+		helper.UpsertTask(context.Background(), task)
+
+		open := helper.GetTasksByFilter(context.Background(), model.TaskFilter{LoanID: 901, Status: constant.TaskStatusOpen})
+		if len(open) != 1 || open[0].AssigneeID != 903 {
+			t.Errorf("expected only the open task of assignee 903, got %+v", open)
+		}
+	})
+}
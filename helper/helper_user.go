@@ -1,10 +1,15 @@
 package helper
 
 import (
+	"context"
+	"strconv"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"amartha-test/constant"
 	"amartha-test/model"
+	"amartha-test/tracing"
 )
 
 var (
@@ -14,54 +19,74 @@ var (
 	users = make(map[int64]*model.User)
 )
 
-func (h *Helper) GenerateIncrementalUserID() int64 {
+func (h *Helper) GenerateIncrementalUserID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalUserID")
+	defer span.End()
 	mutexUser.Lock()
 	defer mutexUser.Unlock()
 	userIDCounter++
 	return userIDCounter
 }
 
-func (h *Helper) InitUsers() {
+func (h *Helper) InitUsers(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "helper.InitUsers")
+	defer span.End()
 	var borrower1, lender1, lender2, fieldValidator1, fieldOfficer1 model.User
 
 	borrower1 = model.User{
-		UserID:   h.GenerateIncrementalUserID(),
-		UserName: "Septian",
-		UserType: constant.UserTypeBorrower,
+		UserID:      h.GenerateIncrementalUserID(ctx),
+		UserName:    "Septian",
+		UserType:    constant.UserTypeBorrower,
+		Locale:      constant.LocaleIndonesian,
+		Email:       "septian@example.com",
+		PhoneNumber: "+6281200000001",
+		Address:     "Jl. Kemang Raya No. 10, Jakarta Selatan",
+		Location:    &model.GeoPoint{Latitude: -6.2607, Longitude: 106.8137},
+		Region:      "jakarta-selatan",
 	}
 
 	lender1 = model.User{
-		UserID:   h.GenerateIncrementalUserID(),
-		UserName: "Pratama",
-		UserType: constant.UserTypeLender,
+		UserID:      h.GenerateIncrementalUserID(ctx),
+		UserName:    "Pratama",
+		UserType:    constant.UserTypeLender,
+		Locale:      constant.LocaleEnglish,
+		Email:       "pratama@example.com",
+		PhoneNumber: "+6281200000002",
 	}
 
 	lender2 = model.User{
-		UserID:   h.GenerateIncrementalUserID(),
+		UserID:   h.GenerateIncrementalUserID(ctx),
 		UserName: "Rusmana",
 		UserType: constant.UserTypeLender,
+		Locale:   constant.LocaleIndonesian,
+		Email:    "rusmana@example.com",
 	}
 
 	fieldValidator1 = model.User{
-		UserID:   h.GenerateIncrementalUserID(),
+		UserID:   h.GenerateIncrementalUserID(ctx),
 		UserName: "Validator",
 		UserType: constant.UserTypeFieldValidatorEmployee,
+		Locale:   constant.LocaleIndonesian,
+		Region:   "jakarta-selatan",
 	}
 
 	fieldOfficer1 = model.User{
-		UserID:   h.GenerateIncrementalUserID(),
+		UserID:   h.GenerateIncrementalUserID(ctx),
 		UserName: "Officer",
 		UserType: constant.UserTypeFieldOfficerEmployee,
+		Locale:   constant.LocaleIndonesian,
+		Region:   "jakarta-selatan",
 	}
 
-	users[borrower1.UserID] = &borrower1
-	users[lender1.UserID] = &lender1
-	users[lender2.UserID] = &lender2
-	users[fieldValidator1.UserID] = &fieldValidator1
-	users[fieldOfficer1.UserID] = &fieldOfficer1
+	for _, user := range []model.User{borrower1, lender1, lender2, fieldValidator1, fieldOfficer1} {
+		users[user.UserID] = &user
+		h.recordAudit(ctx, AuditTargetUser, strconv.FormatInt(user.UserID, 10), nil, user)
+	}
 }
 
-func (h *Helper) GetUsers() []model.User {
+func (h *Helper) GetUsers(ctx context.Context) []model.User {
+	_, span := tracing.Start(ctx, "helper.GetUsers")
+	defer span.End()
 	var listUser []model.User
 	for _, v := range users {
 		listUser = append(listUser, *v)
@@ -70,7 +95,22 @@ func (h *Helper) GetUsers() []model.User {
 	return listUser
 }
 
-func (h *Helper) GetUserByUserID(userID int64) model.User {
+func (h *Helper) GetUsersByFilter(ctx context.Context, filter model.UserFilter) []model.User {
+	_, span := tracing.Start(ctx, "helper.GetUsersByFilter")
+	defer span.End()
+	var listUser []model.User
+	for _, v := range users {
+		if filter.Match(*v) {
+			listUser = append(listUser, *v)
+		}
+	}
+
+	return listUser
+}
+
+func (h *Helper) GetUserByUserID(ctx context.Context, userID int64) model.User {
+	_, span := tracing.Start(ctx, "helper.GetUserByUserID", attribute.Int64("user.id", userID))
+	defer span.End()
 	user, exists := users[userID]
 	if exists {
 		return *user
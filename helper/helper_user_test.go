@@ -1,16 +1,22 @@
 package helper
 
 import (
+	"context"
 	"testing"
+
+	"amartha-test/config"
+	"amartha-test/constant"
+	"amartha-test/model"
+	"amartha-test/storage"
 )
 
 func TestInitUsersAndGetUsers(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("initialize and get users", func(t *testing.T) {
-		helper.InitUsers()
+		helper.InitUsers(context.Background())
 
-		usersList := helper.GetUsers()
+		usersList := helper.GetUsers(context.Background())
 		if len(usersList) != 5 {
 			t.Errorf("expected 5 users, got %d", len(usersList))
 		}
@@ -32,25 +38,43 @@ func TestInitUsersAndGetUsers(t *testing.T) {
 }
 
 func TestGetUserByUserID(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	t.Run("get user by user id", func(t *testing.T) {
-		helper.InitUsers()
+		helper.InitUsers(context.Background())
 
 		userID := int64(1)
-		user := helper.GetUserByUserID(userID)
+		user := helper.GetUserByUserID(context.Background(), userID)
 		if user.UserName != "Septian" && user.UserName != "Pratama" && user.UserName != "Rusmana" && user.UserName != "Validator" && user.UserName != "Officer" {
 			userID = int64(7)
-			user = helper.GetUserByUserID(userID)
+			user = helper.GetUserByUserID(context.Background(), userID)
 			if user.UserName != "Septian" && user.UserName != "Pratama" && user.UserName != "Rusmana" && user.UserName != "Validator" && user.UserName != "Officer" {
 				t.Errorf("expected username 'Septian', got '%s'", user.UserName)
 			}
 		}
 
 		userIDNotFound := int64(999)
-		userNotFound := helper.GetUserByUserID(userIDNotFound)
+		userNotFound := helper.GetUserByUserID(context.Background(), userIDNotFound)
 		if userNotFound.UserID != 0 {
 			t.Errorf("expected non-existing user to return empty user, got %+v", userNotFound)
 		}
 	})
 }
+
+func TestGetUsersByFilter(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("get users by filter", func(t *testing.T) {
+		helper.InitUsers(context.Background())
+
+		for _, user := range helper.GetUsersByFilter(context.Background(), model.UserFilter{UserType: constant.UserTypeLender}) {
+			if user.UserType != constant.UserTypeLender {
+				t.Errorf("expected only lender, got user type %d", user.UserType)
+			}
+		}
+
+		if len(helper.GetUsersByFilter(context.Background(), model.UserFilter{})) != len(helper.GetUsers(context.Background())) {
+			t.Errorf("expected empty filter to return every user")
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package helper
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"amartha-test/model"
+	"amartha-test/tracing"
+)
+
+// webhook data is read by the delivery workers while the api writes it, so every access holds mutexWebhook
+var (
+	webhookSubscriptionIDCounter int64
+	webhookDeliveryIDCounter     int64
+	mutexWebhook                 sync.RWMutex
+
+	webhookSubscriptions = make(map[int64]*model.WebhookSubscription)
+	webhookDeadLetters   = make(map[int64]*model.WebhookDelivery)
+)
+
+func (h *Helper) GenerateIncrementalWebhookSubscriptionID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalWebhookSubscriptionID")
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	webhookSubscriptionIDCounter++
+	return webhookSubscriptionIDCounter
+}
+
+func (h *Helper) UpsertWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertWebhookSubscription", attribute.Int64("webhook.subscription_id", subscription.SubscriptionID))
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	before := webhookSubscriptions[subscription.SubscriptionID]
+	webhookSubscriptions[subscription.SubscriptionID] = &subscription
+	h.recordAudit(ctx, AuditTargetWebhookSubscription, strconv.FormatInt(subscription.SubscriptionID, 10), withoutSecret(before), withoutSecret(&subscription))
+}
+
+func (h *Helper) DeleteWebhookSubscription(ctx context.Context, subscriptionID int64) {
+	ctx, span := tracing.Start(ctx, "helper.DeleteWebhookSubscription", attribute.Int64("webhook.subscription_id", subscriptionID))
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	before := webhookSubscriptions[subscriptionID]
+	delete(webhookSubscriptions, subscriptionID)
+	h.recordAudit(ctx, AuditTargetWebhookSubscription, strconv.FormatInt(subscriptionID, 10), withoutSecret(before), nil)
+}
+
+func (h *Helper) GetWebhookSubscriptions(ctx context.Context) []model.WebhookSubscription {
+	_, span := tracing.Start(ctx, "helper.GetWebhookSubscriptions")
+	defer span.End()
+	mutexWebhook.RLock()
+	defer mutexWebhook.RUnlock()
+
+	var listSubscription []model.WebhookSubscription
+	for _, v := range webhookSubscriptions {
+		listSubscription = append(listSubscription, *v)
+	}
+
+	return listSubscription
+}
+
+func (h *Helper) GetWebhookSubscriptionBySubscriptionID(ctx context.Context, subscriptionID int64) model.WebhookSubscription {
+	_, span := tracing.Start(ctx, "helper.GetWebhookSubscriptionBySubscriptionID", attribute.Int64("webhook.subscription_id", subscriptionID))
+	defer span.End()
+	mutexWebhook.RLock()
+	defer mutexWebhook.RUnlock()
+
+	subscription, exists := webhookSubscriptions[subscriptionID]
+	if exists {
+		return *subscription
+	}
+
+	return model.WebhookSubscription{}
+}
+
+func (h *Helper) GenerateIncrementalWebhookDeliveryID(ctx context.Context) int64 {
+	_, span := tracing.Start(ctx, "helper.GenerateIncrementalWebhookDeliveryID")
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	webhookDeliveryIDCounter++
+	return webhookDeliveryIDCounter
+}
+
+func (h *Helper) UpsertWebhookDeadLetter(ctx context.Context, delivery model.WebhookDelivery) {
+	ctx, span := tracing.Start(ctx, "helper.UpsertWebhookDeadLetter", attribute.Int64("webhook.delivery_id", delivery.DeliveryID))
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	before := webhookDeadLetters[delivery.DeliveryID]
+	webhookDeadLetters[delivery.DeliveryID] = &delivery
+	h.recordAudit(ctx, AuditTargetWebhookDeadLetter, strconv.FormatInt(delivery.DeliveryID, 10), before, delivery)
+}
+
+func (h *Helper) DeleteWebhookDeadLetter(ctx context.Context, deliveryID int64) {
+	ctx, span := tracing.Start(ctx, "helper.DeleteWebhookDeadLetter", attribute.Int64("webhook.delivery_id", deliveryID))
+	defer span.End()
+	mutexWebhook.Lock()
+	defer mutexWebhook.Unlock()
+	before := webhookDeadLetters[deliveryID]
+	delete(webhookDeadLetters, deliveryID)
+	h.recordAudit(ctx, AuditTargetWebhookDeadLetter, strconv.FormatInt(deliveryID, 10), before, nil)
+}
+
+func (h *Helper) GetWebhookDeadLetters(ctx context.Context) []model.WebhookDelivery {
+	_, span := tracing.Start(ctx, "helper.GetWebhookDeadLetters")
+	defer span.End()
+	mutexWebhook.RLock()
+	defer mutexWebhook.RUnlock()
+
+	var listDelivery []model.WebhookDelivery
+	for _, v := range webhookDeadLetters {
+		listDelivery = append(listDelivery, *v)
+	}
+
+	return listDelivery
+}
+
+func (h *Helper) GetWebhookDeadLetterByDeliveryID(ctx context.Context, deliveryID int64) model.WebhookDelivery {
+	_, span := tracing.Start(ctx, "helper.GetWebhookDeadLetterByDeliveryID", attribute.Int64("webhook.delivery_id", deliveryID))
+	defer span.End()
+	mutexWebhook.RLock()
+	defer mutexWebhook.RUnlock()
+
+	delivery, exists := webhookDeadLetters[deliveryID]
+	if exists {
+		return *delivery
+	}
+
+	return model.WebhookDelivery{}
+}
+
+// withoutSecret returns the subscription without its signing secret, which is kept out of the audit log
+func withoutSecret(subscription *model.WebhookSubscription) *model.WebhookSubscription {
+	if subscription == nil {
+		return nil
+	}
+
+	redacted := *subscription
+	redacted.Secret = ""
+	return &redacted
+}
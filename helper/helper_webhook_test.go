@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"amartha-test/config"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+func TestWebhookSubscription(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("upsert, get and delete webhook subscription", func(t *testing.T) {
+		subscription := model.WebhookSubscription{
+			SubscriptionID: helper.GenerateIncrementalWebhookSubscriptionID(context.Background()),
+			URL:            "https://partner.example.com/hook",
+			EventTypes:     []string{"loan.approved"},
+		}
+		helper.UpsertWebhookSubscription(context.Background(), subscription)
+
+		actual := helper.GetWebhookSubscriptionBySubscriptionID(context.Background(), subscription.SubscriptionID)
+		if actual.URL != subscription.URL {
+			t.Errorf("expected subscription url %s, got %s", subscription.URL, actual.URL)
+		}
+		if len(helper.GetWebhookSubscriptions(context.Background())) != 1 {
+			t.Errorf("expected 1 subscription, got %d", len(helper.GetWebhookSubscriptions(context.Background())))
+		}
+
+		helper.DeleteWebhookSubscription(context.Background(), subscription.SubscriptionID)
+		actual = helper.GetWebhookSubscriptionBySubscriptionID(context.Background(), subscription.SubscriptionID)
+		if actual.SubscriptionID != 0 {
+			t.Errorf("expected subscription deleted, got %+v", actual)
+		}
+	})
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
+
+	t.Run("upsert, get and delete webhook dead letter", func(t *testing.T) {
+		delivery := model.WebhookDelivery{
+			DeliveryID: helper.GenerateIncrementalWebhookDeliveryID(context.Background()),
+			EventType:  "loan.approved",
+			Attempts:   5,
+		}
+		helper.UpsertWebhookDeadLetter(context.Background(), delivery)
+
+		actual := helper.GetWebhookDeadLetterByDeliveryID(context.Background(), delivery.DeliveryID)
+		if actual.Attempts != delivery.Attempts {
+			t.Errorf("expected attempts %d, got %d", delivery.Attempts, actual.Attempts)
+		}
+		if len(helper.GetWebhookDeadLetters(context.Background())) != 1 {
+			t.Errorf("expected 1 dead letter, got %d", len(helper.GetWebhookDeadLetters(context.Background())))
+		}
+
+		helper.DeleteWebhookDeadLetter(context.Background(), delivery.DeliveryID)
+		actual = helper.GetWebhookDeadLetterByDeliveryID(context.Background(), delivery.DeliveryID)
+		if actual.DeliveryID != 0 {
+			t.Errorf("expected dead letter deleted, got %+v", actual)
+		}
+	})
+}
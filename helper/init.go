@@ -1,36 +1,81 @@
 package helper
 
-import "amartha-test/model"
+import (
+	"context"
+
+	"amartha-test/config"
+	"amartha-test/model"
+	"amartha-test/picture"
+	"amartha-test/storage"
+)
 
 type IHelper interface {
 	// helper user
-	GenerateIncrementalUserID() int64
-	InitUsers()
-	GetUsers() []model.User
-	GetUserByUserID(userID int64) model.User
+	GenerateIncrementalUserID(ctx context.Context) int64
+	InitUsers(ctx context.Context)
+	GetUsers(ctx context.Context) []model.User
+	GetUsersByFilter(ctx context.Context, filter model.UserFilter) []model.User
+	GetUserByUserID(ctx context.Context, userID int64) model.User
 
 	// helper loan
-	GenerateIncrementalLoanID() int64
-	UpsertLoan(loan model.Loan)
-	GetLoans() []model.Loan
-	GetLoanByLoanID(loanID int64) model.Loan
+	GenerateIncrementalLoanID(ctx context.Context) int64
+	UpsertLoan(ctx context.Context, loan model.Loan)
+	GetLoans(ctx context.Context) []model.Loan
+	GetLoansByFilter(ctx context.Context, filter model.LoanFilter) []model.Loan
+	GetLoanByLoanID(ctx context.Context, loanID int64) model.Loan
+	GetLoanByPublicID(ctx context.Context, publicID string) model.Loan
+	PutPictureProof(ctx context.Context, proof picture.Picture) (model.PictureProof, error)
+	OpenPictureProof(ctx context.Context, loan model.Loan, thumbnail bool) (storage.Document, error)
 
 	// helper agreement
-	GenerateIncrementalAgreementID() int64
-	UpsertAgreement(agreement model.Aggrement)
-	GetAgreements() []model.Aggrement
-	GetAgreementByAgreementID(agreementID int64) model.Aggrement
-	GenerateBorrowerAgreementPDF(loan *model.Loan) error
-	GenerateLenderAgreementPDF(loan *model.Loan) error
-	GenerateSignedAgreementPDF(loan *model.Loan, userID int64) error
-	CheckAgreementCompletelySignedByLender(loan model.Loan) (bool, error)
-	GetAgreementIDByAgreementURL(url string) (int64, error)
+	GenerateIncrementalAgreementID(ctx context.Context) int64
+	UpsertAgreement(ctx context.Context, agreement model.Aggrement)
+	GetAgreements(ctx context.Context) []model.Aggrement
+	GetAgreementsByFilter(ctx context.Context, filter model.AgreementFilter) []model.Aggrement
+	GetAgreementByAgreementID(ctx context.Context, agreementID int64) model.Aggrement
+	GetAgreementByPublicID(ctx context.Context, publicID string) model.Aggrement
+	GenerateBorrowerAgreementPDF(ctx context.Context, loan *model.Loan) error
+	GenerateLenderAgreementPDF(ctx context.Context, loan *model.Loan) error
+	GenerateSignedAgreementPDF(ctx context.Context, loan *model.Loan, agreement model.Aggrement) error
+	CheckAgreementCompletelySignedByLender(ctx context.Context, loan model.Loan) (bool, error)
+	OpenAgreementDocument(ctx context.Context, agreement model.Aggrement) (storage.Document, error)
+
+	// helper webhook
+	GenerateIncrementalWebhookSubscriptionID(ctx context.Context) int64
+	UpsertWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID int64)
+	GetWebhookSubscriptions(ctx context.Context) []model.WebhookSubscription
+	GetWebhookSubscriptionBySubscriptionID(ctx context.Context, subscriptionID int64) model.WebhookSubscription
+	GenerateIncrementalWebhookDeliveryID(ctx context.Context) int64
+	UpsertWebhookDeadLetter(ctx context.Context, delivery model.WebhookDelivery)
+	DeleteWebhookDeadLetter(ctx context.Context, deliveryID int64)
+	GetWebhookDeadLetters(ctx context.Context) []model.WebhookDelivery
+	GetWebhookDeadLetterByDeliveryID(ctx context.Context, deliveryID int64) model.WebhookDelivery
+
+	// helper notification
+	GenerateIncrementalNotificationID(ctx context.Context) int64
+	UpsertNotification(ctx context.Context, notification model.Notification)
+	GetNotificationsByUserID(ctx context.Context, userID int64) []model.Notification
+
+	// helper task
+	GenerateIncrementalTaskID(ctx context.Context) int64
+	UpsertTask(ctx context.Context, task model.Task)
+	GetTasksByFilter(ctx context.Context, filter model.TaskFilter) []model.Task
+
+	// helper audit
+	GetAuditEntries(ctx context.Context) []model.AuditEntry
+	GetAuditEntriesByFilter(ctx context.Context, filter model.AuditFilter) []model.AuditEntry
 }
 
 type Helper struct {
 	IHelper
+	Config        config.Config
+	DocumentStore storage.IDocumentStore
 }
 
-func NewHelper() *Helper {
-	return &Helper{}
+func NewHelper(cfg config.Config, documentStore storage.IDocumentStore) *Helper {
+	return &Helper{
+		Config:        cfg,
+		DocumentStore: documentStore,
+	}
 }
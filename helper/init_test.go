@@ -4,10 +4,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"amartha-test/config"
+	"amartha-test/storage"
 )
 
 func TestNewHelper(t *testing.T) {
-	helper := NewHelper()
+	helper := NewHelper(config.Default(), storage.NewMemoryDocumentStore())
 
 	assert.NotNil(t, helper)
 }
@@ -3,9 +3,13 @@
 package mocks
 
 import (
-	model "amartha-test/model"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	model "amartha-test/model"
+	picture "amartha-test/picture"
+	storage "amartha-test/storage"
 )
 
 // IHelper is an autogenerated mock type for the IHelper type
@@ -13,9 +17,9 @@ type IHelper struct {
 	mock.Mock
 }
 
-// CheckAgreementCompletelySignedByLender provides a mock function with given fields: loan
-func (_m *IHelper) CheckAgreementCompletelySignedByLender(loan model.Loan) (bool, error) {
-	ret := _m.Called(loan)
+// CheckAgreementCompletelySignedByLender provides a mock function with given fields: ctx, loan
+func (_m *IHelper) CheckAgreementCompletelySignedByLender(ctx context.Context, loan model.Loan) (bool, error) {
+	ret := _m.Called(ctx, loan)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckAgreementCompletelySignedByLender")
@@ -23,17 +27,17 @@ func (_m *IHelper) CheckAgreementCompletelySignedByLender(loan model.Loan) (bool
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.Loan) (bool, error)); ok {
-		return rf(loan)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Loan) (bool, error)); ok {
+		return rf(ctx, loan)
 	}
-	if rf, ok := ret.Get(0).(func(model.Loan) bool); ok {
-		r0 = rf(loan)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Loan) bool); ok {
+		r0 = rf(ctx, loan)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(model.Loan) error); ok {
-		r1 = rf(loan)
+	if rf, ok := ret.Get(1).(func(context.Context, model.Loan) error); ok {
+		r1 = rf(ctx, loan)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,17 +45,27 @@ func (_m *IHelper) CheckAgreementCompletelySignedByLender(loan model.Loan) (bool
 	return r0, r1
 }
 
-// GenerateBorrowerAgreementPDF provides a mock function with given fields: loan
-func (_m *IHelper) GenerateBorrowerAgreementPDF(loan *model.Loan) error {
-	ret := _m.Called(loan)
+// DeleteWebhookDeadLetter provides a mock function with given fields: ctx, deliveryID
+func (_m *IHelper) DeleteWebhookDeadLetter(ctx context.Context, deliveryID int64) {
+	_m.Called(ctx, deliveryID)
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, subscriptionID
+func (_m *IHelper) DeleteWebhookSubscription(ctx context.Context, subscriptionID int64) {
+	_m.Called(ctx, subscriptionID)
+}
+
+// GenerateBorrowerAgreementPDF provides a mock function with given fields: ctx, loan
+func (_m *IHelper) GenerateBorrowerAgreementPDF(ctx context.Context, loan *model.Loan) error {
+	ret := _m.Called(ctx, loan)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateBorrowerAgreementPDF")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Loan) error); ok {
-		r0 = rf(loan)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Loan) error); ok {
+		r0 = rf(ctx, loan)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -59,17 +73,17 @@ func (_m *IHelper) GenerateBorrowerAgreementPDF(loan *model.Loan) error {
 	return r0
 }
 
-// GenerateIncrementalAgreementID provides a mock function with given fields:
-func (_m *IHelper) GenerateIncrementalAgreementID() int64 {
-	ret := _m.Called()
+// GenerateIncrementalAgreementID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalAgreementID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateIncrementalAgreementID")
 	}
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
@@ -77,17 +91,35 @@ func (_m *IHelper) GenerateIncrementalAgreementID() int64 {
 	return r0
 }
 
-// GenerateIncrementalLoanID provides a mock function with given fields:
-func (_m *IHelper) GenerateIncrementalLoanID() int64 {
-	ret := _m.Called()
+// GenerateIncrementalLoanID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalLoanID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateIncrementalLoanID")
 	}
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// GenerateIncrementalNotificationID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalNotificationID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIncrementalNotificationID")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
@@ -95,17 +127,35 @@ func (_m *IHelper) GenerateIncrementalLoanID() int64 {
 	return r0
 }
 
-// GenerateIncrementalUserID provides a mock function with given fields:
-func (_m *IHelper) GenerateIncrementalUserID() int64 {
-	ret := _m.Called()
+// GenerateIncrementalTaskID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalTaskID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIncrementalTaskID")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// GenerateIncrementalUserID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalUserID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateIncrementalUserID")
 	}
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
@@ -113,17 +163,53 @@ func (_m *IHelper) GenerateIncrementalUserID() int64 {
 	return r0
 }
 
-// GenerateLenderAgreementPDF provides a mock function with given fields: loan
-func (_m *IHelper) GenerateLenderAgreementPDF(loan *model.Loan) error {
-	ret := _m.Called(loan)
+// GenerateIncrementalWebhookDeliveryID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalWebhookDeliveryID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIncrementalWebhookDeliveryID")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// GenerateIncrementalWebhookSubscriptionID provides a mock function with given fields: ctx
+func (_m *IHelper) GenerateIncrementalWebhookSubscriptionID(ctx context.Context) int64 {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIncrementalWebhookSubscriptionID")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// GenerateLenderAgreementPDF provides a mock function with given fields: ctx, loan
+func (_m *IHelper) GenerateLenderAgreementPDF(ctx context.Context, loan *model.Loan) error {
+	ret := _m.Called(ctx, loan)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateLenderAgreementPDF")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Loan) error); ok {
-		r0 = rf(loan)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Loan) error); ok {
+		r0 = rf(ctx, loan)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -131,17 +217,17 @@ func (_m *IHelper) GenerateLenderAgreementPDF(loan *model.Loan) error {
 	return r0
 }
 
-// GenerateSignedAgreementPDF provides a mock function with given fields: loan, userID
-func (_m *IHelper) GenerateSignedAgreementPDF(loan *model.Loan, userID int64) error {
-	ret := _m.Called(loan, userID)
+// GenerateSignedAgreementPDF provides a mock function with given fields: ctx, loan, agreement
+func (_m *IHelper) GenerateSignedAgreementPDF(ctx context.Context, loan *model.Loan, agreement model.Aggrement) error {
+	ret := _m.Called(ctx, loan, agreement)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateSignedAgreementPDF")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Loan, int64) error); ok {
-		r0 = rf(loan, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Loan, model.Aggrement) error); ok {
+		r0 = rf(ctx, loan, agreement)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -149,17 +235,17 @@ func (_m *IHelper) GenerateSignedAgreementPDF(loan *model.Loan, userID int64) er
 	return r0
 }
 
-// GetAgreementByAgreementID provides a mock function with given fields: agreementID
-func (_m *IHelper) GetAgreementByAgreementID(agreementID int64) model.Aggrement {
-	ret := _m.Called(agreementID)
+// GetAgreementByAgreementID provides a mock function with given fields: ctx, agreementID
+func (_m *IHelper) GetAgreementByAgreementID(ctx context.Context, agreementID int64) model.Aggrement {
+	ret := _m.Called(ctx, agreementID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAgreementByAgreementID")
 	}
 
 	var r0 model.Aggrement
-	if rf, ok := ret.Get(0).(func(int64) model.Aggrement); ok {
-		r0 = rf(agreementID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.Aggrement); ok {
+		r0 = rf(ctx, agreementID)
 	} else {
 		r0 = ret.Get(0).(model.Aggrement)
 	}
@@ -167,45 +253,55 @@ func (_m *IHelper) GetAgreementByAgreementID(agreementID int64) model.Aggrement
 	return r0
 }
 
-// GetAgreementIDByAgreementURL provides a mock function with given fields: url
-func (_m *IHelper) GetAgreementIDByAgreementURL(url string) (int64, error) {
-	ret := _m.Called(url)
+// GetAgreementByPublicID provides a mock function with given fields: ctx, publicID
+func (_m *IHelper) GetAgreementByPublicID(ctx context.Context, publicID string) model.Aggrement {
+	ret := _m.Called(ctx, publicID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAgreementIDByAgreementURL")
+		panic("no return value specified for GetAgreementByPublicID")
 	}
 
-	var r0 int64
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
-		return rf(url)
-	}
-	if rf, ok := ret.Get(0).(func(string) int64); ok {
-		r0 = rf(url)
+	var r0 model.Aggrement
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Aggrement); ok {
+		r0 = rf(ctx, publicID)
 	} else {
-		r0 = ret.Get(0).(int64)
+		r0 = ret.Get(0).(model.Aggrement)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(url)
+	return r0
+}
+
+// GetAgreements provides a mock function with given fields: ctx
+func (_m *IHelper) GetAgreements(ctx context.Context) []model.Aggrement {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgreements")
+	}
+
+	var r0 []model.Aggrement
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Aggrement); ok {
+		r0 = rf(ctx)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Aggrement)
+		}
 	}
 
-	return r0, r1
+	return r0
 }
 
-// GetAgreements provides a mock function with given fields:
-func (_m *IHelper) GetAgreements() []model.Aggrement {
-	ret := _m.Called()
+// GetAgreementsByFilter provides a mock function with given fields: ctx, filter
+func (_m *IHelper) GetAgreementsByFilter(ctx context.Context, filter model.AgreementFilter) []model.Aggrement {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAgreements")
+		panic("no return value specified for GetAgreementsByFilter")
 	}
 
 	var r0 []model.Aggrement
-	if rf, ok := ret.Get(0).(func() []model.Aggrement); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, model.AgreementFilter) []model.Aggrement); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Aggrement)
@@ -215,17 +311,57 @@ func (_m *IHelper) GetAgreements() []model.Aggrement {
 	return r0
 }
 
-// GetLoanByLoanID provides a mock function with given fields: loanID
-func (_m *IHelper) GetLoanByLoanID(loanID int64) model.Loan {
-	ret := _m.Called(loanID)
+// GetAuditEntries provides a mock function with given fields: ctx
+func (_m *IHelper) GetAuditEntries(ctx context.Context) []model.AuditEntry {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEntries")
+	}
+
+	var r0 []model.AuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context) []model.AuditEntry); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEntry)
+		}
+	}
+
+	return r0
+}
+
+// GetAuditEntriesByFilter provides a mock function with given fields: ctx, filter
+func (_m *IHelper) GetAuditEntriesByFilter(ctx context.Context, filter model.AuditFilter) []model.AuditEntry {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEntriesByFilter")
+	}
+
+	var r0 []model.AuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuditFilter) []model.AuditEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEntry)
+		}
+	}
+
+	return r0
+}
+
+// GetLoanByLoanID provides a mock function with given fields: ctx, loanID
+func (_m *IHelper) GetLoanByLoanID(ctx context.Context, loanID int64) model.Loan {
+	ret := _m.Called(ctx, loanID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLoanByLoanID")
 	}
 
 	var r0 model.Loan
-	if rf, ok := ret.Get(0).(func(int64) model.Loan); ok {
-		r0 = rf(loanID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.Loan); ok {
+		r0 = rf(ctx, loanID)
 	} else {
 		r0 = ret.Get(0).(model.Loan)
 	}
@@ -233,17 +369,35 @@ func (_m *IHelper) GetLoanByLoanID(loanID int64) model.Loan {
 	return r0
 }
 
-// GetLoans provides a mock function with given fields:
-func (_m *IHelper) GetLoans() []model.Loan {
-	ret := _m.Called()
+// GetLoanByPublicID provides a mock function with given fields: ctx, publicID
+func (_m *IHelper) GetLoanByPublicID(ctx context.Context, publicID string) model.Loan {
+	ret := _m.Called(ctx, publicID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanByPublicID")
+	}
+
+	var r0 model.Loan
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Loan); ok {
+		r0 = rf(ctx, publicID)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	return r0
+}
+
+// GetLoans provides a mock function with given fields: ctx
+func (_m *IHelper) GetLoans(ctx context.Context) []model.Loan {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLoans")
 	}
 
 	var r0 []model.Loan
-	if rf, ok := ret.Get(0).(func() []model.Loan); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Loan); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Loan)
@@ -253,17 +407,77 @@ func (_m *IHelper) GetLoans() []model.Loan {
 	return r0
 }
 
-// GetUserByUserID provides a mock function with given fields: userID
-func (_m *IHelper) GetUserByUserID(userID int64) model.User {
-	ret := _m.Called(userID)
+// GetLoansByFilter provides a mock function with given fields: ctx, filter
+func (_m *IHelper) GetLoansByFilter(ctx context.Context, filter model.LoanFilter) []model.Loan {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoansByFilter")
+	}
+
+	var r0 []model.Loan
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoanFilter) []model.Loan); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Loan)
+		}
+	}
+
+	return r0
+}
+
+// GetNotificationsByUserID provides a mock function with given fields: ctx, userID
+func (_m *IHelper) GetNotificationsByUserID(ctx context.Context, userID int64) []model.Notification {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotificationsByUserID")
+	}
+
+	var r0 []model.Notification
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.Notification); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Notification)
+		}
+	}
+
+	return r0
+}
+
+// GetTasksByFilter provides a mock function with given fields: ctx, filter
+func (_m *IHelper) GetTasksByFilter(ctx context.Context, filter model.TaskFilter) []model.Task {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasksByFilter")
+	}
+
+	var r0 []model.Task
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskFilter) []model.Task); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	return r0
+}
+
+// GetUserByUserID provides a mock function with given fields: ctx, userID
+func (_m *IHelper) GetUserByUserID(ctx context.Context, userID int64) model.User {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByUserID")
 	}
 
 	var r0 model.User
-	if rf, ok := ret.Get(0).(func(int64) model.User); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.User); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Get(0).(model.User)
 	}
@@ -271,17 +485,37 @@ func (_m *IHelper) GetUserByUserID(userID int64) model.User {
 	return r0
 }
 
-// GetUsers provides a mock function with given fields:
-func (_m *IHelper) GetUsers() []model.User {
-	ret := _m.Called()
+// GetUsers provides a mock function with given fields: ctx
+func (_m *IHelper) GetUsers(ctx context.Context) []model.User {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
 	}
 
 	var r0 []model.User
-	if rf, ok := ret.Get(0).(func() []model.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []model.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	return r0
+}
+
+// GetUsersByFilter provides a mock function with given fields: ctx, filter
+func (_m *IHelper) GetUsersByFilter(ctx context.Context, filter model.UserFilter) []model.User {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByFilter")
+	}
+
+	var r0 []model.User
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserFilter) []model.User); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
@@ -291,19 +525,199 @@ func (_m *IHelper) GetUsers() []model.User {
 	return r0
 }
 
-// InitUsers provides a mock function with given fields:
-func (_m *IHelper) InitUsers() {
-	_m.Called()
+// GetWebhookDeadLetterByDeliveryID provides a mock function with given fields: ctx, deliveryID
+func (_m *IHelper) GetWebhookDeadLetterByDeliveryID(ctx context.Context, deliveryID int64) model.WebhookDelivery {
+	ret := _m.Called(ctx, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeadLetterByDeliveryID")
+	}
+
+	var r0 model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.WebhookDelivery); ok {
+		r0 = rf(ctx, deliveryID)
+	} else {
+		r0 = ret.Get(0).(model.WebhookDelivery)
+	}
+
+	return r0
+}
+
+// GetWebhookDeadLetters provides a mock function with given fields: ctx
+func (_m *IHelper) GetWebhookDeadLetters(ctx context.Context) []model.WebhookDelivery {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeadLetters")
+	}
+
+	var r0 []model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context) []model.WebhookDelivery); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebhookDelivery)
+		}
+	}
+
+	return r0
+}
+
+// GetWebhookSubscriptionBySubscriptionID provides a mock function with given fields: ctx, subscriptionID
+func (_m *IHelper) GetWebhookSubscriptionBySubscriptionID(ctx context.Context, subscriptionID int64) model.WebhookSubscription {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookSubscriptionBySubscriptionID")
+	}
+
+	var r0 model.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.WebhookSubscription); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Get(0).(model.WebhookSubscription)
+	}
+
+	return r0
+}
+
+// GetWebhookSubscriptions provides a mock function with given fields: ctx
+func (_m *IHelper) GetWebhookSubscriptions(ctx context.Context) []model.WebhookSubscription {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookSubscriptions")
+	}
+
+	var r0 []model.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context) []model.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebhookSubscription)
+		}
+	}
+
+	return r0
+}
+
+// InitUsers provides a mock function with given fields: ctx
+func (_m *IHelper) InitUsers(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// OpenAgreementDocument provides a mock function with given fields: ctx, agreement
+func (_m *IHelper) OpenAgreementDocument(ctx context.Context, agreement model.Aggrement) (storage.Document, error) {
+	ret := _m.Called(ctx, agreement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenAgreementDocument")
+	}
+
+	var r0 storage.Document
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Aggrement) (storage.Document, error)); ok {
+		return rf(ctx, agreement)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Aggrement) storage.Document); ok {
+		r0 = rf(ctx, agreement)
+	} else {
+		r0 = ret.Get(0).(storage.Document)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Aggrement) error); ok {
+		r1 = rf(ctx, agreement)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenPictureProof provides a mock function with given fields: ctx, loan, thumbnail
+func (_m *IHelper) OpenPictureProof(ctx context.Context, loan model.Loan, thumbnail bool) (storage.Document, error) {
+	ret := _m.Called(ctx, loan, thumbnail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenPictureProof")
+	}
+
+	var r0 storage.Document
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Loan, bool) (storage.Document, error)); ok {
+		return rf(ctx, loan, thumbnail)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Loan, bool) storage.Document); ok {
+		r0 = rf(ctx, loan, thumbnail)
+	} else {
+		r0 = ret.Get(0).(storage.Document)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Loan, bool) error); ok {
+		r1 = rf(ctx, loan, thumbnail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutPictureProof provides a mock function with given fields: ctx, proof
+func (_m *IHelper) PutPictureProof(ctx context.Context, proof picture.Picture) (model.PictureProof, error) {
+	ret := _m.Called(ctx, proof)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutPictureProof")
+	}
+
+	var r0 model.PictureProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, picture.Picture) (model.PictureProof, error)); ok {
+		return rf(ctx, proof)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, picture.Picture) model.PictureProof); ok {
+		r0 = rf(ctx, proof)
+	} else {
+		r0 = ret.Get(0).(model.PictureProof)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, picture.Picture) error); ok {
+		r1 = rf(ctx, proof)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertAgreement provides a mock function with given fields: ctx, agreement
+func (_m *IHelper) UpsertAgreement(ctx context.Context, agreement model.Aggrement) {
+	_m.Called(ctx, agreement)
+}
+
+// UpsertLoan provides a mock function with given fields: ctx, loan
+func (_m *IHelper) UpsertLoan(ctx context.Context, loan model.Loan) {
+	_m.Called(ctx, loan)
+}
+
+// UpsertNotification provides a mock function with given fields: ctx, notification
+func (_m *IHelper) UpsertNotification(ctx context.Context, notification model.Notification) {
+	_m.Called(ctx, notification)
+}
+
+// UpsertTask provides a mock function with given fields: ctx, task
+func (_m *IHelper) UpsertTask(ctx context.Context, task model.Task) {
+	_m.Called(ctx, task)
 }
 
-// UpsertAgreement provides a mock function with given fields: agreement
-func (_m *IHelper) UpsertAgreement(agreement model.Aggrement) {
-	_m.Called(agreement)
+// UpsertWebhookDeadLetter provides a mock function with given fields: ctx, delivery
+func (_m *IHelper) UpsertWebhookDeadLetter(ctx context.Context, delivery model.WebhookDelivery) {
+	_m.Called(ctx, delivery)
 }
 
-// UpsertLoan provides a mock function with given fields: loan
-func (_m *IHelper) UpsertLoan(loan model.Loan) {
-	_m.Called(loan)
+// UpsertWebhookSubscription provides a mock function with given fields: ctx, subscription
+func (_m *IHelper) UpsertWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription) {
+	_m.Called(ctx, subscription)
 }
 
 // NewIHelper creates a new instance of IHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a stored response is replayed for its key
+const DefaultTTL = 24 * time.Hour
+
+// pruneInterval is how often the memory store drops the expired records
+const pruneInterval = time.Minute
+
+// Record is the first response stored for an idempotency key
+type Record struct {
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// IStore keeps the first response per idempotency key
+type IStore interface {
+	// Begin reserves the key for the request hash, when the key is already reserved
+	// the existing record is returned with exists true and nothing is reserved
+	Begin(key string, requestHash string) (record Record, exists bool)
+	// Complete stores the response of the reserved key
+	Complete(key string, record Record)
+	// Release removes the reservation so the request can be retried
+	Release(key string)
+}
+
+// MemoryStore is an in-memory IStore, records expire after ttl
+type MemoryStore struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	records   map[string]Record
+	lastPrune time.Time
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:     ttl,
+		now:     time.Now,
+		records: make(map[string]Record),
+	}
+}
+
+func (s *MemoryStore) Begin(key string, requestHash string) (Record, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	// an expired record not pruned yet is replaced
+	record, exists := s.records[key]
+	if exists && !s.isExpired(record, now) {
+		return record, true
+	}
+
+	s.records[key] = Record{
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}
+
+	return Record{}, false
+}
+
+func (s *MemoryStore) Complete(key string, record Record) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, exists := s.records[key]
+	if !exists {
+		return
+	}
+
+	record.RequestHash = existing.RequestHash
+	record.CreatedAt = existing.CreatedAt
+	record.Completed = true
+	s.records[key] = record
+}
+
+func (s *MemoryStore) Release(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+}
+
+// prune removes the expired records at most once per pruneInterval, must be called with the lock held
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for k, v := range s.records {
+		if s.isExpired(v, now) {
+			delete(s.records, k)
+		}
+	}
+}
+
+// isExpired tells whether the record is older than the ttl
+func (s *MemoryStore) isExpired(record Record, now time.Time) bool {
+	return now.Sub(record.CreatedAt) > s.ttl
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	now := time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	// first request reserves the key
+	_, exists := store.Begin("actor|key", "hash")
+	assert.False(t, exists)
+
+	// concurrent retry sees the in progress reservation
+	record, exists := store.Begin("actor|key", "hash")
+	assert.True(t, exists)
+	assert.False(t, record.Completed)
+	assert.Equal(t, "hash", record.RequestHash)
+
+	// completed response is replayed
+	store.Complete("actor|key", Record{StatusCode: http.StatusCreated, Body: []byte(`{"code":201}`)})
+	record, exists = store.Begin("actor|key", "other-hash")
+	assert.True(t, exists)
+	assert.True(t, record.Completed)
+	assert.Equal(t, "hash", record.RequestHash)
+	assert.Equal(t, http.StatusCreated, record.StatusCode)
+
+	// released key can be reserved again
+	store.Release("actor|key")
+	_, exists = store.Begin("actor|key", "hash")
+	assert.False(t, exists)
+
+	// expired record is replaced
+	now = now.Add(2 * time.Hour)
+	_, exists = store.Begin("actor|key", "hash")
+	assert.False(t, exists)
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	start := time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+
+	store.Begin("first", "hash")
+	now = start.Add(59*time.Minute + 59*time.Second)
+	store.Begin("second", "hash")
+
+	// expired record is kept until the next prune, records are not walked on every request
+	now = start.Add(60*time.Minute + 30*time.Second)
+	store.Begin("third", "hash")
+	assert.Len(t, store.records, 3)
+
+	now = start.Add(61 * time.Minute)
+	store.Begin("fourth", "hash")
+	assert.Len(t, store.records, 3)
+	assert.NotContains(t, store.records, "first")
+}
+
+func TestMemoryStoreCompleteUnknownKey(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+
+	store.Complete("unknown", Record{StatusCode: http.StatusOK})
+
+	_, exists := store.Begin("unknown", "hash")
+	assert.False(t, exists)
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// list of log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Redacted replaces the value of every secret attribute
+const Redacted = "[REDACTED]"
+
+// secretKeys are the attribute keys never written to the logs, matched case-insensitively at any depth
+var secretKeys = map[string]bool{
+	"picture_proof": true,
+	"pictureproof":  true,
+	"secret":        true,
+	"password":      true,
+	"smtp_password": true,
+	"authorization": true,
+}
+
+type ctxKeyLogger struct{}
+
+// New returns the logger writing to w in format, json or text, from level on. Secret attributes are redacted
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}
+
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error"
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(name))
+	if err != nil {
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+
+	return level, nil
+}
+
+// WithLogger returns the context carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKeyLogger{}, logger)
+}
+
+// FromContext returns the logger carried by the context, the default logger when there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(ctxKeyLogger{}).(*slog.Logger)
+	if !ok {
+		return slog.Default()
+	}
+
+	return logger
+}
+
+// With returns the context carrying its logger with the attributes added, e.g. With(ctx, "loan_id", loanID)
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// redact replaces the secret attributes, structs, maps and slices are walked through their json form
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if isSecretKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	v := a.Value.Any()
+	if _, ok := v.(error); ok {
+		return a
+	}
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return a
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return a
+	}
+	var generic any
+	err = json.Unmarshal(content, &generic)
+	if err != nil {
+		return a
+	}
+
+	return slog.Any(a.Key, redactValue(generic))
+}
+
+func redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if isSecretKey(k) {
+				value[k] = Redacted
+				continue
+			}
+			value[k] = redactValue(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+
+	return v
+}
+
+func isSecretKey(key string) bool {
+	return secretKeys[strings.ToLower(key)]
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/model"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          slog.Level
+		format         string
+		isError        bool
+		expectedOutput string
+	}{
+		{
+			name:           "success - json",
+			level:          slog.LevelInfo,
+			format:         FormatJSON,
+			expectedOutput: `"msg":"loan data is not found","loan_id":1`,
+		},
+		{
+			name:           "success - text",
+			level:          slog.LevelInfo,
+			format:         FormatText,
+			expectedOutput: `msg="loan data is not found" loan_id=1`,
+		},
+		{
+			name:   "success - below level",
+			level:  slog.LevelWarn,
+			format: FormatJSON,
+		},
+		{
+			name:    "error - unknown format",
+			format:  "xml",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			// main func
+			logger, err := New(&buf, tt.level, tt.format)
+
+			assert.Equal(t, tt.isError, err != nil)
+			if tt.isError {
+				return
+			}
+			logger.Info("loan data is not found", "loan_id", 1)
+			if tt.expectedOutput == "" {
+				assert.Empty(t, buf.String())
+			} else {
+				assert.Contains(t, buf.String(), tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, FormatJSON)
+	assert.NoError(t, err)
+
+	// main func
+	logger.Info("approve loan",
+		"picture_proof", "aW1hZ2U=",
+		"secret", "whsec_secret",
+		"approval_info", model.ApprovalInfo{PictureProof: "aW1hZ2U=", FieldValidatorEmployeeID: 4},
+		"loans", []model.Loan{{LoanID: 1, ApprovalInfo: &model.ApprovalInfo{PictureProof: "aW1hZ2U="}}},
+	)
+
+	assert.NotContains(t, buf.String(), "aW1hZ2U=")
+	assert.NotContains(t, buf.String(), "whsec_secret")
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, Redacted, entry["picture_proof"])
+	assert.Equal(t, float64(4), entry["approval_info"].(map[string]any)["field_validator_employee_id"])
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, FormatJSON)
+	assert.NoError(t, err)
+	ctx := WithLogger(context.Background(), logger.With("request_id", "req-1"))
+
+	// main func
+	ctx = With(ctx, "loan_id", 1)
+	FromContext(ctx).Info("loan data is not found")
+
+	assert.Contains(t, buf.String(), `"request_id":"req-1","loan_id":1`)
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+}
@@ -1,45 +1,314 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 
+	"amartha-test/auth"
+	"amartha-test/config"
+	"amartha-test/grpcapi"
 	hand "amartha-test/handler"
 	help "amartha-test/helper"
+	"amartha-test/idempotency"
+	"amartha-test/logging"
+	"amartha-test/metrics"
+	"amartha-test/model"
+	"amartha-test/notification"
+	"amartha-test/ratelimit"
+	"amartha-test/service"
+	"amartha-test/storage"
+	"amartha-test/task"
+	"amartha-test/tracing"
+	"amartha-test/webhook"
 )
 
+// workerStopTimeout bounds the stop of the workers after their drain, a stopped worker cuts its in-flight
+// sends and dead-letters the webhook deliveries left, which takes no time
+const workerStopTimeout = time.Second
+
 func main() {
+	// init config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed load config with error: %+v", err)
+	}
+
+	// init authenticator, the signing secret is shared with the token command
+	secret, err := auth.LoadOrCreateSecret(cfg.AuthSecretFile)
+	if err != nil {
+		log.Fatalf("failed load auth secret with error: %+v", err)
+	}
+	authenticator := auth.NewAuthenticator(secret, time.Duration(cfg.AuthTokenTTL))
+
+	// "amartha token <user_id>" prints the bearer token of the user instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		issueToken(cfg, authenticator, os.Args[2:])
+		return
+	}
+
+	// init logger, every log is structured and carries the request attributes through the request context
+	logLevel, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("failed parse log level with error: %+v", err)
+	}
+	logger, err := logging.New(os.Stdout, logLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("failed init logger with error: %+v", err)
+	}
+	slog.SetDefault(logger)
+
+	// init tracing, exporting the spans of every request, helper call and pdf render
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TraceExporter, cfg.TraceEndpoint, os.Stdout)
+	if err != nil {
+		fatal("failed init tracing", "error", err)
+	}
+
+	// init document store
+	documentStore, err := storage.NewLocalDocumentStore(cfg.DocumentDir)
+	if err != nil {
+		fatal("failed init document store", "error", err)
+	}
+
 	// init helper
-	helper := help.NewHelper()
-	helper.InitUsers()
+	helper := help.NewHelper(cfg, documentStore)
+	helper.InitUsers(context.Background())
+
+	// init service
+	svc := service.NewService(helper)
+	svc.Visits = service.VisitPolicy{
+		Required:          cfg.VisitRequired,
+		MaxDistanceMeters: cfg.VisitMaxDistanceMeters,
+		MaxAge:            time.Duration(cfg.VisitMaxAge),
+		OutOfRange:        cfg.VisitOutOfRange,
+	}
+
+	// init background workers, drained and stopped after the servers on shutdown so the events of the last requests are still handled
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
+	// init metrics, the business gauges are read from the service on every scrape
+	metric := metrics.New(func() model.Stats {
+		return svc.GetStats(context.Background())
+	})
+	svc.Metrics = metric
+
+	// init webhook dispatcher, delivering the service events to the subscribed webhooks on public addresses only
+	webhookGuard := webhook.NewGuard()
+	webhookGuard.AllowPrivate = cfg.WebhookAllowPrivate
+	if cfg.WebhookAllowPrivate {
+		logger.Warn("webhooks are allowed to reach private addresses")
+	}
+	svc.WebhookGuard = webhookGuard
+	dispatcher := webhook.NewDispatcher(helper)
+	dispatcher.Client = webhookGuard.Client(webhook.DefaultTimeout)
+	svc.Webhooks = dispatcher
+	svc.Events.Subscribe(dispatcher.Handle)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		dispatcher.Run(workerCtx, webhook.DefaultWorkers)
+	}()
+
+	// init task assigner, queueing the field visits of the loans for the validators and officers
+	assigner := task.NewAssigner(helper)
+	svc.Events.Subscribe(assigner.Handle)
+
+	// init notifier, telling borrowers and lenders about their loans on the in-app inbox, email and sms
+	var channels []notification.IChannel
+	if cfg.SMTPAddr != "" {
+		channels = append(channels, notification.NewEmailChannel(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword))
+	}
+	if cfg.SMSGatewayURL != "" {
+		channels = append(channels, notification.NewSMSChannel(cfg.SMSGatewayURL))
+	}
+	notifier := notification.NewNotifier(helper, notification.NewInboxChannel(helper), channels...)
+	svc.Events.Subscribe(notifier.Handle)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		notifier.Run(workerCtx)
+	}()
 
 	// init handler
+	trustedProxies, err := cfg.TrustedProxyPrefixes()
+	if err != nil {
+		fatal("failed parse trusted proxies", "error", err)
+	}
 	handler := &hand.Handler{
-		Helper: helper,
+		Service:          svc,
+		IdempotencyStore: idempotency.NewMemoryStore(idempotency.DefaultTTL),
+		Metrics:          metric,
+		DocumentStore:    documentStore,
+		RateLimitStore:   ratelimit.NewMemoryStore(),
+		Authenticator:    authenticator,
+		TrustedProxies:   trustedProxies,
 	}
 
-	// init router
-	router := mux.NewRouter()
+	// init router, wrapped by the middleware chain from the outermost
+	router := handler.Router()
+	middlewares := []func(http.Handler) http.Handler{
+		handler.MeasureRequests(router),
+		handler.Trace(router),
+		handler.MeasureLatency,
+		handler.RequestID,
+		handler.Authenticate,
+		handler.Logger(logger),
+		handler.AccessLog,
+		handler.Recover,
+		handler.Actor,
+		handler.RateLimit(router, cfg.RateLimits),
+		handler.BodyLimit(cfg.MaxBodyBytes),
+		handler.Timeout(time.Duration(cfg.RequestTimeout)),
+	}
+	if cfg.InjectLatency > 0 {
+		logger.Warn("injecting latency on every request", "latency", time.Duration(cfg.InjectLatency).String())
+		middlewares = append(middlewares, handler.InjectLatency(time.Duration(cfg.InjectLatency)))
+	}
+	chain := hand.Chain(router, middlewares...)
 
-	// list of user routes
-	router.HandleFunc("/user/list", handler.Middleware(handler.ListUser)).Methods("GET")
-	router.HandleFunc("/user/{user_id}/detail", handler.Middleware(handler.DetailUser)).Methods("GET")
+	// init grpc server
+	grpcListener, err := net.Listen("tcp", cfg.GRPCListenAddr)
+	if err != nil {
+		fatal("failed listen grpc", "addr", cfg.GRPCListenAddr, "error", err)
+	}
+	grpcServer := grpcapi.NewServer(svc, authenticator)
+	go func() {
+		logger.Info("listening grpc server", "addr", cfg.GRPCListenAddr)
+		err := grpcServer.Serve(grpcListener)
+		if err != nil {
+			fatal("failed serve grpc", "error", err)
+		}
+	}()
 
-	// list of loan routes
-	router.HandleFunc("/loan/list", handler.Middleware(handler.ListLoan)).Methods("GET")
-	router.HandleFunc("/loan/{loan_id}/detail", handler.Middleware(handler.DetailLoan)).Methods("GET")
-	router.HandleFunc("/loan/submit", handler.Middleware(handler.SubmitLoan)).Methods("POST")
-	router.HandleFunc("/loan/{loan_id}/approve", handler.Middleware(handler.ApproveLoan)).Methods("POST")
-	router.HandleFunc("/loan/{loan_id}/invest", handler.Middleware(handler.InvestLoan)).Methods("POST")
-	router.HandleFunc("/loan/{loan_id}/disburse", handler.Middleware(handler.DisburseLoan)).Methods("POST")
+	// init http server, shutdown does not wait for the event streams so they are closed explicitly
+	server := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           chain,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout),
+		ReadTimeout:       time.Duration(cfg.ReadTimeout),
+		WriteTimeout:      time.Duration(cfg.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.IdleTimeout),
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+	server.RegisterOnShutdown(handler.CloseStreams)
+	go func() {
+		logger.Info("listening server", "addr", cfg.ListenAddr, "public_base_url", cfg.PublicBaseURL)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("failed serve http", "error", err)
+		}
+	}()
+
+	// shutdown on interrupt or SIGTERM, in order: stop routing, drain http, stop grpc, drain and stop workers, flush spans
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	logger.Info("shutting down server", "drain_delay", time.Duration(cfg.DrainDelay).String())
+	handler.Drain()
+	time.Sleep(time.Duration(cfg.DrainDelay))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error("failed shutdown http server", "error", err)
+	}
+
+	stopGRPC(shutdownCtx, grpcServer)
+
+	err = dispatcher.Drain(shutdownCtx)
+	if err != nil {
+		logger.Error("failed drain webhook deliveries, dead-lettering the rest", "error", err)
+	}
+	err = notifier.Drain(shutdownCtx)
+	if err != nil {
+		logger.Error("failed drain notifications, dropping the rest", "error", err)
+	}
+	stopWorkers()
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), workerStopTimeout)
+	defer cancelStop()
+	err = wait(stopCtx, &workers)
+	if err != nil {
+		logger.Error("failed stop background workers", "error", err)
+	}
 
-	// list of agreement routes
-	router.HandleFunc("/agreement/list", handler.Middleware(handler.ListAgreement)).Methods("GET")
-	router.HandleFunc("/agreement/{agreement_id}/view", handler.Middleware(handler.ViewAgreement)).Methods("GET")
-	router.HandleFunc("/agreement/{agreement_id}/sign", handler.Middleware(handler.SignAgreement)).Methods("POST")
+	err = shutdownTracing(shutdownCtx)
+	if err != nil {
+		logger.Error("failed flush spans", "error", err)
+	}
+	logger.Info("server stopped")
+}
+
+// issueToken prints the bearer token of the seeded user of args, for the clients of the api and local runs
+func issueToken(cfg config.Config, authenticator *auth.Authenticator, args []string) {
+	if len(args) != 1 {
+		log.Fatalf("usage: amartha token <user_id>")
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		log.Fatalf("failed parse user id with error: %+v", err)
+	}
+
+	helper := help.NewHelper(cfg, nil)
+	helper.InitUsers(context.Background())
+	if helper.GetUserByUserID(context.Background(), userID).UserID == 0 {
+		log.Fatalf("failed get user with error: user %d is not found", userID)
+	}
+
+	token, expiresAt := authenticator.Issue(userID, time.Now())
+	fmt.Println(token)
+	fmt.Fprintf(os.Stderr, "expires at %s\n", expiresAt.Format(time.RFC3339))
+}
+
+// stopGRPC lets the in-flight grpc calls finish, and cuts them when ctx is done first
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Error("failed graceful stop grpc server", "error", ctx.Err())
+		grpcServer.Stop()
+	}
+}
+
+// wait waits for the wait group until ctx is done
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-	fmt.Println("listening server on port :8080")
-	http.ListenAndServe(":8080", router)
+// fatal logs the failure the server can not run without, and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"amartha-test/constant"
+	"amartha-test/model"
+)
+
+// businessCollector exposes the loan and agreement gauges, computed from the current stats when scraped
+type businessCollector struct {
+	stats func() model.Stats
+
+	loans                      *prometheus.Desc
+	principalFunded            *prometheus.Desc
+	agreementsPendingSignature *prometheus.Desc
+}
+
+func newBusinessCollector(stats func() model.Stats) *businessCollector {
+	return &businessCollector{
+		stats: stats,
+		loans: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "loans"),
+			"Count of loans, per loan status.", []string{"status"}, nil),
+		principalFunded: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "loan_principal_funded"),
+			"Principal amount invested by lenders over every loan.", nil, nil),
+		agreementsPendingSignature: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "agreements_pending_signature"),
+			"Count of generated agreements not signed yet.", nil, nil),
+	}
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.loans
+	ch <- c.principalFunded
+	ch <- c.agreementsPendingSignature
+}
+
+// Collect reports every loan status, statuses without loans as zero
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+
+	for status, desc := range constant.LoanStatusDesc {
+		ch <- prometheus.MustNewConstMetric(c.loans, prometheus.GaugeValue, float64(stats.LoanCountByStatus[status]), desc)
+	}
+	ch <- prometheus.MustNewConstMetric(c.principalFunded, prometheus.GaugeValue, stats.PrincipalFunded)
+	ch <- prometheus.MustNewConstMetric(c.agreementsPendingSignature, prometheus.GaugeValue, float64(stats.AgreementsPendingSignature))
+}
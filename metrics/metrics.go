@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"amartha-test/model"
+)
+
+const namespace = "amartha"
+
+// IMetrics records what the service and the handlers observe
+type IMetrics interface {
+	ObserveRequest(method string, route string, statusCode int, duration time.Duration)
+	RecordInvestRejection(reason string)
+}
+
+var _ IMetrics = (*Metrics)(nil)
+
+// Metrics are the prometheus metrics of the server, registered on a registry of their own so they can be
+// gathered in tests without a running prometheus
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	investRejections *prometheus.CounterVec
+}
+
+// New returns the registered metrics, the business gauges are read from stats on every scrape
+func New(stats func() model.Stats) *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Count of http requests served, per route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of http requests, per route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		investRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "invest_rejections_total",
+			Help:      "Count of rejected investments, per error code.",
+		}, []string{"reason"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.investRejections,
+		newBusinessCollector(stats),
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest counts the request and observes its duration, route is the route template to keep the label bounded
+func (m *Metrics) ObserveRequest(method string, route string, statusCode int, duration time.Duration) {
+	status := strconv.Itoa(statusCode)
+	m.requests.WithLabelValues(method, route, status).Inc()
+	m.requestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// RecordInvestRejection counts the rejected investment, reason is the error code
+func (m *Metrics) RecordInvestRejection(reason string) {
+	m.investRejections.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/constant"
+	"amartha-test/model"
+)
+
+func TestObserveRequest(t *testing.T) {
+	m := New(func() model.Stats { return model.Stats{} })
+
+	// main func
+	m.ObserveRequest("GET", "/v1/loan/{loan_id}/detail", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest("GET", "/v1/loan/{loan_id}/detail", http.StatusOK, 40*time.Millisecond)
+	m.ObserveRequest("GET", "/v1/loan/{loan_id}/detail", http.StatusNotFound, time.Millisecond)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/v1/loan/{loan_id}/detail", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/v1/loan/{loan_id}/detail", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.requestDuration))
+}
+
+func TestRecordInvestRejection(t *testing.T) {
+	m := New(func() model.Stats { return model.Stats{} })
+
+	// main func
+	m.RecordInvestRejection("invested_amount_exceeded")
+	m.RecordInvestRejection("invested_amount_exceeded")
+	m.RecordInvestRejection("invalid_loan_status")
+
+	err := testutil.CollectAndCompare(m.investRejections, strings.NewReader(`
+# HELP amartha_invest_rejections_total Count of rejected investments, per error code.
+# TYPE amartha_invest_rejections_total counter
+amartha_invest_rejections_total{reason="invalid_loan_status"} 1
+amartha_invest_rejections_total{reason="invested_amount_exceeded"} 2
+`))
+	assert.NoError(t, err)
+}
+
+func TestBusinessCollector(t *testing.T) {
+	m := New(func() model.Stats {
+		return model.Stats{
+			LoanCountByStatus:          map[int]int{constant.LoanStatusApproved: 2, constant.LoanStatusInvested: 1},
+			PrincipalFunded:            1500,
+			AgreementsPendingSignature: 3,
+		}
+	})
+
+	// main func
+	err := testutil.GatherAndCompare(m.Registry, strings.NewReader(`
+# HELP amartha_agreements_pending_signature Count of generated agreements not signed yet.
+# TYPE amartha_agreements_pending_signature gauge
+amartha_agreements_pending_signature 3
+# HELP amartha_loan_principal_funded Principal amount invested by lenders over every loan.
+# TYPE amartha_loan_principal_funded gauge
+amartha_loan_principal_funded 1500
+# HELP amartha_loans Count of loans, per loan status.
+# TYPE amartha_loans gauge
+amartha_loans{status="approved"} 2
+amartha_loans{status="disbursed"} 0
+amartha_loans{status="invested"} 1
+amartha_loans{status="proposed"} 0
+amartha_loans{status="signed"} 0
+`), "amartha_agreements_pending_signature", "amartha_loan_principal_funded", "amartha_loans")
+	assert.NoError(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	m := New(func() model.Stats { return model.Stats{} })
+	m.ObserveRequest("POST", "/v1/loan/submit", http.StatusCreated, time.Millisecond)
+	w := httptest.NewRecorder()
+
+	// main func
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `amartha_http_requests_total{method="POST",route="/v1/loan/submit",status="201"} 1`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
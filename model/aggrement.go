@@ -1,13 +1,66 @@
 package model
 
+import "time"
+
 type Aggrement struct {
-	AggrementID  int64  `json:"aggrement_id"`
-	DocumentData string `json:"document_data"`
-	UserID       int64  `json:"user_id"`
-	IsSigned     bool   `json:"is_signed"`
+	// AggrementID and LoanID are the sequential ids inside the service, they are never served
+	AggrementID int64 `json:"-"`
+	// PublicID is the opaque id the api identifies the agreement by, it is not a secret, viewing and signing
+	// are checked against the authenticated user
+	PublicID string `json:"agreement_id"`
+	LoanID   int64  `json:"-"`
+	// LoanPublicID is the public id of the loan of the agreement
+	LoanPublicID      string `json:"loan_id"`
+	AgreementType     int    `json:"agreement_type"`
+	AgreementTypeDesc string `json:"agreement_type_desc"`
+	SupersedesID      int64  `json:"-"`
+	// SupersedesPublicID is the public id of the agreement superseded by this one, empty when it supersedes none
+	SupersedesPublicID string    `json:"supersedes_id,omitempty"`
+	DocumentKey        string    `json:"document_key"`
+	UserID             int64     `json:"user_id"`
+	IsSigned           bool      `json:"is_signed"`
+	TemplateVersion    string    `json:"template_version"`
+	Locale             string    `json:"locale"`
+	CreatedAt          time.Time `json:"created_at"`
+	// SignedAt is nil until the agreement is signed
+	SignedAt *time.Time `json:"signed_at,omitempty"`
 }
 
+// Sign is the body of agreement sign, the signer is the authenticated user
 type Sign struct {
-	LoanID int64 `json:"loan_id"`
-	UserID int64 `json:"user_id"`
+	LoanID string `json:"loan_id"`
+}
+
+// AgreementFilter is filter for agreement list, zero value fields are ignored
+type AgreementFilter struct {
+	LoanID int64
+	// LoanPublicID is the loan_id filter of the api
+	LoanPublicID  string
+	UserID        int64
+	AgreementType int
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+}
+
+func (f AgreementFilter) Match(agreement Aggrement) bool {
+	if f.LoanID != 0 && agreement.LoanID != f.LoanID {
+		return false
+	}
+	if f.LoanPublicID != "" && agreement.LoanPublicID != f.LoanPublicID {
+		return false
+	}
+	if f.UserID != 0 && agreement.UserID != f.UserID {
+		return false
+	}
+	if f.AgreementType != 0 && agreement.AgreementType != f.AgreementType {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && agreement.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && agreement.CreatedAt.After(f.CreatedTo) {
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is a write recorded in the append-only audit log, chained to the previous entry by its hash
+type AuditEntry struct {
+	Sequence   int64  `json:"sequence"`
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	// TargetID is the id the api serves the target by, the public id of loans and agreements
+	TargetID string `json:"target_id"`
+	// Before is the target before the write, null when created
+	Before json.RawMessage `json:"before"`
+	// After is the target after the write, null when deleted
+	After     json.RawMessage `json:"after"`
+	Changes   []AuditChange   `json:"changes"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	// PrevHash is the hash of the previous entry, a tampered entry breaks the hash of every following entry
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditChange is a field changed by the write, nested fields are joined by a dot
+type AuditChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditFilter is filter for audit log, zero value fields are ignored
+type AuditFilter struct {
+	Actor       string
+	Action      string
+	TargetType  string
+	TargetID    string
+	RequestID   string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// AuditVerification is the result of recomputing the hash chain of the audit log
+type AuditVerification struct {
+	IsValid    bool `json:"is_valid"`
+	EntryCount int  `json:"entry_count"`
+	// BrokenSequence is the first entry whose hash does not match, zero when valid
+	BrokenSequence int64  `json:"broken_sequence,omitempty"`
+	LastHash       string `json:"last_hash"`
+}
+
+func (f AuditFilter) Match(entry AuditEntry) bool {
+	if f.Actor != "" && entry.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" && entry.Action != f.Action {
+		return false
+	}
+	if f.TargetType != "" && entry.TargetType != f.TargetType {
+		return false
+	}
+	if f.TargetID != "" && entry.TargetID != f.TargetID {
+		return false
+	}
+	if f.RequestID != "" && entry.RequestID != f.RequestID {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && entry.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && entry.CreatedAt.After(f.CreatedTo) {
+		return false
+	}
+
+	return true
+}
@@ -5,4 +5,5 @@ import "time"
 type Disbursement struct {
 	FieldOfficerID   int64     `json:"field_officer_id"`
 	DisbursementDate time.Time `json:"disbursement_date"`
+	Visit            *Visit    `json:"visit,omitempty"`
 }
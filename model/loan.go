@@ -5,37 +5,81 @@ import (
 )
 
 type Loan struct {
-	LoanID                        int64            `json:"loan_id"`
-	TrxID                         int64            `json:"trx_id"`
-	BorrowerID                    int64            `json:"borrower_id"`
-	PrincipalAmount               float64          `json:"principal_amount"`
-	CollectedAmount               float64          `json:"collected_amount"`
-	InterestRate                  float64          `json:"interest_rate"`
-	Status                        int              `json:"status"`
-	StatusDesc                    string           `json:"status_desc"`
+	// LoanID is the sequential id of the loan inside the service, the api identifies the loan by PublicID
+	LoanID          int64   `json:"-"`
+	PublicID        string  `json:"loan_id"`
+	TrxID           int64   `json:"trx_id"`
+	BorrowerID      int64   `json:"borrower_id"`
+	PrincipalAmount float64 `json:"principal_amount"`
+	CollectedAmount float64 `json:"collected_amount"`
+	InterestRate    float64 `json:"interest_rate"`
+	Status          int     `json:"status"`
+	StatusDesc      string  `json:"status_desc"`
+	// OrganizerBorrowerAggrementURL is the view link of the agreement, only the borrower and employees may open it
 	OrganizerBorrowerAggrementURL string           `json:"organizer_borrower_aggrement_url,omitempty"`
 	ApprovalInfo                  *ApprovalInfo    `json:"approval_info,omitempty"`
 	Lending                       []Lending        `json:"lending,omitempty"`
 	DisbursementInfo              DisbursementInfo `json:"disbursement_info,omitempty"`
+	CreatedAt                     time.Time        `json:"created_at"`
+}
+
+// LoanFilter is filter for loan list, zero value fields are ignored
+type LoanFilter struct {
+	Status      int
+	BorrowerID  int64
+	LenderID    int64
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// IsVisitOutOfRange keeps the loans with an approval or disbursement visit flagged out of range
+	IsVisitOutOfRange bool
+}
+
+// LoanUpdate is the funding progress of a loan pushed to the clients watching it
+type LoanUpdate struct {
+	LoanID          string  `json:"loan_id"`
+	Status          int     `json:"status"`
+	StatusDesc      string  `json:"status_desc"`
+	PrincipalAmount float64 `json:"principal_amount"`
+	CollectedAmount float64 `json:"collected_amount"`
+	RemainingAmount float64 `json:"remaining_amount"`
+	LenderCount     int     `json:"lender_count"`
 }
 
 type ApprovalInfo struct {
-	PictureProof             string    `json:"picture_proof"` // base64 encoded string (image)
-	FieldValidatorEmployeeID int64     `json:"field_validator_employee_id"`
-	ApprovalDate             time.Time `json:"approval_date"`
+	// PictureProof is the base64 encoded picture of a json approval request, it is stored as Proof and never kept on the loan
+	PictureProof             string        `json:"picture_proof,omitempty"`
+	FieldValidatorEmployeeID int64         `json:"field_validator_employee_id"`
+	ApprovalDate             time.Time     `json:"approval_date"`
+	Proof                    *PictureProof `json:"proof,omitempty"`
+	// Visit is the field visit of the validator approving the loan
+	Visit *Visit `json:"visit,omitempty"`
+}
+
+// PictureProof is the picture of the field visit stored in the document store, stripped of its metadata
+type PictureProof struct {
+	DocumentKey  string `json:"document_key"`
+	ThumbnailKey string `json:"thumbnail_key"`
+	ContentType  string `json:"content_type"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	SizeBytes    int    `json:"size_bytes"`
 }
 
 type Lending struct {
-	LenderID                    int64   `json:"lender_id"`
-	InvestedAmount              float64 `json:"invested_amount"`
+	LenderID       int64   `json:"lender_id"`
+	InvestedAmount float64 `json:"invested_amount"`
+	// OrganizerLenderAggrementURL is the view link of the agreement, only the lender and employees may open it
 	OrganizerLenderAggrementURL string  `json:"organizer_lender_aggrement_url"`
 	ReturnAmount                float64 `json:"return_amount"`
 }
 
 type DisbursementInfo struct {
+	// AgreementSignedURLs are the view links of the signed agreements, only their signers and employees may open them
 	AgreementSignedURLs []string  `json:"agreement_signed_urls"`
 	FieldOfficerID      int64     `json:"field_officer_id"`
 	DisbursementDate    time.Time `json:"disbursement_date"`
+	// Visit is the field visit of the officer disbursing the loan
+	Visit *Visit `json:"visit,omitempty"`
 }
 
 func (l *Loan) GetRemainingRequiredAmount() float64 {
@@ -54,6 +98,18 @@ func (l *Loan) CalculateReturnAmount() float64 {
 	return l.PrincipalAmount + (l.PrincipalAmount * l.InterestRate)
 }
 
+func (l *Loan) GetUpdate() LoanUpdate {
+	return LoanUpdate{
+		LoanID:          l.PublicID,
+		Status:          l.Status,
+		StatusDesc:      l.StatusDesc,
+		PrincipalAmount: l.PrincipalAmount,
+		CollectedAmount: l.CollectedAmount,
+		RemainingAmount: l.GetRemainingRequiredAmount(),
+		LenderCount:     len(l.Lending),
+	}
+}
+
 func (l *Loan) IsLenderInvested(lenderID int64) bool {
 	for _, v := range l.Lending {
 		if v.LenderID == lenderID {
@@ -63,3 +119,35 @@ func (l *Loan) IsLenderInvested(lenderID int64) bool {
 
 	return false
 }
+
+// IsVisitOutOfRange reports whether the approval or disbursement visit was flagged out of range
+func (l *Loan) IsVisitOutOfRange() bool {
+	if l.ApprovalInfo != nil && l.ApprovalInfo.Visit != nil && l.ApprovalInfo.Visit.IsOutOfRange {
+		return true
+	}
+
+	return l.DisbursementInfo.Visit != nil && l.DisbursementInfo.Visit.IsOutOfRange
+}
+
+func (f LoanFilter) Match(loan Loan) bool {
+	if f.Status != 0 && loan.Status != f.Status {
+		return false
+	}
+	if f.BorrowerID != 0 && loan.BorrowerID != f.BorrowerID {
+		return false
+	}
+	if f.LenderID != 0 && !loan.IsLenderInvested(f.LenderID) {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && loan.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && loan.CreatedAt.After(f.CreatedTo) {
+		return false
+	}
+	if f.IsVisitOutOfRange && !loan.IsVisitOutOfRange() {
+		return false
+	}
+
+	return true
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -139,6 +140,28 @@ func TestCalculateReturnAmount(t *testing.T) {
 	}
 }
 
+func TestGetUpdate(t *testing.T) {
+	loan := Loan{
+		LoanID:          7,
+		PublicID:        "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+		Status:          2,
+		StatusDesc:      "approved",
+		PrincipalAmount: 1000.0,
+		CollectedAmount: 600.0,
+		Lending:         []Lending{{LenderID: 2, InvestedAmount: 400.0}, {LenderID: 3, InvestedAmount: 200.0}},
+	}
+
+	assert.Equal(t, LoanUpdate{
+		LoanID:          "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+		Status:          2,
+		StatusDesc:      "approved",
+		PrincipalAmount: 1000.0,
+		CollectedAmount: 600.0,
+		RemainingAmount: 400.0,
+		LenderCount:     2,
+	}, loan.GetUpdate())
+}
+
 func TestIsLenderInvested(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -176,3 +199,56 @@ func TestIsLenderInvested(t *testing.T) {
 		})
 	}
 }
+
+func TestLoanFilterMatch(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 10, 8, 0, 0, 0, time.UTC)
+	loan := Loan{
+		LoanID:     1,
+		BorrowerID: 1,
+		Status:     2,
+		Lending:    []Lending{{LenderID: 2}},
+		CreatedAt:  createdAt,
+	}
+
+	tests := []struct {
+		name          string
+		filter        LoanFilter
+		expectedValue bool
+	}{
+		{name: "empty filter", filter: LoanFilter{}, expectedValue: true},
+		{name: "status match", filter: LoanFilter{Status: 2}, expectedValue: true},
+		{name: "status mismatch", filter: LoanFilter{Status: 1}, expectedValue: false},
+		{name: "borrower mismatch", filter: LoanFilter{BorrowerID: 9}, expectedValue: false},
+		{name: "lender match", filter: LoanFilter{LenderID: 2}, expectedValue: true},
+		{name: "lender mismatch", filter: LoanFilter{LenderID: 3}, expectedValue: false},
+		{name: "created within range", filter: LoanFilter{CreatedFrom: createdAt.Add(-time.Hour), CreatedTo: createdAt.Add(time.Hour)}, expectedValue: true},
+		{name: "created before range", filter: LoanFilter{CreatedFrom: createdAt.Add(time.Hour)}, expectedValue: false},
+		{name: "created after range", filter: LoanFilter{CreatedTo: createdAt.Add(-time.Hour)}, expectedValue: false},
+		{name: "visit out of range mismatch", filter: LoanFilter{IsVisitOutOfRange: true}, expectedValue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedValue, tt.filter.Match(loan))
+		})
+	}
+}
+
+func TestIsVisitOutOfRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		loan          Loan
+		expectedValue bool
+	}{
+		{name: "no visit", loan: Loan{ApprovalInfo: &ApprovalInfo{}}, expectedValue: false},
+		{name: "approval visit in range", loan: Loan{ApprovalInfo: &ApprovalInfo{Visit: &Visit{}}}, expectedValue: false},
+		{name: "approval visit out of range", loan: Loan{ApprovalInfo: &ApprovalInfo{Visit: &Visit{IsOutOfRange: true}}}, expectedValue: true},
+		{name: "disbursement visit out of range", loan: Loan{DisbursementInfo: DisbursementInfo{Visit: &Visit{IsOutOfRange: true}}}, expectedValue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedValue, tt.loan.IsVisitOutOfRange())
+		})
+	}
+}
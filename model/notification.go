@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Notification is a message in the in-app inbox of the user
+type Notification struct {
+	NotificationID int64     `json:"notification_id"`
+	UserID         int64     `json:"user_id"`
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Subject        string    `json:"subject"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
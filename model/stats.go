@@ -0,0 +1,11 @@
+package model
+
+// Stats is a snapshot of the loans and agreements, exposed as business metrics
+type Stats struct {
+	// LoanCountByStatus is the count of loans per loan status
+	LoanCountByStatus map[int]int `json:"loan_count_by_status"`
+	// PrincipalFunded is the principal amount invested by lenders over every loan
+	PrincipalFunded float64 `json:"principal_funded"`
+	// AgreementsPendingSignature is the count of generated agreements not signed yet
+	AgreementsPendingSignature int `json:"agreements_pending_signature"`
+}
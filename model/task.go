@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Task is a field visit waiting for an employee, the validation visit of a proposed loan or the disbursement
+// visit of a signed loan
+type Task struct {
+	TaskID int64 `json:"task_id"`
+	// LoanID is the sequential id of the loan inside the service, the api identifies the loan by LoanPublicID
+	LoanID       int64  `json:"-"`
+	LoanPublicID string `json:"loan_id"`
+	TaskType     int    `json:"task_type"`
+	TaskTypeDesc string `json:"task_type_desc"`
+	Status       int    `json:"status"`
+	StatusDesc   string `json:"status_desc"`
+	// AssigneeID is the employee the task is assigned to, zero while no employee can take it
+	AssigneeID int64 `json:"assignee_id"`
+	// Region is the region of the borrower the visit takes place in
+	Region string `json:"region"`
+	// CompletedBy is the employee who approved or disbursed the loan, not always the assignee
+	CompletedBy int64     `json:"completed_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// CompletedAt is nil while the task is open
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TaskFilter is filter for task list, zero value fields are ignored
+type TaskFilter struct {
+	LoanID int64
+	// LoanPublicID is the loan_id filter of the api
+	LoanPublicID string
+	AssigneeID   int64
+	TaskType     int
+	Status       int
+}
+
+func (f TaskFilter) Match(task Task) bool {
+	if f.LoanID != 0 && task.LoanID != f.LoanID {
+		return false
+	}
+	if f.LoanPublicID != "" && task.LoanPublicID != f.LoanPublicID {
+		return false
+	}
+	if f.AssigneeID != 0 && task.AssigneeID != f.AssigneeID {
+		return false
+	}
+	if f.TaskType != 0 && task.TaskType != f.TaskType {
+		return false
+	}
+	if f.Status != 0 && task.Status != f.Status {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskFilterMatch(t *testing.T) {
+	task := Task{TaskID: 1, LoanID: 1, TaskType: 1, Status: 1, AssigneeID: 4}
+
+	tests := []struct {
+		name          string
+		filter        TaskFilter
+		expectedValue bool
+	}{
+		{name: "empty filter", filter: TaskFilter{}, expectedValue: true},
+		{name: "every field match", filter: TaskFilter{LoanID: 1, AssigneeID: 4, TaskType: 1, Status: 1}, expectedValue: true},
+		{name: "loan mismatch", filter: TaskFilter{LoanID: 2}, expectedValue: false},
+		{name: "assignee mismatch", filter: TaskFilter{AssigneeID: 5}, expectedValue: false},
+		{name: "type mismatch", filter: TaskFilter{TaskType: 2}, expectedValue: false},
+		{name: "status mismatch", filter: TaskFilter{Status: 2}, expectedValue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedValue, tt.filter.Match(task))
+		})
+	}
+}
@@ -4,4 +4,30 @@ type User struct {
 	UserID   int64  `json:"user_id"`
 	UserName string `json:"user_name"`
 	UserType int    `json:"user_type"`
+	Locale   string `json:"locale"`
+	// Email and PhoneNumber are the notification addresses, the channel is skipped when empty
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	// Address and Location are the registered home of a borrower, the field visits are measured from Location
+	Address  string    `json:"address,omitempty"`
+	Location *GeoPoint `json:"location,omitempty"`
+	// Region is where a borrower lives or an employee works, the field visits are assigned within the region
+	Region string `json:"region,omitempty"`
+}
+
+// UserFilter is filter for user list, zero value fields are ignored
+type UserFilter struct {
+	UserType int
+	Region   string
+}
+
+func (f UserFilter) Match(user User) bool {
+	if f.UserType != 0 && user.UserType != f.UserType {
+		return false
+	}
+	if f.Region != "" && user.Region != f.Region {
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// earthRadiusMeters is the mean radius of the earth
+const earthRadiusMeters = 6371008.8
+
+// GeoPoint is a gps position in decimal degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Visit is the field visit of an employee, located by the gps of their device
+type Visit struct {
+	Location  GeoPoint  `json:"location"`
+	VisitedAt time.Time `json:"visited_at"`
+	// DistanceMeters is the distance from the borrower address, absent when the borrower has no registered location
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+	// IsOutOfRange flags a visit accepted while farther from the borrower address than allowed, or missing so its
+	// distance is unknown
+	IsOutOfRange bool `json:"is_out_of_range"`
+	// IsMissing flags an approval or disbursement accepted without a visit, its location and time are left empty
+	IsMissing bool `json:"is_missing"`
+}
+
+// Validate checks the point is on the earth. The zero point is rejected too, it is what a device
+// without a gps fix reports
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Latitude) || p.Latitude < -90 || p.Latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	if p.Latitude == 0 && p.Longitude == 0 {
+		return errors.New("location is the zero point, the device has no gps fix")
+	}
+
+	return nil
+}
+
+// DistanceMeters returns the great-circle distance to the other point by the haversine formula
+func (p GeoPoint) DistanceMeters(other GeoPoint) float64 {
+	lat1 := p.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLon := (other.Longitude - p.Longitude) * math.Pi / 180
+
+	a := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(deltaLon/2), 2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(math.Min(1, a)))
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoPointValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   GeoPoint
+		isError bool
+	}{
+		{name: "valid", point: GeoPoint{Latitude: -6.2088, Longitude: 106.8456}},
+		{name: "valid - on the equator", point: GeoPoint{Latitude: 0, Longitude: 106.8456}},
+		{name: "invalid - latitude", point: GeoPoint{Latitude: -91, Longitude: 106.8456}, isError: true},
+		{name: "invalid - longitude", point: GeoPoint{Latitude: -6.2088, Longitude: 181}, isError: true},
+		{name: "invalid - nan", point: GeoPoint{Latitude: math.NaN(), Longitude: 106.8456}, isError: true},
+		{name: "invalid - zero point", point: GeoPoint{}, isError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.isError, tt.point.Validate() != nil)
+		})
+	}
+}
+
+func TestGeoPointDistanceMeters(t *testing.T) {
+	tests := []struct {
+		name             string
+		from             GeoPoint
+		to               GeoPoint
+		expectedDistance float64
+		delta            float64
+	}{
+		{
+			name:             "same point",
+			from:             GeoPoint{Latitude: -6.2088, Longitude: 106.8456},
+			to:               GeoPoint{Latitude: -6.2088, Longitude: 106.8456},
+			expectedDistance: 0,
+			delta:            0.001,
+		},
+		{
+			name:             "one thousandth of a degree of latitude",
+			from:             GeoPoint{Latitude: -6.2088, Longitude: 106.8456},
+			to:               GeoPoint{Latitude: -6.2078, Longitude: 106.8456},
+			expectedDistance: 111.2,
+			delta:            0.1,
+		},
+		{
+			name:             "jakarta to bandung",
+			from:             GeoPoint{Latitude: -6.2088, Longitude: 106.8456},
+			to:               GeoPoint{Latitude: -6.9175, Longitude: 107.6191},
+			expectedDistance: 116000,
+			delta:            1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expectedDistance, tt.from.DistanceMeters(tt.to), tt.delta)
+			assert.InDelta(t, tt.expectedDistance, tt.to.DistanceMeters(tt.from), tt.delta)
+		})
+	}
+}
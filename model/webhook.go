@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookSubscription struct {
+	SubscriptionID int64  `json:"subscription_id"`
+	URL            string `json:"url"`
+	// EventTypes are the event types delivered to the url, empty subscribes to every event type
+	EventTypes []string `json:"event_types"`
+	// Secret signs the delivered payloads, only returned when subscribing
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is a webhook delivery that failed every attempt, kept in the dead-letter list until retried
+type WebhookDelivery struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+func (s WebhookSubscription) IsSubscribed(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+
+	for _, v := range s.EventTypes {
+		if v == eventType {
+			return true
+		}
+	}
+
+	return false
+}
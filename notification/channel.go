@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"amartha-test/helper"
+	"amartha-test/model"
+)
+
+// ErrNoAddress is returned by a channel when the user has no address on it, the channel is skipped
+var ErrNoAddress = errors.New("user has no address on this channel")
+
+// Message is a notification rendered for one user
+type Message struct {
+	EventID   string
+	EventType string
+	Subject   string
+	Body      string
+	// URL is the link the message points to, e.g. the agreement to sign, empty when there is none
+	URL string
+}
+
+type IChannel interface {
+	// Name is the channel name used in logs
+	Name() string
+	Send(ctx context.Context, user model.User, message Message) error
+}
+
+// EmailChannel sends the message as a plain text email through an smtp server
+type EmailChannel struct {
+	Addr string
+	From string
+	// Auth authenticates to the smtp server, nil sends without authentication
+	Auth smtp.Auth
+}
+
+// NewEmailChannel returns the email channel of the smtp server, authenticating with PLAIN when the username is set
+func NewEmailChannel(addr, from, username, password string) *EmailChannel {
+	channel := &EmailChannel{
+		Addr: addr,
+		From: from,
+	}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		channel.Auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return channel
+}
+
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+func (c *EmailChannel) Send(ctx context.Context, user model.User, message Message) error {
+	if user.Email == "" {
+		return ErrNoAddress
+	}
+
+	var sb strings.Builder
+	sb.WriteString("From: " + c.From + "\r\n")
+	sb.WriteString("To: " + user.Email + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	sb.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n") + "\r\n")
+
+	return smtp.SendMail(c.Addr, c.Auth, c.From, []string{user.Email}, []byte(sb.String()))
+}
+
+// SMSChannel posts the message subject and link to an sms gateway as {"to": phone number, "message": text}
+type SMSChannel struct {
+	GatewayURL string
+	Client     *http.Client
+}
+
+func NewSMSChannel(gatewayURL string) *SMSChannel {
+	return &SMSChannel{
+		GatewayURL: gatewayURL,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *SMSChannel) Name() string {
+	return "sms"
+}
+
+func (c *SMSChannel) Send(ctx context.Context, user model.User, message Message) error {
+	if user.PhoneNumber == "" {
+		return ErrNoAddress
+	}
+
+	text := message.Subject
+	if message.URL != "" {
+		text += " " + message.URL
+	}
+	payload, err := json.Marshal(map[string]string{"to": user.PhoneNumber, "message": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.GatewayURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// InboxChannel stores the message in the in-app inbox of the user
+type InboxChannel struct {
+	Helper helper.IHelper
+}
+
+func NewInboxChannel(helper helper.IHelper) *InboxChannel {
+	return &InboxChannel{
+		Helper: helper,
+	}
+}
+
+func (c *InboxChannel) Name() string {
+	return "inbox"
+}
+
+func (c *InboxChannel) Send(ctx context.Context, user model.User, message Message) error {
+	c.Helper.UpsertNotification(ctx, model.Notification{
+		NotificationID: c.Helper.GenerateIncrementalNotificationID(ctx),
+		UserID:         user.UserID,
+		EventID:        message.EventID,
+		EventType:      message.EventType,
+		Subject:        message.Subject,
+		Body:           message.Body,
+		CreatedAt:      time.Now(),
+	})
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+// fakeMail is a mail received by the fake smtp server
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer is a minimal smtp server keeping every received mail in memory
+type fakeSMTPServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	mails    []fakeMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTPServer) Mails() []fakeMail {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]fakeMail(nil), s.mails...)
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	var mail fakeMail
+	reply("220 localhost fake smtp")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mail = fakeMail{from: strings.Trim(line[len("MAIL FROM:"):], "<>")}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			mail.data = data.String()
+			s.mutex.Lock()
+			s.mails = append(s.mails, mail)
+			s.mutex.Unlock()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailChannel(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	channel := NewEmailChannel(server.Addr(), "noreply@loan.example.com", "", "")
+	message := Message{Subject: "Perjanjian pinjaman 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 siap ditandatangani", Body: "Halo Septian,\n\nSilakan tandatangani."}
+
+	err := channel.Send(context.Background(), model.User{UserID: 1, Email: "septian@example.com"}, message)
+	assert.NoError(t, err)
+
+	mails := server.Mails()
+	if assert.Len(t, mails, 1) {
+		assert.Equal(t, "noreply@loan.example.com", mails[0].from)
+		assert.Equal(t, []string{"septian@example.com"}, mails[0].to)
+		assert.Contains(t, mails[0].data, "To: septian@example.com\r\n")
+		assert.Contains(t, mails[0].data, "Subject: Perjanjian pinjaman 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 siap ditandatangani\r\n")
+		assert.Contains(t, mails[0].data, "Content-Type: text/plain; charset=UTF-8\r\n")
+		assert.Contains(t, mails[0].data, "\r\n\r\nHalo Septian,\r\n\r\nSilakan tandatangani.\r\n")
+	}
+
+	err = channel.Send(context.Background(), model.User{UserID: 4}, message)
+	assert.ErrorIs(t, err, ErrNoAddress)
+	assert.Len(t, server.Mails(), 1)
+}
+
+func TestSMSChannel(t *testing.T) {
+	var received []map[string]string
+	status := http.StatusAccepted
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body)
+		w.WriteHeader(status)
+	}))
+	defer gateway.Close()
+	channel := NewSMSChannel(gateway.URL)
+	message := Message{Subject: "Agreement of loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 ready to sign", Body: "Hi Pratama", URL: "http://localhost:8080/v1/agreement/8/view"}
+
+	err := channel.Send(context.Background(), model.User{UserID: 2, PhoneNumber: "+6281200000002"}, message)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"to": "+6281200000002", "message": "Agreement of loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 ready to sign http://localhost:8080/v1/agreement/8/view"}}, received)
+
+	status = http.StatusBadGateway
+	err = channel.Send(context.Background(), model.User{UserID: 2, PhoneNumber: "+6281200000002"}, message)
+	assert.Error(t, err)
+
+	err = channel.Send(context.Background(), model.User{UserID: 3}, message)
+	assert.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestInboxChannel(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	channel := NewInboxChannel(mockHelper)
+
+	mockHelper.On("GenerateIncrementalNotificationID", mock.Anything).Return(int64(1)).Once()
+	mockHelper.On("UpsertNotification", mock.Anything, mock.MatchedBy(func(notification model.Notification) bool {
+		return notification.NotificationID == 1 && notification.UserID == 3 && notification.EventID == "evt_0a1b" &&
+			notification.Subject == "Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved" && !notification.CreatedAt.IsZero()
+	})).Return().Once()
+
+	err := channel.Send(context.Background(), model.User{UserID: 3}, Message{EventID: "evt_0a1b", EventType: "loan.approved", Subject: "Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved"})
+
+	assert.NoError(t, err)
+	mockHelper.AssertExpectations(t)
+}
@@ -0,0 +1,175 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"amartha-test/audit"
+	"amartha-test/event"
+	"amartha-test/helper"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+const (
+	DefaultQueueSize = 1024
+
+	drainPollInterval = 10 * time.Millisecond
+)
+
+// Notifier tells the borrower and lenders of a loan about its state changes on every channel they have an address on
+type Notifier struct {
+	Helper helper.IHelper
+	// Inbox is sent right away by Handle, it is an in-memory write so it is never dropped, nil skips it
+	Inbox IChannel
+	// Channels are sent by Run from the queue, they are dropped when the queue is full
+	Channels []IChannel
+
+	queue chan event.Event
+	// pending counts the queued and in-flight events
+	pending atomic.Int64
+}
+
+// recipient is a user to notify of an event, with the lending and link of that user
+type recipient struct {
+	userID  int64
+	lending model.Lending
+	url     string
+}
+
+func NewNotifier(helper helper.IHelper, inbox IChannel, channels ...IChannel) *Notifier {
+	return &Notifier{
+		Helper:   helper,
+		Inbox:    inbox,
+		Channels: channels,
+		queue:    make(chan event.Event, DefaultQueueSize),
+	}
+}
+
+// Handle is the event bus handler storing the inbox notification and queueing the event for the other channels,
+// sending is left to Run so slow channels never hold a request
+func (n *Notifier) Handle(ctx context.Context, e event.Event) {
+	if len(recipientsOf(e)) == 0 {
+		return
+	}
+
+	// 1. store inbox notification, written by the system like the queued ones
+	if n.Inbox != nil {
+		n.send(audit.WithActor(ctx, audit.SystemActor), e, []IChannel{n.Inbox})
+	}
+
+	// 2. queue event for the other channels, only they are shed under load
+	if len(n.Channels) == 0 {
+		return
+	}
+	n.pending.Add(1)
+	select {
+	case n.queue <- e:
+	default:
+		n.pending.Add(-1)
+		logging.FromContext(ctx).Warn("notification queue is full, event dropped", "op", "Notifier", "event_id", e.ID)
+	}
+}
+
+// Run sends the notifications of the queued events until ctx is done, the events left in the queue are
+// logged and dropped, their inbox notifications are already stored
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			n.dropQueued(ctx)
+			return
+		case e := <-n.queue:
+			n.Notify(ctx, e)
+			n.pending.Add(-1)
+		}
+	}
+}
+
+// Drain waits until every queued event is sent, or ctx is done. Run keeps sending meanwhile, it is stopped after Drain
+func (n *Notifier) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for n.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) dropQueued(ctx context.Context) {
+	for {
+		select {
+		case e := <-n.queue:
+			logging.FromContext(ctx).Warn("notification is left on shutdown, event dropped", "op", "Notifier", "event_id", e.ID)
+			n.pending.Add(-1)
+		default:
+			return
+		}
+	}
+}
+
+// Notify renders and sends the message of the event to each of its recipients on the queued channels,
+// a failing channel does not stop the others
+func (n *Notifier) Notify(ctx context.Context, e event.Event) {
+	n.send(ctx, e, n.Channels)
+}
+
+func (n *Notifier) send(ctx context.Context, e event.Event, channels []IChannel) {
+	for _, recipient := range recipientsOf(e) {
+		user := n.Helper.GetUserByUserID(ctx, recipient.userID)
+		if user.UserID == 0 {
+			logging.FromContext(ctx).Info("user data is not found", "op", "Notifier", "event_id", e.ID, "user_id", recipient.userID)
+			continue
+		}
+
+		message, err := Render(TemplateData{
+			User:    user,
+			Event:   e,
+			Lending: recipient.lending,
+			URL:     recipient.url,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("failed render message", "op", "Notifier", "event_id", e.ID, "user_id", user.UserID, "error", err)
+			continue
+		}
+
+		for _, channel := range channels {
+			err = channel.Send(ctx, user, message)
+			if errors.Is(err, ErrNoAddress) {
+				continue
+			}
+			if err != nil {
+				logging.FromContext(ctx).Error("failed send notification", "op", "Notifier", "event_id", e.ID, "user_id", user.UserID, "channel", channel.Name(), "error", err)
+			}
+		}
+	}
+}
+
+// recipientsOf returns the users told about the event
+func recipientsOf(e event.Event) []recipient {
+	loan := e.Loan
+	switch e.Type {
+	case event.LoanApproved, event.LoanInvested:
+		return []recipient{{userID: loan.BorrowerID}}
+	case event.AgreementReady:
+		if e.Lending != nil {
+			return []recipient{{userID: e.Lending.LenderID, lending: *e.Lending, url: e.Lending.OrganizerLenderAggrementURL}}
+		}
+		return []recipient{{userID: loan.BorrowerID, url: loan.OrganizerBorrowerAggrementURL}}
+	case event.LoanDisbursed:
+		recipients := []recipient{{userID: loan.BorrowerID}}
+		for _, lending := range loan.Lending {
+			recipients = append(recipients, recipient{userID: lending.LenderID, lending: lending})
+		}
+		return recipients
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+// fakeChannel records the subject sent to each user and the last message, failing with err when set
+type fakeChannel struct {
+	err   error
+	mutex sync.Mutex
+	sent  map[int64][]string
+	last  Message
+}
+
+func (c *fakeChannel) Name() string {
+	return "fake"
+}
+
+func (c *fakeChannel) Send(ctx context.Context, user model.User, message Message) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.sent == nil {
+		c.sent = make(map[int64][]string)
+	}
+	c.sent[user.UserID] = append(c.sent[user.UserID], message.Subject)
+	c.last = message
+	return c.err
+}
+
+func (c *fakeChannel) Sent() map[int64][]string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.sent
+}
+
+func TestNotify(t *testing.T) {
+	borrower := model.User{UserID: 1, UserName: "Septian", UserType: constant.UserTypeBorrower, Locale: constant.LocaleEnglish}
+	lender1 := model.User{UserID: 2, UserName: "Pratama", UserType: constant.UserTypeLender, Locale: constant.LocaleEnglish}
+	lender2 := model.User{UserID: 3, UserName: "Rusmana", UserType: constant.UserTypeLender, Locale: constant.LocaleIndonesian}
+	loan := model.Loan{
+		LoanID:                        7,
+		PublicID:                      "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+		BorrowerID:                    borrower.UserID,
+		PrincipalAmount:               1000,
+		OrganizerBorrowerAggrementURL: "http://localhost:8080/v1/agreement/9/view",
+		Lending: []model.Lending{
+			{LenderID: lender1.UserID, InvestedAmount: 600, OrganizerLenderAggrementURL: "http://localhost:8080/v1/agreement/5/view"},
+			{LenderID: lender2.UserID, InvestedAmount: 400, OrganizerLenderAggrementURL: "http://localhost:8080/v1/agreement/6/view"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		event        event.Event
+		expectedSent map[int64][]string
+		mocks        func(mockHelper *mocks.IHelper)
+	}{
+		{
+			name:         "loan submitted - nobody",
+			event:        event.New(event.LoanSubmitted, borrower.UserID, loan),
+			expectedSent: nil,
+			mocks:        func(mockHelper *mocks.IHelper) {},
+		},
+		{
+			name:         "loan approved - borrower",
+			event:        event.New(event.LoanApproved, 4, loan),
+			expectedSent: map[int64][]string{1: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved"}},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, borrower.UserID).Return(borrower)
+			},
+		},
+		{
+			name:         "lender agreement ready - its lender",
+			event:        event.New(event.AgreementReady, lender1.UserID, loan).WithLending(loan.Lending[1]),
+			expectedSent: map[int64][]string{3: {"Perjanjian pinjaman 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 siap ditandatangani"}},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, lender2.UserID).Return(lender2)
+			},
+		},
+		{
+			name:         "borrower agreement ready - borrower",
+			event:        event.New(event.AgreementReady, lender2.UserID, loan),
+			expectedSent: map[int64][]string{1: {"Agreement of loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 ready to sign"}},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, borrower.UserID).Return(borrower)
+			},
+		},
+		{
+			name:         "loan disbursed - borrower and every lender, skipping unknown user",
+			event:        event.New(event.LoanDisbursed, 5, loan),
+			expectedSent: map[int64][]string{1: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 disbursed"}, 2: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 disbursed"}},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetUserByUserID", mock.Anything, borrower.UserID).Return(borrower)
+				mockHelper.On("GetUserByUserID", mock.Anything, lender1.UserID).Return(lender1)
+				mockHelper.On("GetUserByUserID", mock.Anything, lender2.UserID).Return(model.User{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			failing := &fakeChannel{err: errors.New("smtp unavailable")}
+			noAddress := &fakeChannel{err: ErrNoAddress}
+			channel := &fakeChannel{}
+			notifier := NewNotifier(mockHelper, nil, failing, noAddress, channel)
+
+			// main func
+			notifier.Notify(context.Background(), tt.event)
+
+			assert.Equal(t, tt.expectedSent, channel.Sent())
+			assert.Equal(t, tt.expectedSent, failing.Sent(), "a failing channel is tried for every recipient")
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotifierRun(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Locale: constant.LocaleEnglish})
+	inbox := &fakeChannel{}
+	channel := &fakeChannel{}
+	notifier := NewNotifier(mockHelper, inbox, channel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	notifier.Handle(ctx, event.New(event.LoanSubmitted, 1, model.Loan{LoanID: 7, PublicID: "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", BorrowerID: 1}))
+	notifier.Handle(ctx, event.New(event.LoanApproved, 4, model.Loan{LoanID: 7, PublicID: "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", BorrowerID: 1}))
+
+	assert.Eventually(t, func() bool { return len(channel.Sent()[1]) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, map[int64][]string{1: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved"}}, channel.Sent())
+	assert.Equal(t, map[int64][]string{1: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved"}}, inbox.Sent())
+}
+
+func TestNotifierAgreementLink(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Locale: constant.LocaleEnglish})
+	inbox := &fakeChannel{}
+	channel := &fakeChannel{}
+	notifier := NewNotifier(mockHelper, inbox, channel)
+	url := "http://localhost:8080/v1/agreement/0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c/view"
+
+	// main func
+	notifier.Handle(context.Background(), event.New(event.AgreementReady, 2, model.Loan{LoanID: 7, PublicID: "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", BorrowerID: 1, OrganizerBorrowerAggrementURL: url}))
+	notifier.Notify(context.Background(), <-notifier.queue)
+
+	assert.Equal(t, url, inbox.last.URL, "the link is opened by the signer only, so the inbox keeps it")
+	assert.Contains(t, inbox.last.Body, url)
+	assert.Equal(t, url, channel.last.URL)
+	assert.Contains(t, channel.last.Body, url)
+}
+
+func TestNotifierHandleFullQueue(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Locale: constant.LocaleEnglish})
+	inbox := &fakeChannel{}
+	channel := &fakeChannel{}
+	notifier := NewNotifier(mockHelper, inbox, channel)
+	notifier.queue = make(chan event.Event)
+
+	// main func
+	notifier.Handle(context.Background(), event.New(event.LoanApproved, 4, model.Loan{LoanID: 7, PublicID: "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24", BorrowerID: 1}))
+
+	assert.Equal(t, map[int64][]string{1: {"Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 approved"}}, inbox.Sent(), "the inbox is written without the queue")
+	assert.Empty(t, notifier.queue)
+	assert.Nil(t, channel.Sent())
+}
+
+func TestNotifierDrain(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Locale: constant.LocaleEnglish})
+	channel := &fakeChannel{}
+	notifier := NewNotifier(mockHelper, nil, channel)
+	for i := int64(1); i <= 3; i++ {
+		notifier.Handle(context.Background(), event.New(event.LoanApproved, 4, model.Loan{LoanID: i, BorrowerID: 1}))
+	}
+
+	// nothing is sent without the worker
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, notifier.Drain(ctx), context.DeadlineExceeded)
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go notifier.Run(workerCtx)
+
+	// main func
+	err := notifier.Drain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, channel.Sent()[1], 3)
+}
+
+func TestNotifierStopDropsQueued(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Locale: constant.LocaleEnglish}).Maybe()
+	notifier := NewNotifier(mockHelper, nil, &fakeChannel{})
+	for i := int64(1); i <= 3; i++ {
+		notifier.Handle(context.Background(), event.New(event.LoanApproved, 4, model.Loan{LoanID: i, BorrowerID: 1}))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// main func
+	notifier.Run(ctx)
+
+	assert.Empty(t, notifier.queue)
+	assert.NoError(t, notifier.Drain(context.Background()))
+}
@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"amartha-test/constant"
+	"amartha-test/document"
+	"amartha-test/event"
+	"amartha-test/model"
+)
+
+// TemplateData is the data passed into every message template
+type TemplateData struct {
+	User  model.User
+	Event event.Event
+	// Lending is the lending of the user when the user is a lender of the loan
+	Lending model.Lending
+	URL     string
+}
+
+func (d TemplateData) Loan() model.Loan {
+	return d.Event.Loan
+}
+
+type messageTemplate struct {
+	subject string
+	body    string
+}
+
+// templates is the catalogue of every message, keyed by event type then locale
+var templates = map[event.Type]map[string]messageTemplate{
+	event.LoanApproved: {
+		constant.LocaleIndonesian: {
+			subject: "Pinjaman {{.Loan.PublicID}} disetujui",
+			body:    "Halo {{.User.UserName}},\n\nPinjaman Anda sebesar {{rupiah .Loan.PrincipalAmount}} telah disetujui dan kini terbuka untuk pendanaan.",
+		},
+		constant.LocaleEnglish: {
+			subject: "Loan {{.Loan.PublicID}} approved",
+			body:    "Hi {{.User.UserName}},\n\nYour loan of {{rupiah .Loan.PrincipalAmount}} has been approved and is now open for investment.",
+		},
+	},
+	event.LoanInvested: {
+		constant.LocaleIndonesian: {
+			subject: "Pinjaman {{.Loan.PublicID}} telah terdanai penuh",
+			body:    "Halo {{.User.UserName}},\n\nPinjaman Anda sebesar {{rupiah .Loan.PrincipalAmount}} telah terdanai penuh oleh {{len .Loan.Lending}} pemberi pinjaman. Perjanjian Anda dikirim setelah semua pemberi pinjaman menandatangani perjanjiannya.",
+		},
+		constant.LocaleEnglish: {
+			subject: "Loan {{.Loan.PublicID}} fully funded",
+			body:    "Hi {{.User.UserName}},\n\nYour loan of {{rupiah .Loan.PrincipalAmount}} has been fully funded by {{len .Loan.Lending}} lender(s). Your agreement is sent once every lender has signed theirs.",
+		},
+	},
+	event.AgreementReady: {
+		constant.LocaleIndonesian: {
+			subject: "Perjanjian pinjaman {{.Loan.PublicID}} siap ditandatangani",
+			body:    "Halo {{.User.UserName}},\n\nPerjanjian Anda untuk pinjaman {{.Loan.PublicID}} sudah siap. Silakan baca dan tandatangani perjanjian berikut:\n{{.URL}}",
+		},
+		constant.LocaleEnglish: {
+			subject: "Agreement of loan {{.Loan.PublicID}} ready to sign",
+			body:    "Hi {{.User.UserName}},\n\nYour agreement for loan {{.Loan.PublicID}} is ready. Please read and sign it at:\n{{.URL}}",
+		},
+	},
+	event.LoanDisbursed: {
+		constant.LocaleIndonesian: {
+			subject: "Pinjaman {{.Loan.PublicID}} telah dicairkan",
+			body: "Halo {{.User.UserName}},\n\n{{if .Lending.LenderID}}Pinjaman {{.Loan.PublicID}} yang Anda danai sebesar {{rupiah .Lending.InvestedAmount}} telah dicairkan pada {{date .Loan.DisbursementInfo.DisbursementDate}}. " +
+				"Jumlah pengembalian Anda adalah {{rupiah .Lending.ReturnAmount}}.{{else}}Pinjaman Anda sebesar {{rupiah .Loan.PrincipalAmount}} telah dicairkan pada {{date .Loan.DisbursementInfo.DisbursementDate}}.{{end}}",
+		},
+		constant.LocaleEnglish: {
+			subject: "Loan {{.Loan.PublicID}} disbursed",
+			body: "Hi {{.User.UserName}},\n\n{{if .Lending.LenderID}}Loan {{.Loan.PublicID}} you funded with {{rupiah .Lending.InvestedAmount}} was disbursed on {{date .Loan.DisbursementInfo.DisbursementDate}}. " +
+				"Your return amount is {{rupiah .Lending.ReturnAmount}}.{{else}}Your loan of {{rupiah .Loan.PrincipalAmount}} was disbursed on {{date .Loan.DisbursementInfo.DisbursementDate}}.{{end}}",
+		},
+	},
+}
+
+// EventTypes returns every event type with a message template
+func EventTypes() []event.Type {
+	var eventTypes []event.Type
+	for _, v := range event.Types() {
+		if _, ok := templates[v]; ok {
+			eventTypes = append(eventTypes, v)
+		}
+	}
+
+	return eventTypes
+}
+
+// Render renders the message of the event in the locale of the user
+func Render(data TemplateData) (Message, error) {
+	catalogue, ok := templates[data.Event.Type]
+	if !ok {
+		return Message{}, fmt.Errorf("no message template of event type %s", data.Event.Type)
+	}
+	locale := document.NormalizeLocale(data.User.Locale)
+	messageTemplate := catalogue[locale]
+
+	funcs := template.FuncMap{
+		"rupiah": func(amount float64) string {
+			return document.FormatRupiah(locale, amount)
+		},
+		"date": func(date time.Time) string {
+			return document.FormatDate(locale, date)
+		},
+	}
+	execute := func(name, text string) (string, error) {
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", err
+		}
+
+		var sb strings.Builder
+		err = tmpl.Execute(&sb, data)
+		if err != nil {
+			return "", err
+		}
+
+		return sb.String(), nil
+	}
+
+	subject, err := execute("subject", messageTemplate.subject)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := execute("body", messageTemplate.body)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		EventID:   data.Event.ID,
+		EventType: string(data.Event.Type),
+		Subject:   subject,
+		Body:      body,
+		URL:       data.URL,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"amartha-test/constant"
+	"amartha-test/document"
+	"amartha-test/event"
+	"amartha-test/model"
+)
+
+func TestRender(t *testing.T) {
+	loan := model.Loan{
+		LoanID:                        7,
+		PublicID:                      "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24",
+		BorrowerID:                    1,
+		PrincipalAmount:               1500000,
+		InterestRate:                  0.1,
+		OrganizerBorrowerAggrementURL: "http://localhost:8080/v1/agreement/9/view",
+		Lending:                       []model.Lending{{LenderID: 2, InvestedAmount: 1500000, ReturnAmount: 1650000}},
+		DisbursementInfo:              model.DisbursementInfo{DisbursementDate: time.Date(2026, time.August, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	borrower := model.User{UserID: 1, UserName: "Septian", Locale: constant.LocaleIndonesian}
+	lender := model.User{UserID: 2, UserName: "Pratama", Locale: constant.LocaleEnglish}
+
+	tests := []struct {
+		name            string
+		data            TemplateData
+		expectedSubject string
+		expectedBody    []string
+		isError         bool
+	}{
+		{
+			name:            "loan approved in indonesian",
+			data:            TemplateData{User: borrower, Event: event.New(event.LoanApproved, 4, loan)},
+			expectedSubject: "Pinjaman 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 disetujui",
+			expectedBody:    []string{"Halo Septian", "Rp 1.500.000,00"},
+		},
+		{
+			name:            "lender agreement ready in english",
+			data:            TemplateData{User: lender, Event: event.New(event.AgreementReady, 2, loan), Lending: loan.Lending[0], URL: "http://localhost:8080/v1/agreement/8/view"},
+			expectedSubject: "Agreement of loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 ready to sign",
+			expectedBody:    []string{"Hi Pratama", "http://localhost:8080/v1/agreement/8/view"},
+		},
+		{
+			name:            "loan disbursed to borrower",
+			data:            TemplateData{User: borrower, Event: event.New(event.LoanDisbursed, 5, loan)},
+			expectedSubject: "Pinjaman 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 telah dicairkan",
+			expectedBody:    []string{"Pinjaman Anda sebesar Rp 1.500.000,00 telah dicairkan pada 17 Agustus 2026"},
+		},
+		{
+			name:            "loan disbursed to lender",
+			data:            TemplateData{User: lender, Event: event.New(event.LoanDisbursed, 5, loan), Lending: loan.Lending[0]},
+			expectedSubject: "Loan 5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24 disbursed",
+			expectedBody:    []string{"you funded with Rp 1,500,000.00 was disbursed on 17 August 2026", "Rp 1,650,000.00"},
+		},
+		{
+			name:    "event type without template",
+			data:    TemplateData{User: borrower, Event: event.New(event.LoanSubmitted, 1, loan)},
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// main func
+			message, err := Render(tt.data)
+
+			assert.Equal(t, tt.isError, err != nil)
+			if !tt.isError {
+				assert.Equal(t, tt.expectedSubject, message.Subject)
+				for _, v := range tt.expectedBody {
+					assert.Contains(t, message.Body, v)
+				}
+				assert.Equal(t, tt.data.Event.ID, message.EventID)
+				assert.Equal(t, string(tt.data.Event.Type), message.EventType)
+			}
+		})
+	}
+}
+
+func TestTemplatesCoverEveryLocale(t *testing.T) {
+	for _, eventType := range EventTypes() {
+		for _, locale := range document.Locales() {
+			_, err := Render(TemplateData{User: model.User{UserID: 1, Locale: locale}, Event: event.New(eventType, 1, model.Loan{LoanID: 1})})
+			assert.NoError(t, err, "template of %s in %s", eventType, locale)
+			assert.NotEmpty(t, templates[eventType][locale].subject, "template of %s in %s", eventType, locale)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document of the versioned REST API
+//
+//go:embed openapi.json
+var Spec []byte
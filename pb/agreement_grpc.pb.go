@@ -0,0 +1,199 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: agreement.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AgreementService_ListAgreements_FullMethodName       = "/amartha.v1.AgreementService/ListAgreements"
+	AgreementService_GetAgreementDocument_FullMethodName = "/amartha.v1.AgreementService/GetAgreementDocument"
+	AgreementService_SignAgreement_FullMethodName        = "/amartha.v1.AgreementService/SignAgreement"
+)
+
+// AgreementServiceClient is the client API for AgreementService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AgreementServiceClient interface {
+	ListAgreements(ctx context.Context, in *ListAgreementsRequest, opts ...grpc.CallOption) (*ListAgreementsResponse, error)
+	GetAgreementDocument(ctx context.Context, in *GetAgreementDocumentRequest, opts ...grpc.CallOption) (*AgreementDocument, error)
+	// SignAgreement returns the loan of the signed agreement
+	SignAgreement(ctx context.Context, in *SignAgreementRequest, opts ...grpc.CallOption) (*Loan, error)
+}
+
+type agreementServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgreementServiceClient(cc grpc.ClientConnInterface) AgreementServiceClient {
+	return &agreementServiceClient{cc}
+}
+
+func (c *agreementServiceClient) ListAgreements(ctx context.Context, in *ListAgreementsRequest, opts ...grpc.CallOption) (*ListAgreementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgreementsResponse)
+	err := c.cc.Invoke(ctx, AgreementService_ListAgreements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agreementServiceClient) GetAgreementDocument(ctx context.Context, in *GetAgreementDocumentRequest, opts ...grpc.CallOption) (*AgreementDocument, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgreementDocument)
+	err := c.cc.Invoke(ctx, AgreementService_GetAgreementDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agreementServiceClient) SignAgreement(ctx context.Context, in *SignAgreementRequest, opts ...grpc.CallOption) (*Loan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Loan)
+	err := c.cc.Invoke(ctx, AgreementService_SignAgreement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AgreementServiceServer is the server API for AgreementService service.
+// All implementations must embed UnimplementedAgreementServiceServer
+// for forward compatibility.
+type AgreementServiceServer interface {
+	ListAgreements(context.Context, *ListAgreementsRequest) (*ListAgreementsResponse, error)
+	GetAgreementDocument(context.Context, *GetAgreementDocumentRequest) (*AgreementDocument, error)
+	// SignAgreement returns the loan of the signed agreement
+	SignAgreement(context.Context, *SignAgreementRequest) (*Loan, error)
+	mustEmbedUnimplementedAgreementServiceServer()
+}
+
+// UnimplementedAgreementServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAgreementServiceServer struct{}
+
+func (UnimplementedAgreementServiceServer) ListAgreements(context.Context, *ListAgreementsRequest) (*ListAgreementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgreements not implemented")
+}
+func (UnimplementedAgreementServiceServer) GetAgreementDocument(context.Context, *GetAgreementDocumentRequest) (*AgreementDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgreementDocument not implemented")
+}
+func (UnimplementedAgreementServiceServer) SignAgreement(context.Context, *SignAgreementRequest) (*Loan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignAgreement not implemented")
+}
+func (UnimplementedAgreementServiceServer) mustEmbedUnimplementedAgreementServiceServer() {}
+func (UnimplementedAgreementServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAgreementServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgreementServiceServer will
+// result in compilation errors.
+type UnsafeAgreementServiceServer interface {
+	mustEmbedUnimplementedAgreementServiceServer()
+}
+
+func RegisterAgreementServiceServer(s grpc.ServiceRegistrar, srv AgreementServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAgreementServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AgreementService_ServiceDesc, srv)
+}
+
+func _AgreementService_ListAgreements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgreementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgreementServiceServer).ListAgreements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgreementService_ListAgreements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgreementServiceServer).ListAgreements(ctx, req.(*ListAgreementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgreementService_GetAgreementDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgreementDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgreementServiceServer).GetAgreementDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgreementService_GetAgreementDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgreementServiceServer).GetAgreementDocument(ctx, req.(*GetAgreementDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgreementService_SignAgreement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignAgreementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgreementServiceServer).SignAgreement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgreementService_SignAgreement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgreementServiceServer).SignAgreement(ctx, req.(*SignAgreementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AgreementService_ServiceDesc is the grpc.ServiceDesc for AgreementService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AgreementService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amartha.v1.AgreementService",
+	HandlerType: (*AgreementServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAgreements",
+			Handler:    _AgreementService_ListAgreements_Handler,
+		},
+		{
+			MethodName: "GetAgreementDocument",
+			Handler:    _AgreementService_GetAgreementDocument_Handler,
+		},
+		{
+			MethodName: "SignAgreement",
+			Handler:    _AgreementService_SignAgreement_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "agreement.proto",
+}
@@ -0,0 +1,4 @@
+// Package pb holds the generated protobuf messages and grpc stubs of proto/*.proto
+package pb
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=amartha-test --go-grpc_out=.. --go-grpc_opt=module=amartha-test user.proto loan.proto agreement.proto
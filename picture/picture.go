@@ -0,0 +1,133 @@
+package picture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+)
+
+const (
+	// MinDimension is the smallest width and height accepted, a smaller picture can not prove the visit
+	MinDimension = 200
+	// MaxDimension is the biggest width and height accepted, checked from the header before decoding
+	// so a crafted picture can not exhaust the memory
+	MaxDimension = 8000
+	// ThumbnailDimension is the longest side of the thumbnail
+	ThumbnailDimension = 256
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("picture type is not supported, it must be jpeg or png")
+	ErrInvalidPicture  = errors.New("picture can not be decoded")
+	ErrTooSmall        = fmt.Errorf("picture must be at least %dx%d pixels", MinDimension, MinDimension)
+	ErrTooLarge        = fmt.Errorf("picture must be at most %dx%d pixels", MaxDimension, MaxDimension)
+)
+
+// Picture is a validated picture re-encoded without its metadata, with its thumbnail
+type Picture struct {
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+	// Thumbnail is always a jpeg, its longest side is ThumbnailDimension
+	Thumbnail []byte
+}
+
+// Process validates the type and dimensions of the uploaded picture and re-encodes it from its pixels,
+// which drops the EXIF metadata, e.g. the gps position and the serial number of the camera.
+// The EXIF orientation is dropped too, a picture is stored as its pixels are laid out
+func Process(data []byte) (Picture, error) {
+	// 1. check type by content, the declared type of an upload is not trusted
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return Picture{}, ErrUnsupportedType
+	}
+
+	// 2. check dimensions
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Picture{}, fmt.Errorf("%w: %v", ErrInvalidPicture, err)
+	}
+	if config.Width < MinDimension || config.Height < MinDimension {
+		return Picture{}, ErrTooSmall
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return Picture{}, ErrTooLarge
+	}
+
+	// 3. decode and re-encode the pixels only
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Picture{}, fmt.Errorf("%w: %v", ErrInvalidPicture, err)
+	}
+	stripped, err := encode(img, contentType)
+	if err != nil {
+		return Picture{}, err
+	}
+
+	// 4. create thumbnail
+	thumbnail, err := encode(Thumbnail(img, ThumbnailDimension), ContentTypeJPEG)
+	if err != nil {
+		return Picture{}, err
+	}
+
+	return Picture{
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+		Data:        stripped,
+		Thumbnail:   thumbnail,
+	}, nil
+}
+
+// Thumbnail scales the image down so its longest side is at most dimension, keeping its aspect ratio.
+// The transparent pixels are flattened on white, since the thumbnail is a jpeg
+func Thumbnail(img image.Image, dimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > dimension || height > dimension {
+		if width >= height {
+			width, height = dimension, max(1, height*dimension/width)
+		} else {
+			width, height = max(1, width*dimension/height), dimension
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumbnail, thumbnail.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Over, nil)
+
+	return thumbnail
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case ContentTypeJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case ContentTypePNG:
+		err = png.Encode(&buf, img)
+	default:
+		err = ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", contentType, err)
+	}
+
+	return buf.Bytes(), nil
+}
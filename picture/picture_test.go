@@ -0,0 +1,189 @@
+package picture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newJPEG(t *testing.T, width int, height int) []byte {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, newImage(width, height), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func newPNG(t *testing.T, width int, height int) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, newImage(width, height))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func newImage(width int, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+// withEXIF inserts an APP1 EXIF segment holding the payload right after the start of image marker
+func withEXIF(data []byte, payload string) []byte {
+	segment := append([]byte("Exif\x00\x00"), payload...)
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(len(segment)+2))
+
+	var buf bytes.Buffer
+	buf.Write(data[:2])
+	buf.Write([]byte{0xff, 0xe1})
+	buf.Write(length)
+	buf.Write(segment)
+	buf.Write(data[2:])
+
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name                string
+		data                []byte
+		expectedContentType string
+		expectedWidth       int
+		expectedHeight      int
+		expectedErr         error
+	}{
+		{
+			name:                "success - jpeg",
+			data:                newJPEG(t, 640, 480),
+			expectedContentType: ContentTypeJPEG,
+			expectedWidth:       640,
+			expectedHeight:      480,
+		},
+		{
+			name:                "success - png",
+			data:                newPNG(t, 300, 900),
+			expectedContentType: ContentTypePNG,
+			expectedWidth:       300,
+			expectedHeight:      900,
+		},
+		{
+			name:        "error - unsupported type",
+			data:        []byte("GIF89a not supported"),
+			expectedErr: ErrUnsupportedType,
+		},
+		{
+			name:        "error - text is not a picture",
+			data:        []byte("image"),
+			expectedErr: ErrUnsupportedType,
+		},
+		{
+			name:        "error - truncated",
+			data:        newPNG(t, 300, 300)[:40],
+			expectedErr: ErrInvalidPicture,
+		},
+		{
+			name:        "error - too small",
+			data:        newPNG(t, 100, 300),
+			expectedErr: ErrTooSmall,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// main func
+			picture, err := Process(tt.data)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr != nil {
+				return
+			}
+			assert.Equal(t, tt.expectedContentType, picture.ContentType)
+			assert.Equal(t, tt.expectedWidth, picture.Width)
+			assert.Equal(t, tt.expectedHeight, picture.Height)
+
+			config, format, err := image.DecodeConfig(bytes.NewReader(picture.Data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedContentType, "image/"+format)
+			assert.Equal(t, tt.expectedWidth, config.Width)
+
+			thumbnail, format, err := image.DecodeConfig(bytes.NewReader(picture.Thumbnail))
+			assert.NoError(t, err)
+			assert.Equal(t, "jpeg", format)
+			assert.Equal(t, ThumbnailDimension, max(thumbnail.Width, thumbnail.Height))
+		})
+	}
+}
+
+func TestProcessTooLarge(t *testing.T) {
+	// only the header is read, the pixels of a too large picture are never decoded
+	var ihdr bytes.Buffer
+	ihdr.WriteString("IHDR")
+	binary.Write(&ihdr, binary.BigEndian, uint32(MaxDimension+1))
+	binary.Write(&ihdr, binary.BigEndian, uint32(MaxDimension))
+	ihdr.Write([]byte{8, 2, 0, 0, 0})
+
+	var header bytes.Buffer
+	header.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&header, binary.BigEndian, uint32(ihdr.Len()-4))
+	header.Write(ihdr.Bytes())
+	binary.Write(&header, binary.BigEndian, crc32.ChecksumIEEE(ihdr.Bytes()))
+
+	_, err := Process(header.Bytes())
+
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestProcessStripsEXIF(t *testing.T) {
+	data := withEXIF(newJPEG(t, 400, 300), "GPS -6.2088,106.8456 serial CAM-123")
+	assert.Contains(t, string(data), "CAM-123")
+
+	// main func
+	picture, err := Process(data)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(picture.Data), "Exif")
+	assert.NotContains(t, string(picture.Data), "CAM-123")
+	assert.NotContains(t, string(picture.Thumbnail), "CAM-123")
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name           string
+		width          int
+		height         int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "landscape", width: 1024, height: 768, expectedWidth: 256, expectedHeight: 192},
+		{name: "portrait", width: 300, height: 1200, expectedWidth: 64, expectedHeight: 256},
+		{name: "smaller than thumbnail is kept", width: 200, height: 100, expectedWidth: 200, expectedHeight: 100},
+		{name: "thin is at least one pixel", width: 4000, height: 2, expectedWidth: 256, expectedHeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// main func
+			thumbnail := Thumbnail(image.NewGray(image.Rect(0, 0, tt.width, tt.height)), ThumbnailDimension)
+
+			assert.Equal(t, tt.expectedWidth, thumbnail.Bounds().Dx())
+			assert.Equal(t, tt.expectedHeight, thumbnail.Bounds().Dy())
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"amartha-test/constant"
+)
+
+// DefaultRoute is the key of Policies applied to the routes without a policy of their own
+const DefaultRoute = "default"
+
+// Policy is a token bucket allowing Burst requests at once, refilled with Limit requests every Period.
+// A zero Limit is unlimited
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+type policyJSON struct {
+	Limit  int    `json:"limit"`
+	Period string `json:"period"`
+	Burst  int    `json:"burst"`
+}
+
+// UnmarshalJSON reads the period as a duration string, e.g. {"limit": 10, "period": "1m", "burst": 5}
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var v policyJSON
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+
+	period, err := time.ParseDuration(v.Period)
+	if err != nil && v.Limit > 0 {
+		return fmt.Errorf("parse period: %w", err)
+	}
+
+	*p = Policy{Limit: v.Limit, Period: period, Burst: v.Burst}
+	return nil
+}
+
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policyJSON{Limit: p.Limit, Period: p.Period.String(), Burst: p.Burst})
+}
+
+// Unlimited reports whether the policy lets every request through
+func (p Policy) Unlimited() bool {
+	return p.Limit <= 0
+}
+
+// Interval is the time to earn back one token
+func (p Policy) Interval() time.Duration {
+	return p.Period / time.Duration(p.Limit)
+}
+
+// Validate checks a limited policy refills and allows at least one request
+func (p Policy) Validate() error {
+	if p.Unlimited() {
+		return nil
+	}
+	if p.Period <= 0 {
+		return fmt.Errorf("period %s must be positive", p.Period)
+	}
+	if p.Burst <= 0 {
+		return fmt.Errorf("burst %d must be positive", p.Burst)
+	}
+	if p.Interval() <= 0 {
+		return fmt.Errorf("limit %d per %s is too fine", p.Limit, p.Period)
+	}
+
+	return nil
+}
+
+// Policies are the policies per route, keyed by "METHOD /path/template" as routed, e.g. "POST /v1/loan/submit",
+// the DefaultRoute policy applies to the others
+type Policies map[string]Policy
+
+// DefaultPolicies returns the policies used when nothing is configured: loan submission and agreement
+// viewing are limited tight against flooding and id guessing, the probes and metrics are left unlimited
+func DefaultPolicies() Policies {
+	return Policies{
+		DefaultRoute: {Limit: 600, Period: time.Minute, Burst: 100},
+		"POST " + constant.APIVersionPrefix + "/loan/submit":                  {Limit: 10, Period: time.Minute, Burst: 5},
+		"GET " + constant.APIVersionPrefix + "/agreement/{agreement_id}/view": {Limit: 30, Period: time.Minute, Burst: 10},
+		"GET /healthz": {},
+		"GET /readyz":  {},
+		"GET /metrics": {},
+	}
+}
+
+// For returns the policy of the route
+func (p Policies) For(method string, route string) Policy {
+	policy, exists := p[method+" "+route]
+	if !exists {
+		policy = p[DefaultRoute]
+	}
+
+	return policy
+}
+
+// Validate checks every policy
+func (p Policies) Validate() error {
+	for route, policy := range p {
+		err := policy.Validate()
+		if err != nil {
+			return fmt.Errorf("rate limit of %q: %w", route, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		expectedPolicy Policy
+		isError        bool
+	}{
+		{
+			name:           "success - limited",
+			content:        `{"limit": 10, "period": "1m", "burst": 5}`,
+			expectedPolicy: Policy{Limit: 10, Period: time.Minute, Burst: 5},
+		},
+		{
+			name:           "success - unlimited",
+			content:        `{}`,
+			expectedPolicy: Policy{},
+		},
+		{
+			name:    "error - invalid period",
+			content: `{"limit": 10, "period": "soon", "burst": 5}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy Policy
+			err := json.Unmarshal([]byte(tt.content), &policy)
+
+			assert.Equal(t, tt.isError, err != nil)
+			if !tt.isError {
+				assert.Equal(t, tt.expectedPolicy, policy)
+
+				content, err := json.Marshal(policy)
+				assert.NoError(t, err)
+				var roundTrip Policy
+				assert.NoError(t, json.Unmarshal(content, &roundTrip))
+				assert.Equal(t, policy, roundTrip)
+			}
+		})
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	policies := DefaultPolicies()
+	assert.NoError(t, policies.Validate())
+
+	assert.Equal(t, policies["POST /v1/loan/submit"], policies.For("POST", "/v1/loan/submit"))
+	assert.Equal(t, policies[DefaultRoute], policies.For("GET", "/v1/loan/list"))
+	assert.True(t, policies.For("GET", "/healthz").Unlimited())
+
+	policies["POST /v1/loan/submit"] = Policy{Limit: 10, Period: time.Minute}
+	assert.Error(t, policies.Validate())
+}
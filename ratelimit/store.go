@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the memory store drops the buckets refilled to full
+const pruneInterval = time.Minute
+
+// Decision is the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of requests still allowed at once after this one
+	Remaining int
+	// RetryAfter is the wait before the next token, zero when allowed
+	RetryAfter time.Duration
+}
+
+// IStore keeps the token buckets. The memory store limits a single server, a shared store
+// (e.g. redis) implements the same interface to limit across every instance
+type IStore interface {
+	// Take takes a token from the bucket of the key, refilled as the policy says
+	Take(ctx context.Context, key string, policy Policy) (Decision, error)
+}
+
+// MemoryStore is an in-memory IStore, buckets refilled to full are pruned
+type MemoryStore struct {
+	mutex     sync.Mutex
+	now       func() time.Time
+	buckets   map[string]bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	burst     int
+	updatedAt time.Time
+	interval  time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:     time.Now,
+		buckets: make(map[string]bucket),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Decision, error) {
+	if policy.Unlimited() {
+		return Decision{Allowed: true, Remaining: math.MaxInt}, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = bucket{tokens: float64(policy.Burst), updatedAt: now}
+	}
+	b.burst = policy.Burst
+	b.interval = policy.Interval()
+	b.refill(now)
+
+	if b.tokens < 1 {
+		s.buckets[key] = b
+		return Decision{
+			RetryAfter: time.Duration((1 - b.tokens) * float64(b.interval)),
+		}, nil
+	}
+
+	b.tokens--
+	s.buckets[key] = b
+
+	return Decision{
+		Allowed:   true,
+		Remaining: int(b.tokens),
+	}, nil
+}
+
+// refill adds the tokens earned since the last update, up to the burst
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.burst), b.tokens+float64(elapsed)/float64(b.interval))
+	}
+	b.updatedAt = now
+}
+
+// prune removes the buckets full again at most once per pruneInterval, must be called with the lock held
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for k, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.burst) {
+			delete(s.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2026, time.October, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+	policy := Policy{Limit: 6, Period: time.Minute, Burst: 2}
+
+	// burst is allowed at once
+	decision, err := store.Take(ctx, "actor", policy)
+	assert.NoError(t, err)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 1}, decision)
+	decision, _ = store.Take(ctx, "actor", policy)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 0}, decision)
+
+	// empty bucket tells when the next token comes
+	decision, _ = store.Take(ctx, "actor", policy)
+	assert.Equal(t, Decision{RetryAfter: 10 * time.Second}, decision)
+	now = now.Add(4 * time.Second)
+	decision, _ = store.Take(ctx, "actor", policy)
+	assert.Equal(t, Decision{RetryAfter: 6 * time.Second}, decision)
+
+	// other keys have a bucket of their own
+	decision, _ = store.Take(ctx, "other-actor", policy)
+	assert.True(t, decision.Allowed)
+
+	// token is earned back after the interval
+	now = now.Add(6 * time.Second)
+	decision, _ = store.Take(ctx, "actor", policy)
+	assert.Equal(t, Decision{Allowed: true, Remaining: 0}, decision)
+
+	// unlimited policy keeps no bucket
+	decision, _ = store.Take(ctx, "probe", Policy{})
+	assert.True(t, decision.Allowed)
+	assert.NotContains(t, store.buckets, "probe")
+
+	// buckets full again are pruned
+	now = now.Add(time.Hour)
+	store.Take(ctx, "actor", policy)
+	assert.Len(t, store.buckets, 1)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/logging"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+// ListAgreements returns every agreement matching the filter
+func (s *Service) ListAgreements(ctx context.Context, filter model.AgreementFilter) []model.Aggrement {
+	return s.Helper.GetAgreementsByFilter(ctx, filter)
+}
+
+// OpenAgreement returns the agreement of the public id with its pdf document, the caller must close the document content.
+// It is only served to the authenticated signer of the agreement and to the employees,
+// userID is the user of the verified bearer token, zero when the request is anonymous
+func (s *Service) OpenAgreement(ctx context.Context, agreementID string, userID int64) (model.Aggrement, storage.Document, error) {
+	ctx = logging.With(ctx, "agreement_id", agreementID)
+
+	// 1. sanitize payload
+	if userID == 0 {
+		logging.FromContext(ctx).Info("user id is empty", "op", "OpenAgreement")
+		return model.Aggrement{}, storage.Document{}, apperror.Unauthenticated.New()
+	}
+	_, err := uuid.Parse(agreementID)
+	if err != nil {
+		logging.FromContext(ctx).Info("agreement id is invalid", "op", "OpenAgreement", "error", err)
+		return model.Aggrement{}, storage.Document{}, apperror.InvalidRequest.Wrap(err).WithDetail("field", "agreement_id")
+	}
+
+	// 2. get user by user id
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data is not found", "op", "OpenAgreement", "user_id", userID)
+		return model.Aggrement{}, storage.Document{}, apperror.Unauthenticated.New().WithDetail("user_id", userID)
+	}
+
+	// 3. get agreement by public id
+	agreement := s.Helper.GetAgreementByPublicID(ctx, agreementID)
+	if agreement.AggrementID == 0 {
+		logging.FromContext(ctx).Info("agreement data is not found", "op", "OpenAgreement", "user_id", userID)
+		return model.Aggrement{}, storage.Document{}, apperror.AgreementNotFound.New().WithDetail("agreement_id", agreementID)
+	}
+
+	// 4. check user is allowed to see the agreement
+	isEmployee := user.UserType == constant.UserTypeFieldValidatorEmployee || user.UserType == constant.UserTypeFieldOfficerEmployee
+	if !isEmployee && agreement.UserID != userID {
+		logging.FromContext(ctx).Info("user is not allowed to see agreement", "op", "OpenAgreement", "user_id", userID)
+		return model.Aggrement{}, storage.Document{}, apperror.AccessDenied.New().WithDetail("user_id", userID)
+	}
+
+	// 5. open agreement document from document store
+	document, err := s.Helper.OpenAgreementDocument(ctx, agreement)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to open agreement document", "op", "OpenAgreement", "user_id", userID, "error", err)
+		return model.Aggrement{}, storage.Document{}, err
+	}
+
+	return agreement, document, nil
+}
+
+// SignAgreement signs the agreement by its user, the borrower agreement is generated once every lender signed
+// and the loan is signed once the borrower signed. The agreement and the loan are identified by their public ids,
+// userID is the user of the verified bearer token, zero when the request is anonymous
+func (s *Service) SignAgreement(ctx context.Context, agreementID string, loanID string, userID int64) (model.Loan, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. sanitize payload
+	if userID == 0 {
+		logging.FromContext(ctx).Info("user id is empty", "op", "SignAgreement", "agreement_id", agreementID)
+		return model.Loan{}, apperror.Unauthenticated.New()
+	}
+	if loanID == "" {
+		logging.FromContext(ctx).Info("loan id is empty", "op", "SignAgreement", "agreement_id", agreementID)
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "loan_id")
+	}
+
+	// 2. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data not found", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	// 3. check loan status
+	if loan.Status != constant.LoanStatusInvested {
+		logging.FromContext(ctx).Info("loan status invalid", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID, "current_status", constant.GetLoanStatusDesc(loan.Status))
+		return model.Loan{}, apperror.InvalidLoanStatus.New().WithDetail("loan_id", loanID).WithDetail("current_status", constant.GetLoanStatusDesc(loan.Status))
+	}
+
+	// 4. get user by user id
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data not found", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.Unauthenticated.New().WithDetail("user_id", userID)
+	}
+
+	// 5. get agreement by public id
+	agreement := s.Helper.GetAgreementByPublicID(ctx, agreementID)
+	if agreement.AggrementID == 0 {
+		logging.FromContext(ctx).Info("agreement data not found", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.AgreementNotFound.New().WithDetail("agreement_id", agreementID)
+	}
+
+	// 6. wrong user to sign
+	if agreement.UserID != userID {
+		logging.FromContext(ctx).Info("wrong user to sign this agreement", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.WrongSigner.New().WithDetail("agreement_id", agreementID).WithDetail("user_id", userID)
+	}
+
+	// 7. check agreement belongs to loan
+	if agreement.LoanID != loan.LoanID {
+		logging.FromContext(ctx).Info("agreement does not belong to this loan", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.AgreementLoanMismatch.New().WithDetail("agreement_id", agreementID).WithDetail("loan_id", loanID)
+	}
+
+	// 8. check agreement sign
+	if agreement.IsSigned {
+		logging.FromContext(ctx).Info("agreement already signed", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID)
+		return model.Loan{}, apperror.AgreementAlreadySigned.New().WithDetail("agreement_id", agreementID)
+	}
+
+	// 9. generate agreement sign pdf on behalf of the signer, before the agreement is signed so a failure can be retried
+	ctx = audit.WithActor(ctx, audit.UserActor(userID))
+	err := s.Helper.GenerateSignedAgreementPDF(ctx, &loan, agreement)
+	if err != nil {
+		logging.FromContext(ctx).Error("fail to generate signed agreement pdf", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID, "error", err)
+		return model.Loan{}, apperror.AgreementGenerationFailed.Wrap(err).WithDetail("loan_id", loanID)
+	}
+
+	// 10. update agreement sign
+	agreement.IsSigned = true
+	signedAt := time.Now()
+	agreement.SignedAt = &signedAt
+	s.Helper.UpsertAgreement(ctx, agreement)
+
+	// 11. check based on user type
+	if user.UserType == constant.UserTypeLender {
+		// 11a. check agreement is completely signed by all lender
+		isCompletelySignedByLender, err := s.Helper.CheckAgreementCompletelySignedByLender(ctx, loan)
+		if err != nil {
+			logging.FromContext(ctx).Error("check agreement completely signed by lender got fail", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID, "error", err)
+			return model.Loan{}, err
+		}
+		if isCompletelySignedByLender {
+			// 11b. generate borrower agreement pdf
+			err = s.Helper.GenerateBorrowerAgreementPDF(ctx, &loan)
+			if err != nil {
+				logging.FromContext(ctx).Error("fail to generate borrower agreement pdf", "op", "SignAgreement", "agreement_id", agreementID, "user_id", userID, "error", err)
+				return model.Loan{}, apperror.AgreementGenerationFailed.Wrap(err).WithDetail("loan_id", loanID)
+			}
+		}
+	} else if user.UserType == constant.UserTypeBorrower {
+		// 11c. if borrower sign, must be final sign, and move status to signed
+		loan.Status = constant.LoanStatusSigned
+		loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
+		s.Helper.UpsertLoan(ctx, loan)
+	}
+
+	// 12. publish the signature, the borrower agreement once generated, and the signed loan
+	s.publish(ctx, event.New(event.AgreementSigned, userID, loan).WithAgreement(agreement))
+	if user.UserType == constant.UserTypeLender && loan.OrganizerBorrowerAggrementURL != "" {
+		s.publish(ctx, event.New(event.AgreementReady, userID, loan))
+	}
+	if loan.Status == constant.LoanStatusSigned {
+		s.publish(ctx, event.New(event.LoanSigned, userID, loan))
+	}
+
+	return loan, nil
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/storage"
+)
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func TestOpenAgreement(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	agreementID := "0b7e6a4c-3d1f-4e8a-9c2b-5f6d7e8a9b0c"
+	agreement := model.Aggrement{AggrementID: 5, PublicID: agreementID, UserID: 2, DocumentKey: "0a1b"}
+	borrower := model.User{UserID: 1, UserType: constant.UserTypeBorrower}
+	lender := model.User{UserID: 2, UserType: constant.UserTypeLender}
+	employee := model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}
+
+	tests := []struct {
+		name        string
+		agreementID string
+		userID      int64
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - anonymous user",
+			agreementID: agreementID,
+			expectedErr: apperror.Unauthenticated,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - agreement id is empty",
+			userID:      2,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - sequential agreement id is not accepted",
+			agreementID: "5",
+			userID:      2,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - user not found",
+			agreementID: agreementID,
+			userID:      99,
+			expectedErr: apperror.Unauthenticated,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(99)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:        "error - agreement not found",
+			agreementID: "9c2b5f6d-7e8a-4b0c-8b7e-6a4c3d1f4e8a",
+			userID:      2,
+			expectedErr: apperror.AgreementNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, "9c2b5f6d-7e8a-4b0c-8b7e-6a4c3d1f4e8a").Return(model.Aggrement{}).Once()
+			},
+		},
+		{
+			name:        "error - other user",
+			agreementID: agreementID,
+			userID:      1,
+			expectedErr: apperror.AccessDenied,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementID).Return(agreement).Once()
+			},
+		},
+		{
+			name:        "error - document not found",
+			agreementID: agreementID,
+			userID:      2,
+			expectedErr: apperror.DocumentNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementID).Return(agreement).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, agreement).Return(storage.Document{}, apperror.DocumentNotFound.New()).Once()
+			},
+		},
+		{
+			name:        "success - signer",
+			agreementID: agreementID,
+			userID:      2,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementID).Return(agreement).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, agreement).Return(storage.Document{
+					Key:     agreement.DocumentKey,
+					Content: nopReadSeekCloser{bytes.NewReader([]byte("%PDF-1.3"))},
+				}, nil).Once()
+			},
+		},
+		{
+			name:        "success - employee",
+			agreementID: agreementID,
+			userID:      4,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(employee).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, agreementID).Return(agreement).Once()
+				mockHelper.On("OpenAgreementDocument", mock.Anything, agreement).Return(storage.Document{
+					Key:     agreement.DocumentKey,
+					Content: nopReadSeekCloser{bytes.NewReader([]byte("%PDF-1.3"))},
+				}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			result, document, err := svc.OpenAgreement(context.Background(), tt.agreementID, tt.userID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, agreement, result)
+				assert.Equal(t, agreement.DocumentKey, document.Key)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSignAgreement(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	borrower := model.User{UserID: 1, UserType: constant.UserTypeBorrower}
+	lender := model.User{UserID: 2, UserType: constant.UserTypeLender}
+	investedLoan := model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusInvested, Lending: []model.Lending{{LenderID: 2}}}
+	lenderAgreement := model.Aggrement{AggrementID: 5, PublicID: "7a3e9c1b-4d2f-4b8a-a6e0-2c9f5b1d8e47", LoanID: 1, UserID: 2, AgreementType: constant.AgreementTypeOrganizerLender}
+	borrowerAgreement := model.Aggrement{AggrementID: 6, PublicID: "e4b2d8f6-1a9c-4e3b-b7d5-0f6a2c8e4b91", LoanID: 1, UserID: 1, AgreementType: constant.AgreementTypeOrganizerBorrower}
+
+	tests := []struct {
+		name           string
+		agreementID    string
+		loanID         string
+		userID         int64
+		expectedErr    error
+		expectedStatus int
+		mocks          func()
+	}{
+		{
+			name:        "error - anonymous signer",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			expectedErr: apperror.Unauthenticated,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - loan id is empty",
+			agreementID: lenderAgreement.PublicID,
+			userID:      2,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - signer not found",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      99,
+			expectedErr: apperror.Unauthenticated,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(99)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:        "error - loan is not invested",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      2,
+			expectedErr: apperror.InvalidLoanStatus,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+			},
+		},
+		{
+			name:        "error - wrong signer",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      1,
+			expectedErr: apperror.WrongSigner,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, lenderAgreement.PublicID).Return(lenderAgreement).Once()
+			},
+		},
+		{
+			name:        "error - agreement not found",
+			agreementID: "5",
+			loanID:      loanPublicID,
+			userID:      2,
+			expectedErr: apperror.AgreementNotFound,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, "5").Return(model.Aggrement{}).Once()
+			},
+		},
+		{
+			name:        "error - agreement of other loan",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      2,
+			expectedErr: apperror.AgreementLoanMismatch,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, lenderAgreement.PublicID).Return(model.Aggrement{AggrementID: 5, LoanID: 2, UserID: 2}).Once()
+			},
+		},
+		{
+			name:        "error - agreement already signed",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      2,
+			expectedErr: apperror.AgreementAlreadySigned,
+			mocks: func() {
+				signed := lenderAgreement
+				signed.IsSigned = true
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, lenderAgreement.PublicID).Return(signed).Once()
+			},
+		},
+		{
+			name:        "error - generate signed agreement failed",
+			agreementID: lenderAgreement.PublicID,
+			loanID:      loanPublicID,
+			userID:      2,
+			expectedErr: apperror.AgreementGenerationFailed,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, lenderAgreement.PublicID).Return(lenderAgreement).Once()
+				// the agreement is left unsigned, so the signer can retry
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("render failed")).Once()
+			},
+		},
+		{
+			name:           "success - last lender signs, borrower agreement generated",
+			agreementID:    lenderAgreement.PublicID,
+			loanID:         loanPublicID,
+			userID:         2,
+			expectedStatus: constant.LoanStatusInvested,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, lenderAgreement.PublicID).Return(lenderAgreement).Once()
+				mockHelper.On("UpsertAgreement", mock.Anything, mock.MatchedBy(func(agreement model.Aggrement) bool {
+					return agreement.IsSigned && agreement.SignedAt != nil
+				})).Return().Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("CheckAgreementCompletelySignedByLender", mock.Anything, mock.Anything).Return(true, nil).Once()
+				mockHelper.On("GenerateBorrowerAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:           "success - borrower signs, loan signed",
+			agreementID:    borrowerAgreement.PublicID,
+			loanID:         loanPublicID,
+			userID:         1,
+			expectedStatus: constant.LoanStatusSigned,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(investedLoan).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetAgreementByPublicID", mock.Anything, borrowerAgreement.PublicID).Return(borrowerAgreement).Once()
+				mockHelper.On("UpsertAgreement", mock.Anything, mock.Anything).Return().Once()
+				mockHelper.On("GenerateSignedAgreementPDF", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.MatchedBy(func(loan model.Loan) bool {
+					return loan.Status == constant.LoanStatusSigned
+				})).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			loan, err := svc.SignAgreement(context.Background(), tt.agreementID, tt.loanID, tt.userID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, loan.Status)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
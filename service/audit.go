@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/audit"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// ListAuditEntries returns every audit log entry matching the filter, ordered by sequence
+func (s *Service) ListAuditEntries(ctx context.Context, filter model.AuditFilter) []model.AuditEntry {
+	return s.Helper.GetAuditEntriesByFilter(ctx, filter)
+}
+
+// VerifyAuditLog recomputes the hash chain of the whole audit log, an invalid chain means an entry was altered or removed
+func (s *Service) VerifyAuditLog(ctx context.Context) model.AuditVerification {
+	verification := audit.Verify(s.Helper.GetAuditEntries(ctx))
+	if !verification.IsValid {
+		logging.FromContext(ctx).Info("audit log hash chain is broken", "op", "VerifyAuditLog", "sequence", verification.BrokenSequence)
+	}
+
+	return verification
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/audit"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestVerifyAuditLog(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	entry, err := audit.NewEntry(context.Background(), "loan", loanPublicID, nil, model.Loan{LoanID: 1, PublicID: loanPublicID})
+	assert.NoError(t, err)
+	entry = audit.Chain(entry, 1, audit.GenesisHash)
+	tampered := entry
+	tampered.Actor = "user:1"
+
+	tests := []struct {
+		name     string
+		entries  []model.AuditEntry
+		expected model.AuditVerification
+	}{
+		{
+			name:     "valid",
+			entries:  []model.AuditEntry{entry},
+			expected: model.AuditVerification{IsValid: true, EntryCount: 1, LastHash: entry.Hash},
+		},
+		{
+			name:     "tampered",
+			entries:  []model.AuditEntry{tampered},
+			expected: model.AuditVerification{EntryCount: 1, BrokenSequence: 1, LastHash: audit.GenesisHash},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper.On("GetAuditEntries", mock.Anything).Return(tt.entries).Once()
+
+			// main func
+			verification := svc.VerifyAuditLog(context.Background())
+
+			assert.Equal(t, tt.expected, verification)
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
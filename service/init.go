@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/event"
+	"amartha-test/helper"
+	"amartha-test/metrics"
+	"amartha-test/model"
+	"amartha-test/storage"
+	"amartha-test/webhook"
+)
+
+// IService is the business rules of the loan service, the errors returned are coded apperror errors
+// which every transport maps to its own status
+type IService interface {
+	// service user
+	ListUsers(ctx context.Context, filter model.UserFilter) []model.User
+	GetUser(ctx context.Context, userID int64) (model.User, error)
+	ListNotifications(ctx context.Context, userID int64) ([]model.Notification, error)
+
+	// service loan
+	ListLoans(ctx context.Context, filter model.LoanFilter) []model.Loan
+	GetLoan(ctx context.Context, loanID string) (model.Loan, error)
+	WatchLoan(ctx context.Context, loanID string) (model.Loan, <-chan event.Event, error)
+	SubmitLoan(ctx context.Context, borrowerID int64, principalAmount float64, interestRate float64) (model.Loan, error)
+	ApproveLoan(ctx context.Context, loanID string, approvalInfo model.ApprovalInfo, pictureProof []byte) (model.Loan, error)
+	OpenPictureProof(ctx context.Context, loanID string, userID int64, thumbnail bool) (model.PictureProof, storage.Document, error)
+	Invest(ctx context.Context, loanID string, lenderID int64, amount float64) (model.Loan, error)
+	Disburse(ctx context.Context, loanID string, disbursement model.Disbursement) (model.Loan, error)
+
+	// service task
+	ListEmployeeTasks(ctx context.Context, userID int64, filter model.TaskFilter) ([]model.Task, error)
+
+	// service agreement
+	ListAgreements(ctx context.Context, filter model.AgreementFilter) []model.Aggrement
+	OpenAgreement(ctx context.Context, agreementID string, userID int64) (model.Aggrement, storage.Document, error)
+	SignAgreement(ctx context.Context, agreementID string, loanID string, userID int64) (model.Loan, error)
+
+	// service webhook
+	Subscribe(ctx context.Context, userID int64, url string, eventTypes []string, secret string) (model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) []model.WebhookSubscription
+	Unsubscribe(ctx context.Context, subscriptionID int64) (model.WebhookSubscription, error)
+	ListDeadLetters(ctx context.Context) []model.WebhookDelivery
+	RetryDeadLetter(ctx context.Context, deliveryID int64) (model.WebhookDelivery, error)
+
+	// service audit
+	ListAuditEntries(ctx context.Context, filter model.AuditFilter) []model.AuditEntry
+	VerifyAuditLog(ctx context.Context) model.AuditVerification
+
+	// service stats
+	GetStats(ctx context.Context) model.Stats
+}
+
+// WatchBufferSize is the count of events buffered for each loan watcher
+const WatchBufferSize = 16
+
+// Service holds the business rules shared by the REST and gRPC transports
+type Service struct {
+	IService
+	Helper helper.IHelper
+	// Events receives a domain event for every loan state change
+	Events event.IBus
+	// Webhooks redelivers the dead-lettered webhook deliveries, retrying is unavailable when nil
+	Webhooks webhook.IDispatcher
+	// WebhookGuard refuses the webhook urls reaching a loopback, private or otherwise special address
+	WebhookGuard *webhook.Guard
+	// Metrics counts the rejected investments, nothing is counted when nil
+	Metrics metrics.IMetrics
+	// Visits is how the field visits are checked against the borrower address
+	Visits VisitPolicy
+}
+
+func NewService(helper helper.IHelper) *Service {
+	return &Service{
+		Helper:       helper,
+		Events:       event.NewBus(),
+		WebhookGuard: webhook.NewGuard(),
+	}
+}
+
+// publish emits the event once the state change is stored
+func (s *Service) publish(ctx context.Context, e event.Event) {
+	if s.Events == nil {
+		return
+	}
+
+	s.Events.Publish(ctx, e)
+}
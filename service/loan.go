@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"amartha-test/apperror"
+	"amartha-test/audit"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/logging"
+	"amartha-test/model"
+	"amartha-test/picture"
+	"amartha-test/storage"
+)
+
+// ListLoans returns every loan matching the filter
+func (s *Service) ListLoans(ctx context.Context, filter model.LoanFilter) []model.Loan {
+	return s.Helper.GetLoansByFilter(ctx, filter)
+}
+
+// GetLoan returns the loan of the public loan id, the sequential loan id is not accepted
+func (s *Service) GetLoan(ctx context.Context, loanID string) (model.Loan, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. sanitize payload
+	if loanID == "" {
+		logging.FromContext(ctx).Info("loan id is empty", "op", "GetLoan")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "loan_id")
+	}
+
+	// 2. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data is not found", "op", "GetLoan")
+		return model.Loan{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	return loan, nil
+}
+
+// WatchLoan returns the loan and the events changing it until ctx is done, when the channel is closed.
+// The events are buffered per watcher and a watcher falling behind loses its oldest events, so a slow
+// reader never holds the publisher and still receives the latest state of the loan.
+// The watcher subscribes before the loan is read, so a change landing in between is still an event, at worst
+// an event the returned loan already holds
+func (s *Service) WatchLoan(ctx context.Context, loanID string) (model.Loan, <-chan event.Event, error) {
+	// 1. check event bus
+	if s.Events == nil {
+		logging.FromContext(ctx).Info("event bus is not configured", "op", "WatchLoan", "loan_id", loanID)
+		return model.Loan{}, nil, apperror.Internal.New().WithDetail("loan_id", loanID)
+	}
+
+	// 2. subscribe to the events of the loan
+	var mutex sync.Mutex
+	isClosed := false
+	events := make(chan event.Event, WatchBufferSize)
+	unsubscribe := s.Events.Subscribe(func(_ context.Context, e event.Event) {
+		if e.Loan.PublicID != loanID {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if isClosed {
+			return
+		}
+		for {
+			select {
+			case events <- e:
+				return
+			default:
+				// watcher is behind, drop its oldest event to make room
+				select {
+				case <-events:
+				default:
+				}
+			}
+		}
+	})
+
+	// 3. get loan by loan id
+	loan, err := s.GetLoan(ctx, loanID)
+	if err != nil {
+		unsubscribe()
+		return model.Loan{}, nil, err
+	}
+
+	// 4. close the events once the watcher is gone
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		isClosed = true
+		close(events)
+	}()
+
+	return loan, events, nil
+}
+
+// SubmitLoan creates a new proposed loan of the borrower
+func (s *Service) SubmitLoan(ctx context.Context, borrowerID int64, principalAmount float64, interestRate float64) (model.Loan, error) {
+	// 1. sanitize payload
+	if borrowerID == 0 {
+		logging.FromContext(ctx).Info("borrower id is empty", "op", "SubmitLoan")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "borrower_id")
+	}
+	if principalAmount == 0 {
+		logging.FromContext(ctx).Info("principal amount is empty", "op", "SubmitLoan", "borrower_id", borrowerID)
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "principal_amount")
+	}
+	if interestRate < 0 || interestRate > 1 {
+		logging.FromContext(ctx).Info("interest rate is invalid", "op", "SubmitLoan", "borrower_id", borrowerID, "amount", principalAmount)
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "interest_rate")
+	}
+
+	// 2. get borrower by user id
+	borrower := s.Helper.GetUserByUserID(ctx, borrowerID)
+	if borrower.UserID == 0 {
+		logging.FromContext(ctx).Info("borrower data is not found", "op", "SubmitLoan", "borrower_id", borrowerID, "amount", principalAmount, "rate", interestRate)
+		return model.Loan{}, apperror.UserNotFound.New().WithDetail("user_id", borrowerID)
+	}
+
+	// 3. check user status
+	if borrower.UserType != constant.UserTypeBorrower {
+		logging.FromContext(ctx).Info("user type is not borrower", "op", "SubmitLoan", "borrower_id", borrowerID, "amount", principalAmount, "rate", interestRate)
+		return model.Loan{}, apperror.UserTypeNotAllowed.New().WithDetail("user_id", borrowerID).WithDetail("required_user_type", constant.UserTypeBorrower)
+	}
+
+	// 4. create loan, on behalf of the borrower
+	ctx = audit.WithActor(ctx, audit.UserActor(borrowerID))
+	loan := model.Loan{
+		LoanID:          s.Helper.GenerateIncrementalLoanID(ctx),
+		PublicID:        uuid.NewString(),
+		BorrowerID:      borrowerID,
+		PrincipalAmount: principalAmount,
+		InterestRate:    interestRate,
+		CreatedAt:       time.Now(),
+		Status:          constant.LoanStatusProposed,
+	}
+	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
+	s.Helper.UpsertLoan(ctx, loan)
+	s.publish(ctx, event.New(event.LoanSubmitted, borrowerID, loan))
+
+	return loan, nil
+}
+
+// ApproveLoan approves the proposed loan by a field validator employee, with the picture taken on the field visit.
+// The picture is checked and stripped of its metadata before it is stored, only its document keys are kept on the loan
+func (s *Service) ApproveLoan(ctx context.Context, loanID string, approvalInfo model.ApprovalInfo, pictureProof []byte) (model.Loan, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. sanitize payload
+	if len(pictureProof) == 0 {
+		logging.FromContext(ctx).Info("picture proof is empty", "op", "ApproveLoan")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "picture_proof")
+	}
+	if approvalInfo.FieldValidatorEmployeeID == 0 {
+		logging.FromContext(ctx).Info("field validator employee id is empty", "op", "ApproveLoan")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "field_validator_employee_id")
+	}
+
+	// 2. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data is not found", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID)
+		return model.Loan{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	// 3. check loan status
+	if loan.Status != constant.LoanStatusProposed {
+		logging.FromContext(ctx).Info("loan status invalid", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID, "current_status", constant.GetLoanStatusDesc(loan.Status))
+		return model.Loan{}, apperror.InvalidLoanStatus.New().WithDetail("loan_id", loanID).WithDetail("current_status", constant.GetLoanStatusDesc(loan.Status))
+	}
+
+	// 4. get field validator employee by user id
+	fieldValidatorEmployee := s.Helper.GetUserByUserID(ctx, approvalInfo.FieldValidatorEmployeeID)
+	if fieldValidatorEmployee.UserID == 0 {
+		logging.FromContext(ctx).Info("field validator employee data is not found", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID)
+		return model.Loan{}, apperror.UserNotFound.New().WithDetail("user_id", approvalInfo.FieldValidatorEmployeeID)
+	}
+
+	// 5. check user type
+	if fieldValidatorEmployee.UserType != constant.UserTypeFieldValidatorEmployee {
+		logging.FromContext(ctx).Info("user type is not field validator employee", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID)
+		return model.Loan{}, apperror.UserTypeNotAllowed.New().WithDetail("user_id", approvalInfo.FieldValidatorEmployeeID).WithDetail("required_user_type", constant.UserTypeFieldValidatorEmployee)
+	}
+
+	// 6. check field visit
+	visit, err := s.checkVisit(ctx, "ApproveLoan", loan, approvalInfo.Visit)
+	if err != nil {
+		return model.Loan{}, err
+	}
+
+	// 7. check picture proof, stripping its metadata
+	proof, err := picture.Process(pictureProof)
+	if err != nil {
+		logging.FromContext(ctx).Info("picture proof is invalid", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID, "error", err)
+		return model.Loan{}, apperror.InvalidPictureProof.Wrap(err).WithDetail("reason", err.Error())
+	}
+
+	// 8. store picture proof
+	storedProof, err := s.Helper.PutPictureProof(ctx, proof)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to store picture proof", "op", "ApproveLoan", "employee_id", approvalInfo.FieldValidatorEmployeeID, "error", err)
+		return model.Loan{}, apperror.Internal.Wrap(err).WithDetail("loan_id", loanID)
+	}
+
+	// 9. update loan status to approve, on behalf of the field validator employee
+	ctx = audit.WithActor(ctx, audit.UserActor(approvalInfo.FieldValidatorEmployeeID))
+	loan.Status = constant.LoanStatusApproved
+	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
+	loan.ApprovalInfo = &model.ApprovalInfo{
+		FieldValidatorEmployeeID: approvalInfo.FieldValidatorEmployeeID,
+		ApprovalDate:             approvalInfo.ApprovalDate,
+		Proof:                    &storedProof,
+		Visit:                    visit,
+	}
+	if loan.ApprovalInfo.ApprovalDate.IsZero() {
+		loan.ApprovalInfo.ApprovalDate = time.Now()
+	}
+	s.Helper.UpsertLoan(ctx, loan)
+	s.publish(ctx, event.New(event.LoanApproved, approvalInfo.FieldValidatorEmployeeID, loan))
+
+	return loan, nil
+}
+
+// OpenPictureProof returns the picture proof of the approved loan, or its thumbnail, the caller must close the document content.
+// It is only served to the authenticated borrower of the loan and to the employees, since the picture shows the borrower's home,
+// userID is the user of the verified bearer token, zero when the request is anonymous
+func (s *Service) OpenPictureProof(ctx context.Context, loanID string, userID int64, thumbnail bool) (model.PictureProof, storage.Document, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. sanitize payload
+	if userID == 0 {
+		logging.FromContext(ctx).Info("user id is empty", "op", "OpenPictureProof")
+		return model.PictureProof{}, storage.Document{}, apperror.Unauthenticated.New()
+	}
+
+	// 2. get user by user id
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data is not found", "op", "OpenPictureProof", "user_id", userID)
+		return model.PictureProof{}, storage.Document{}, apperror.Unauthenticated.New().WithDetail("user_id", userID)
+	}
+
+	// 3. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data is not found", "op", "OpenPictureProof", "user_id", userID)
+		return model.PictureProof{}, storage.Document{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	// 4. check user is allowed to see the picture
+	isEmployee := user.UserType == constant.UserTypeFieldValidatorEmployee || user.UserType == constant.UserTypeFieldOfficerEmployee
+	if !isEmployee && loan.BorrowerID != userID {
+		logging.FromContext(ctx).Info("user is not allowed to see picture proof", "op", "OpenPictureProof", "user_id", userID)
+		return model.PictureProof{}, storage.Document{}, apperror.AccessDenied.New().WithDetail("user_id", userID)
+	}
+
+	// 5. open picture proof from document store
+	document, err := s.Helper.OpenPictureProof(ctx, loan, thumbnail)
+	if err != nil {
+		if errors.Is(err, apperror.ProofNotFound) || errors.Is(err, apperror.DocumentNotFound) {
+			logging.FromContext(ctx).Info("picture proof is not found", "op", "OpenPictureProof", "user_id", userID, "error", err)
+		} else {
+			logging.FromContext(ctx).Error("failed to open picture proof", "op", "OpenPictureProof", "user_id", userID, "error", err)
+		}
+		return model.PictureProof{}, storage.Document{}, err
+	}
+
+	return *loan.ApprovalInfo.Proof, document, nil
+}
+
+// Invest adds the lender investment to the approved loan, the loan is invested once the principal amount is fulfilled.
+// Every rejected investment is counted by its error code
+func (s *Service) Invest(ctx context.Context, loanID string, lenderID int64, amount float64) (model.Loan, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+	loan, err := s.invest(ctx, loanID, lenderID, amount)
+	if err != nil && s.Metrics != nil {
+		s.Metrics.RecordInvestRejection(string(apperror.From(err).Code))
+	}
+
+	return loan, err
+}
+
+func (s *Service) invest(ctx context.Context, loanID string, lenderID int64, amount float64) (model.Loan, error) {
+	// 1. sanitize payload
+	if lenderID == 0 {
+		logging.FromContext(ctx).Info("lender id is empty", "op", "Invest")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "lender_id")
+	}
+	if amount == 0 {
+		logging.FromContext(ctx).Info("invested amount is empty", "op", "Invest", "lender_id", lenderID)
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "invested_amount")
+	}
+
+	// 2. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data is not found", "op", "Invest", "lender_id", lenderID, "amount", amount)
+		return model.Loan{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	// 3. check loan status
+	if loan.Status != constant.LoanStatusApproved {
+		logging.FromContext(ctx).Info("loan status invalid", "op", "Invest", "lender_id", lenderID, "amount", amount, "current_status", constant.GetLoanStatusDesc(loan.Status))
+		return model.Loan{}, apperror.InvalidLoanStatus.New().WithDetail("loan_id", loanID).WithDetail("current_status", constant.GetLoanStatusDesc(loan.Status))
+	}
+
+	// 4. get lender by user id
+	lender := s.Helper.GetUserByUserID(ctx, lenderID)
+	if lender.UserID == 0 {
+		logging.FromContext(ctx).Info("lender data is not found", "op", "Invest", "lender_id", lenderID, "amount", amount)
+		return model.Loan{}, apperror.UserNotFound.New().WithDetail("user_id", lenderID)
+	}
+
+	// 5. check user type
+	if lender.UserType != constant.UserTypeLender {
+		logging.FromContext(ctx).Info("user type is not lender", "op", "Invest", "lender_id", lenderID, "amount", amount)
+		return model.Loan{}, apperror.UserTypeNotAllowed.New().WithDetail("user_id", lenderID).WithDetail("required_user_type", constant.UserTypeLender)
+	}
+
+	// 6. check invested amount
+	if amount > loan.GetRemainingRequiredAmount() {
+		logging.FromContext(ctx).Info("invested amount is bigger than remaining required amount", "op", "Invest", "lender_id", lenderID, "amount", amount, "remaining_amount", loan.GetRemainingRequiredAmount())
+		return model.Loan{}, apperror.InvestedAmountExceeded.New().WithDetail("loan_id", loanID).WithDetail("invested_amount", amount).WithDetail("remaining_amount", loan.GetRemainingRequiredAmount())
+	}
+
+	// 7. update loan lending data, on behalf of the lender
+	ctx = audit.WithActor(ctx, audit.UserActor(lenderID))
+	if loan.IsLenderInvested(lender.UserID) {
+		for i := 0; i < len(loan.Lending); i++ {
+			if loan.Lending[i].LenderID == lender.UserID {
+				loan.Lending[i].InvestedAmount += amount
+				loan.Lending[i].ReturnAmount = loan.Lending[i].CalculateLenderReturnAmount(loan.InterestRate)
+			}
+		}
+	} else {
+		lending := model.Lending{
+			LenderID:       lenderID,
+			InvestedAmount: amount,
+		}
+		lending.ReturnAmount = lending.CalculateLenderReturnAmount(loan.InterestRate)
+		loan.Lending = append(loan.Lending, lending)
+	}
+	loan.CollectedAmount += amount
+
+	// 8. check principal amount is fulfilled?
+	if loan.IsAmountFulfilled() {
+		// 8a. update loan status to invested
+		loan.Status = constant.LoanStatusInvested
+		loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
+
+		// 8b. generate lender agreement pdf
+		err := s.Helper.GenerateLenderAgreementPDF(ctx, &loan)
+		if err != nil {
+			logging.FromContext(ctx).Error("fail to generate lender agreement pdf", "op", "Invest", "lender_id", lenderID, "amount", amount, "error", err)
+			return model.Loan{}, apperror.AgreementGenerationFailed.Wrap(err).WithDetail("loan_id", loanID)
+		}
+	}
+
+	// 9. update loan lending
+	s.Helper.UpsertLoan(ctx, loan)
+
+	// 10. publish the investment, and the fulfilled loan
+	for _, lending := range loan.Lending {
+		if lending.LenderID == lenderID {
+			s.publish(ctx, event.New(event.LoanInvestmentAdded, lenderID, loan).WithLending(lending))
+		}
+	}
+	if loan.Status == constant.LoanStatusInvested {
+		s.publish(ctx, event.New(event.LoanInvested, lenderID, loan))
+		for _, lending := range loan.Lending {
+			s.publish(ctx, event.New(event.AgreementReady, lenderID, loan).WithLending(lending))
+		}
+	}
+
+	return loan, nil
+}
+
+// Disburse disburses the signed loan to the borrower by a field officer employee
+func (s *Service) Disburse(ctx context.Context, loanID string, disbursement model.Disbursement) (model.Loan, error) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. sanitize payload
+	if disbursement.FieldOfficerID == 0 {
+		logging.FromContext(ctx).Info("field officer id is empty", "op", "Disburse")
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "field_officer_id")
+	}
+	if disbursement.DisbursementDate.IsZero() {
+		logging.FromContext(ctx).Info("disbursement date is empty", "op", "Disburse", "employee_id", disbursement.FieldOfficerID)
+		return model.Loan{}, apperror.InvalidRequest.New().WithDetail("field", "disbursement_date")
+	}
+
+	// 2. get loan by loan id
+	loan := s.Helper.GetLoanByPublicID(ctx, loanID)
+	if loan.LoanID == 0 {
+		logging.FromContext(ctx).Info("loan data is not found", "op", "Disburse", "employee_id", disbursement.FieldOfficerID)
+		return model.Loan{}, apperror.LoanNotFound.New().WithDetail("loan_id", loanID)
+	}
+
+	// 3. check loan status
+	if loan.Status != constant.LoanStatusSigned {
+		logging.FromContext(ctx).Info("loan status invalid", "op", "Disburse", "employee_id", disbursement.FieldOfficerID, "current_status", constant.GetLoanStatusDesc(loan.Status))
+		return model.Loan{}, apperror.InvalidLoanStatus.New().WithDetail("loan_id", loanID).WithDetail("current_status", constant.GetLoanStatusDesc(loan.Status))
+	}
+
+	// 4. get field officer employee by user id
+	fieldOfficerEmployee := s.Helper.GetUserByUserID(ctx, disbursement.FieldOfficerID)
+	if fieldOfficerEmployee.UserID == 0 {
+		logging.FromContext(ctx).Info("field officer employee data is not found", "op", "Disburse", "employee_id", disbursement.FieldOfficerID)
+		return model.Loan{}, apperror.UserNotFound.New().WithDetail("user_id", disbursement.FieldOfficerID)
+	}
+
+	// 5. check user type
+	if fieldOfficerEmployee.UserType != constant.UserTypeFieldOfficerEmployee {
+		logging.FromContext(ctx).Info("user type is not field officer employee", "op", "Disburse", "employee_id", disbursement.FieldOfficerID)
+		return model.Loan{}, apperror.UserTypeNotAllowed.New().WithDetail("user_id", disbursement.FieldOfficerID).WithDetail("required_user_type", constant.UserTypeFieldOfficerEmployee)
+	}
+
+	// 6. check field visit
+	visit, err := s.checkVisit(ctx, "Disburse", loan, disbursement.Visit)
+	if err != nil {
+		return model.Loan{}, err
+	}
+
+	// 7. update loan disbursement and status, on behalf of the field officer employee
+	ctx = audit.WithActor(ctx, audit.UserActor(disbursement.FieldOfficerID))
+	loan.Status = constant.LoanStatusDisbursed
+	loan.StatusDesc = constant.GetLoanStatusDesc(loan.Status)
+	loan.DisbursementInfo.FieldOfficerID = disbursement.FieldOfficerID
+	loan.DisbursementInfo.DisbursementDate = disbursement.DisbursementDate
+	loan.DisbursementInfo.Visit = visit
+	s.Helper.UpsertLoan(ctx, loan)
+	s.publish(ctx, event.New(event.LoanDisbursed, disbursement.FieldOfficerID, loan))
+
+	return loan, nil
+}
@@ -0,0 +1,701 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/picture"
+	"amartha-test/storage"
+)
+
+const (
+	loanPublicID        = "5d0c6f8e-2a4b-4e1c-9b7d-3f8a1c6e0b24"
+	unknownLoanPublicID = "b8e1f4a2-6c3d-4f9e-8a1b-7d2c5e9f0a13"
+)
+
+func TestGetLoan(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name        string
+		loanID      string
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - loan id is empty",
+			loanID:      "",
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - loan not found",
+			loanID:      unknownLoanPublicID,
+			expectedErr: apperror.LoanNotFound,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, unknownLoanPublicID).Return(model.Loan{}).Once()
+			},
+		},
+		{
+			name:   "success",
+			loanID: loanPublicID,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, PublicID: loanPublicID}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			loan, err := svc.GetLoan(context.Background(), tt.loanID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.loanID, loan.PublicID)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWatchLoan(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	t.Run("error - loan not found", func(t *testing.T) {
+		mockHelper.On("GetLoanByPublicID", mock.Anything, unknownLoanPublicID).Return(model.Loan{}).Once()
+
+		_, _, err := svc.WatchLoan(context.Background(), unknownLoanPublicID)
+
+		assert.ErrorIs(t, err, apperror.LoanNotFound)
+	})
+
+	t.Run("success - only events of the loan until ctx is done", func(t *testing.T) {
+		mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, PublicID: loanPublicID, Status: constant.LoanStatusApproved}).Once()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		loan, events, err := svc.WatchLoan(ctx, loanPublicID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), loan.LoanID)
+
+		svc.Events.Publish(context.Background(), event.New(event.LoanInvestmentAdded, 2, model.Loan{LoanID: 2, PublicID: unknownLoanPublicID}))
+		invested := event.New(event.LoanInvestmentAdded, 2, model.Loan{LoanID: 1, PublicID: loanPublicID, CollectedAmount: 400})
+		svc.Events.Publish(context.Background(), invested)
+		assert.Equal(t, invested.ID, (<-events).ID)
+
+		cancel()
+		for range events {
+		}
+		// publishing after the watcher is gone is harmless
+		svc.Events.Publish(context.Background(), invested)
+	})
+
+	t.Run("success - change between subscribe and read is not lost", func(t *testing.T) {
+		invested := event.New(event.LoanInvested, 2, model.Loan{LoanID: 1, PublicID: loanPublicID, Status: constant.LoanStatusInvested})
+		mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, PublicID: loanPublicID, Status: constant.LoanStatusApproved}).Run(func(args mock.Arguments) {
+			svc.Events.Publish(context.Background(), invested)
+		}).Once()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		loan, events, err := svc.WatchLoan(ctx, loanPublicID)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.LoanStatusApproved, loan.Status)
+		assert.Equal(t, invested.ID, (<-events).ID)
+	})
+
+	t.Run("success - slow watcher loses its oldest events", func(t *testing.T) {
+		mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, PublicID: loanPublicID, Status: constant.LoanStatusApproved}).Once()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, events, err := svc.WatchLoan(ctx, loanPublicID)
+		assert.NoError(t, err)
+
+		for i := 1; i <= WatchBufferSize+5; i++ {
+			svc.Events.Publish(context.Background(), event.New(event.LoanInvestmentAdded, 2, model.Loan{LoanID: 1, PublicID: loanPublicID, CollectedAmount: float64(i)}))
+		}
+
+		assert.Len(t, events, WatchBufferSize)
+		assert.Equal(t, float64(6), (<-events).Loan.CollectedAmount)
+		var last event.Event
+		for len(events) > 0 {
+			last = <-events
+		}
+		assert.Equal(t, float64(WatchBufferSize+5), last.Loan.CollectedAmount)
+	})
+
+	mockHelper.AssertExpectations(t)
+}
+
+func TestSubmitLoan(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name            string
+		borrowerID      int64
+		principalAmount float64
+		interestRate    float64
+		expectedErr     error
+		mocks           func()
+	}{
+		{
+			name:            "error - borrower id is empty",
+			principalAmount: 1000,
+			interestRate:    0.1,
+			expectedErr:     apperror.InvalidRequest,
+			mocks:           func() {},
+		},
+		{
+			name:         "error - principal amount is empty",
+			borrowerID:   1,
+			interestRate: 0.1,
+			expectedErr:  apperror.InvalidRequest,
+			mocks:        func() {},
+		},
+		{
+			name:            "error - interest rate is invalid",
+			borrowerID:      1,
+			principalAmount: 1000,
+			interestRate:    1.5,
+			expectedErr:     apperror.InvalidRequest,
+			mocks:           func() {},
+		},
+		{
+			name:            "error - borrower not found",
+			borrowerID:      1,
+			principalAmount: 1000,
+			interestRate:    0.1,
+			expectedErr:     apperror.UserNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:            "error - user is not borrower",
+			borrowerID:      2,
+			principalAmount: 1000,
+			interestRate:    0.1,
+			expectedErr:     apperror.UserTypeNotAllowed,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+			},
+		},
+		{
+			name:            "success",
+			borrowerID:      1,
+			principalAmount: 1000,
+			interestRate:    0.1,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GenerateIncrementalLoanID", mock.Anything).Return(int64(7)).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.MatchedBy(func(loan model.Loan) bool {
+					return loan.LoanID == 7 && loan.PublicID != "" && loan.Status == constant.LoanStatusProposed
+				})).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			loan, err := svc.SubmitLoan(context.Background(), tt.borrowerID, tt.principalAmount, tt.interestRate)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), loan.LoanID)
+				assert.NoError(t, uuid.Validate(loan.PublicID))
+				assert.Equal(t, tt.borrowerID, loan.BorrowerID)
+				assert.Equal(t, constant.GetLoanStatusDesc(constant.LoanStatusProposed), loan.StatusDesc)
+				assert.False(t, loan.CreatedAt.IsZero())
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+// newPictureProof returns a png picture big enough to prove a visit
+func newPictureProof(t *testing.T) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 400, 300)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestApproveLoan(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	approvalDate := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	pictureProof := newPictureProof(t)
+	storedProof := model.PictureProof{DocumentKey: "document", ThumbnailKey: "thumbnail", ContentType: "image/png", Width: 400, Height: 300}
+	svc.Visits = VisitPolicy{MaxDistanceMeters: 500, OutOfRange: constant.VisitOutOfRangeReject}
+	home := model.GeoPoint{Latitude: -6.2607, Longitude: 106.8137}
+	farVisit := &model.Visit{Location: model.GeoPoint{Latitude: -6.2507, Longitude: 106.8137}, VisitedAt: approvalDate}
+
+	tests := []struct {
+		name         string
+		approvalInfo model.ApprovalInfo
+		pictureProof []byte
+		expectedErr  error
+		mocks        func()
+	}{
+		{
+			name:         "error - picture proof is empty",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4},
+			expectedErr:  apperror.InvalidRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - field validator employee id is empty",
+			pictureProof: pictureProof,
+			expectedErr:  apperror.InvalidRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - loan not found",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4},
+			pictureProof: pictureProof,
+			expectedErr:  apperror.LoanNotFound,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
+			},
+		},
+		{
+			name:         "error - loan is not proposed",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4},
+			pictureProof: pictureProof,
+			expectedErr:  apperror.InvalidLoanStatus,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusApproved}).Once()
+			},
+		},
+		{
+			name:         "error - user is not field validator employee",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 5},
+			pictureProof: pictureProof,
+			expectedErr:  apperror.UserTypeNotAllowed,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+			},
+		},
+		{
+			name:         "error - visit is out of range",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4, Visit: farVisit},
+			pictureProof: pictureProof,
+			expectedErr:  apperror.VisitOutOfRange,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Location: &home}).Once()
+			},
+		},
+		{
+			name:         "error - picture proof is not a picture",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4},
+			pictureProof: []byte("image"),
+			expectedErr:  apperror.InvalidPictureProof,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+			},
+		},
+		{
+			name:         "error - picture proof can not be stored",
+			approvalInfo: model.ApprovalInfo{FieldValidatorEmployeeID: 4},
+			pictureProof: pictureProof,
+			expectedErr:  apperror.Internal,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("PutPictureProof", mock.Anything, mock.Anything).Return(model.PictureProof{}, errors.New("disk full")).Once()
+			},
+		},
+		{
+			name:         "success",
+			approvalInfo: model.ApprovalInfo{PictureProof: "aW1hZ2U=", FieldValidatorEmployeeID: 4, ApprovalDate: approvalDate},
+			pictureProof: pictureProof,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("PutPictureProof", mock.Anything, mock.MatchedBy(func(proof picture.Picture) bool {
+					return proof.ContentType == "image/png" && proof.Width == 400 && len(proof.Thumbnail) > 0
+				})).Return(storedProof, nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			loan, err := svc.ApproveLoan(context.Background(), loanPublicID, tt.approvalInfo, tt.pictureProof)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.LoanStatusApproved, loan.Status)
+				assert.Equal(t, approvalDate, loan.ApprovalInfo.ApprovalDate)
+				assert.Equal(t, &storedProof, loan.ApprovalInfo.Proof)
+				assert.Empty(t, loan.ApprovalInfo.PictureProof, "the picture is never kept on the loan")
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOpenPictureProof(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	proof := model.PictureProof{DocumentKey: "document", ThumbnailKey: "thumbnail", ContentType: "image/jpeg"}
+	approvedLoan := model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusApproved, ApprovalInfo: &model.ApprovalInfo{FieldValidatorEmployeeID: 4, Proof: &proof}}
+
+	tests := []struct {
+		name        string
+		userID      int64
+		thumbnail   bool
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - user id is empty",
+			expectedErr: apperror.Unauthenticated,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - user not found",
+			userID:      9,
+			expectedErr: apperror.Unauthenticated,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:        "error - loan not found",
+			userID:      4,
+			expectedErr: apperror.LoanNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{}).Once()
+			},
+		},
+		{
+			name:        "error - lender is not allowed",
+			userID:      2,
+			expectedErr: apperror.AccessDenied,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(model.User{UserID: 2, UserType: constant.UserTypeLender}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+			},
+		},
+		{
+			name:        "error - other borrower is not allowed",
+			userID:      6,
+			expectedErr: apperror.AccessDenied,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(6)).Return(model.User{UserID: 6, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+			},
+		},
+		{
+			name:        "error - proposed loan has no proof",
+			userID:      4,
+			expectedErr: apperror.ProofNotFound,
+			mocks: func() {
+				proposedLoan := model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusProposed}
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(4)).Return(model.User{UserID: 4, UserType: constant.UserTypeFieldValidatorEmployee}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(proposedLoan).Once()
+				mockHelper.On("OpenPictureProof", mock.Anything, proposedLoan, false).Return(storage.Document{}, apperror.ProofNotFound.New()).Once()
+			},
+		},
+		{
+			name:   "success - borrower of the loan",
+			userID: 1,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+				mockHelper.On("OpenPictureProof", mock.Anything, approvedLoan, false).Return(storage.Document{Key: "document"}, nil).Once()
+			},
+		},
+		{
+			name:      "success - thumbnail for field officer employee",
+			userID:    5,
+			thumbnail: true,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan).Once()
+				mockHelper.On("OpenPictureProof", mock.Anything, approvedLoan, true).Return(storage.Document{Key: "thumbnail"}, nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			actualProof, document, err := svc.OpenPictureProof(context.Background(), loanPublicID, tt.userID, tt.thumbnail)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, proof, actualProof)
+				assert.NotEmpty(t, document.Key)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInvest(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	lender := model.User{UserID: 2, UserType: constant.UserTypeLender}
+	approvedLoan := func(lending ...model.Lending) model.Loan {
+		loan := model.Loan{LoanID: 1, PrincipalAmount: 1000, InterestRate: 0.1, Status: constant.LoanStatusApproved, Lending: lending}
+		for _, v := range lending {
+			loan.CollectedAmount += v.InvestedAmount
+		}
+		return loan
+	}
+
+	tests := []struct {
+		name             string
+		lenderID         int64
+		amount           float64
+		expectedErr      error
+		expectedStatus   int
+		expectedCollect  float64
+		expectedLendings int
+		expectedEvents   []event.Type
+		mocks            func()
+	}{
+		{
+			name:        "error - lender id is empty",
+			amount:      100,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - amount is empty",
+			lenderID:    2,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - loan is not approved",
+			lenderID:    2,
+			amount:      100,
+			expectedErr: apperror.InvalidLoanStatus,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+			},
+		},
+		{
+			name:        "error - user is not lender",
+			lenderID:    1,
+			amount:      100,
+			expectedErr: apperror.UserTypeNotAllowed,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan()).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+			},
+		},
+		{
+			name:        "error - amount exceeds remaining amount",
+			lenderID:    2,
+			amount:      600,
+			expectedErr: apperror.InvestedAmountExceeded,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan(model.Lending{LenderID: 3, InvestedAmount: 500})).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+			},
+		},
+		{
+			name:        "error - generate lender agreement failed",
+			lenderID:    2,
+			amount:      1000,
+			expectedErr: apperror.AgreementGenerationFailed,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan()).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything, mock.Anything).Return(errors.New("render failed")).Once()
+			},
+		},
+		{
+			name:             "success - partially invested",
+			lenderID:         2,
+			amount:           400,
+			expectedStatus:   constant.LoanStatusApproved,
+			expectedCollect:  400,
+			expectedLendings: 1,
+			expectedEvents:   []event.Type{event.LoanInvestmentAdded},
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan()).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+		{
+			name:             "success - same lender invests again and fulfils the loan",
+			lenderID:         2,
+			amount:           600,
+			expectedStatus:   constant.LoanStatusInvested,
+			expectedCollect:  1000,
+			expectedLendings: 1,
+			expectedEvents:   []event.Type{event.LoanInvestmentAdded, event.LoanInvested, event.AgreementReady},
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(approvedLoan(model.Lending{LenderID: 2, InvestedAmount: 400})).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(2)).Return(lender).Once()
+				mockHelper.On("GenerateLenderAgreementPDF", mock.Anything, mock.Anything).Return(nil).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+			var events []event.Type
+			unsubscribe := svc.Events.Subscribe(func(ctx context.Context, e event.Event) {
+				events = append(events, e.Type)
+			})
+			defer unsubscribe()
+
+			// main func
+			loan, err := svc.Invest(context.Background(), loanPublicID, tt.lenderID, tt.amount)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, loan.Status)
+				assert.Equal(t, tt.expectedCollect, loan.CollectedAmount)
+				assert.Len(t, loan.Lending, tt.expectedLendings)
+				assert.Equal(t, loan.Lending[0].CalculateLenderReturnAmount(loan.InterestRate), loan.Lending[0].ReturnAmount)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisburse(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	svc.Visits = VisitPolicy{MaxDistanceMeters: 500, OutOfRange: constant.VisitOutOfRangeFlag}
+	disbursementDate := time.Date(2026, time.October, 2, 0, 0, 0, 0, time.UTC)
+	home := model.GeoPoint{Latitude: -6.2607, Longitude: 106.8137}
+	visit := &model.Visit{Location: model.GeoPoint{Latitude: -6.2507, Longitude: 106.8137}, VisitedAt: disbursementDate}
+
+	tests := []struct {
+		name                 string
+		disbursement         model.Disbursement
+		expectedErr          error
+		expectedIsOutOfRange bool
+		mocks                func()
+	}{
+		{
+			name:         "error - field officer id is empty",
+			disbursement: model.Disbursement{DisbursementDate: disbursementDate},
+			expectedErr:  apperror.InvalidRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - disbursement date is empty",
+			disbursement: model.Disbursement{FieldOfficerID: 5},
+			expectedErr:  apperror.InvalidRequest,
+			mocks:        func() {},
+		},
+		{
+			name:         "error - loan is not signed",
+			disbursement: model.Disbursement{FieldOfficerID: 5, DisbursementDate: disbursementDate},
+			expectedErr:  apperror.InvalidLoanStatus,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusInvested}).Once()
+			},
+		},
+		{
+			name:         "error - field officer not found",
+			disbursement: model.Disbursement{FieldOfficerID: 5, DisbursementDate: disbursementDate},
+			expectedErr:  apperror.UserNotFound,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:                 "success - missing visit flagged",
+			disbursement:         model.Disbursement{FieldOfficerID: 5, DisbursementDate: disbursementDate},
+			expectedIsOutOfRange: true,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+		{
+			name:                 "success - visit flagged out of range",
+			disbursement:         model.Disbursement{FieldOfficerID: 5, DisbursementDate: disbursementDate, Visit: visit},
+			expectedIsOutOfRange: true,
+			mocks: func() {
+				mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, BorrowerID: 1, Status: constant.LoanStatusSigned}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, Location: &home}).Once()
+				mockHelper.On("UpsertLoan", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			loan, err := svc.Disburse(context.Background(), loanPublicID, tt.disbursement)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.LoanStatusDisbursed, loan.Status)
+				assert.Equal(t, int64(5), loan.DisbursementInfo.FieldOfficerID)
+				assert.Equal(t, disbursementDate, loan.DisbursementInfo.DisbursementDate)
+				assert.Equal(t, tt.expectedIsOutOfRange, loan.IsVisitOutOfRange())
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
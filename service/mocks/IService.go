@@ -0,0 +1,635 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	event "amartha-test/event"
+	model "amartha-test/model"
+	storage "amartha-test/storage"
+)
+
+// IService is an autogenerated mock type for the IService type
+type IService struct {
+	mock.Mock
+}
+
+// ApproveLoan provides a mock function with given fields: ctx, loanID, approvalInfo, pictureProof
+func (_m *IService) ApproveLoan(ctx context.Context, loanID string, approvalInfo model.ApprovalInfo, pictureProof []byte) (model.Loan, error) {
+	ret := _m.Called(ctx, loanID, approvalInfo, pictureProof)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveLoan")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.ApprovalInfo, []byte) (model.Loan, error)); ok {
+		return rf(ctx, loanID, approvalInfo, pictureProof)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.ApprovalInfo, []byte) model.Loan); ok {
+		r0 = rf(ctx, loanID, approvalInfo, pictureProof)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.ApprovalInfo, []byte) error); ok {
+		r1 = rf(ctx, loanID, approvalInfo, pictureProof)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disburse provides a mock function with given fields: ctx, loanID, disbursement
+func (_m *IService) Disburse(ctx context.Context, loanID string, disbursement model.Disbursement) (model.Loan, error) {
+	ret := _m.Called(ctx, loanID, disbursement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disburse")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Disbursement) (model.Loan, error)); ok {
+		return rf(ctx, loanID, disbursement)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Disbursement) model.Loan); ok {
+		r0 = rf(ctx, loanID, disbursement)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Disbursement) error); ok {
+		r1 = rf(ctx, loanID, disbursement)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoan provides a mock function with given fields: ctx, loanID
+func (_m *IService) GetLoan(ctx context.Context, loanID string) (model.Loan, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoan")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Loan, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Loan); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStats provides a mock function with given fields: ctx
+func (_m *IService) GetStats(ctx context.Context) model.Stats {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 model.Stats
+	if rf, ok := ret.Get(0).(func(context.Context) model.Stats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(model.Stats)
+	}
+
+	return r0
+}
+
+// GetUser provides a mock function with given fields: ctx, userID
+func (_m *IService) GetUser(ctx context.Context, userID int64) (model.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (model.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invest provides a mock function with given fields: ctx, loanID, lenderID, amount
+func (_m *IService) Invest(ctx context.Context, loanID string, lenderID int64, amount float64) (model.Loan, error) {
+	ret := _m.Called(ctx, loanID, lenderID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invest")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, float64) (model.Loan, error)); ok {
+		return rf(ctx, loanID, lenderID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, float64) model.Loan); ok {
+		r0 = rf(ctx, loanID, lenderID, amount)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, float64) error); ok {
+		r1 = rf(ctx, loanID, lenderID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAgreements provides a mock function with given fields: ctx, filter
+func (_m *IService) ListAgreements(ctx context.Context, filter model.AgreementFilter) []model.Aggrement {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAgreements")
+	}
+
+	var r0 []model.Aggrement
+	if rf, ok := ret.Get(0).(func(context.Context, model.AgreementFilter) []model.Aggrement); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Aggrement)
+		}
+	}
+
+	return r0
+}
+
+// ListAuditEntries provides a mock function with given fields: ctx, filter
+func (_m *IService) ListAuditEntries(ctx context.Context, filter model.AuditFilter) []model.AuditEntry {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditEntries")
+	}
+
+	var r0 []model.AuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuditFilter) []model.AuditEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEntry)
+		}
+	}
+
+	return r0
+}
+
+// ListDeadLetters provides a mock function with given fields: ctx
+func (_m *IService) ListDeadLetters(ctx context.Context) []model.WebhookDelivery {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeadLetters")
+	}
+
+	var r0 []model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context) []model.WebhookDelivery); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebhookDelivery)
+		}
+	}
+
+	return r0
+}
+
+// ListEmployeeTasks provides a mock function with given fields: ctx, userID, filter
+func (_m *IService) ListEmployeeTasks(ctx context.Context, userID int64, filter model.TaskFilter) ([]model.Task, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEmployeeTasks")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.TaskFilter) ([]model.Task, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.TaskFilter) []model.Task); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, model.TaskFilter) error); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoans provides a mock function with given fields: ctx, filter
+func (_m *IService) ListLoans(ctx context.Context, filter model.LoanFilter) []model.Loan {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoans")
+	}
+
+	var r0 []model.Loan
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoanFilter) []model.Loan); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Loan)
+		}
+	}
+
+	return r0
+}
+
+// ListNotifications provides a mock function with given fields: ctx, userID
+func (_m *IService) ListNotifications(ctx context.Context, userID int64) ([]model.Notification, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNotifications")
+	}
+
+	var r0 []model.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]model.Notification, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.Notification); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSubscriptions provides a mock function with given fields: ctx
+func (_m *IService) ListSubscriptions(ctx context.Context) []model.WebhookSubscription {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubscriptions")
+	}
+
+	var r0 []model.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context) []model.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebhookSubscription)
+		}
+	}
+
+	return r0
+}
+
+// ListUsers provides a mock function with given fields: ctx, filter
+func (_m *IService) ListUsers(ctx context.Context, filter model.UserFilter) []model.User {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []model.User
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserFilter) []model.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	return r0
+}
+
+// OpenAgreement provides a mock function with given fields: ctx, agreementID, userID
+func (_m *IService) OpenAgreement(ctx context.Context, agreementID string, userID int64) (model.Aggrement, storage.Document, error) {
+	ret := _m.Called(ctx, agreementID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenAgreement")
+	}
+
+	var r0 model.Aggrement
+	var r1 storage.Document
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (model.Aggrement, storage.Document, error)); ok {
+		return rf(ctx, agreementID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) model.Aggrement); ok {
+		r0 = rf(ctx, agreementID, userID)
+	} else {
+		r0 = ret.Get(0).(model.Aggrement)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) storage.Document); ok {
+		r1 = rf(ctx, agreementID, userID)
+	} else {
+		r1 = ret.Get(1).(storage.Document)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, agreementID, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// OpenPictureProof provides a mock function with given fields: ctx, loanID, userID, thumbnail
+func (_m *IService) OpenPictureProof(ctx context.Context, loanID string, userID int64, thumbnail bool) (model.PictureProof, storage.Document, error) {
+	ret := _m.Called(ctx, loanID, userID, thumbnail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenPictureProof")
+	}
+
+	var r0 model.PictureProof
+	var r1 storage.Document
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool) (model.PictureProof, storage.Document, error)); ok {
+		return rf(ctx, loanID, userID, thumbnail)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool) model.PictureProof); ok {
+		r0 = rf(ctx, loanID, userID, thumbnail)
+	} else {
+		r0 = ret.Get(0).(model.PictureProof)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool) storage.Document); ok {
+		r1 = rf(ctx, loanID, userID, thumbnail)
+	} else {
+		r1 = ret.Get(1).(storage.Document)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, bool) error); ok {
+		r2 = rf(ctx, loanID, userID, thumbnail)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RetryDeadLetter provides a mock function with given fields: ctx, deliveryID
+func (_m *IService) RetryDeadLetter(ctx context.Context, deliveryID int64) (model.WebhookDelivery, error) {
+	ret := _m.Called(ctx, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryDeadLetter")
+	}
+
+	var r0 model.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (model.WebhookDelivery, error)); ok {
+		return rf(ctx, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.WebhookDelivery); ok {
+		r0 = rf(ctx, deliveryID)
+	} else {
+		r0 = ret.Get(0).(model.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SignAgreement provides a mock function with given fields: ctx, agreementID, loanID, userID
+func (_m *IService) SignAgreement(ctx context.Context, agreementID string, loanID string, userID int64) (model.Loan, error) {
+	ret := _m.Called(ctx, agreementID, loanID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignAgreement")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (model.Loan, error)); ok {
+		return rf(ctx, agreementID, loanID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) model.Loan); ok {
+		r0 = rf(ctx, agreementID, loanID, userID)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, agreementID, loanID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmitLoan provides a mock function with given fields: ctx, borrowerID, principalAmount, interestRate
+func (_m *IService) SubmitLoan(ctx context.Context, borrowerID int64, principalAmount float64, interestRate float64) (model.Loan, error) {
+	ret := _m.Called(ctx, borrowerID, principalAmount, interestRate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitLoan")
+	}
+
+	var r0 model.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, float64) (model.Loan, error)); ok {
+		return rf(ctx, borrowerID, principalAmount, interestRate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, float64) model.Loan); ok {
+		r0 = rf(ctx, borrowerID, principalAmount, interestRate)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, float64, float64) error); ok {
+		r1 = rf(ctx, borrowerID, principalAmount, interestRate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: ctx, userID, url, eventTypes, secret
+func (_m *IService) Subscribe(ctx context.Context, userID int64, url string, eventTypes []string, secret string) (model.WebhookSubscription, error) {
+	ret := _m.Called(ctx, userID, url, eventTypes, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 model.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []string, string) (model.WebhookSubscription, error)); ok {
+		return rf(ctx, userID, url, eventTypes, secret)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []string, string) model.WebhookSubscription); ok {
+		r0 = rf(ctx, userID, url, eventTypes, secret)
+	} else {
+		r0 = ret.Get(0).(model.WebhookSubscription)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, []string, string) error); ok {
+		r1 = rf(ctx, userID, url, eventTypes, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unsubscribe provides a mock function with given fields: ctx, subscriptionID
+func (_m *IService) Unsubscribe(ctx context.Context, subscriptionID int64) (model.WebhookSubscription, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unsubscribe")
+	}
+
+	var r0 model.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (model.WebhookSubscription, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.WebhookSubscription); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Get(0).(model.WebhookSubscription)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyAuditLog provides a mock function with given fields: ctx
+func (_m *IService) VerifyAuditLog(ctx context.Context) model.AuditVerification {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAuditLog")
+	}
+
+	var r0 model.AuditVerification
+	if rf, ok := ret.Get(0).(func(context.Context) model.AuditVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(model.AuditVerification)
+	}
+
+	return r0
+}
+
+// WatchLoan provides a mock function with given fields: ctx, loanID
+func (_m *IService) WatchLoan(ctx context.Context, loanID string) (model.Loan, <-chan event.Event, error) {
+	ret := _m.Called(ctx, loanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchLoan")
+	}
+
+	var r0 model.Loan
+	var r1 <-chan event.Event
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Loan, <-chan event.Event, error)); ok {
+		return rf(ctx, loanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Loan); ok {
+		r0 = rf(ctx, loanID)
+	} else {
+		r0 = ret.Get(0).(model.Loan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) <-chan event.Event); ok {
+		r1 = rf(ctx, loanID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan event.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, loanID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewIService creates a new instance of IService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IService {
+	mock := &IService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/apperror"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// ListNotifications returns every notification in the in-app inbox of the user
+func (s *Service) ListNotifications(ctx context.Context, userID int64) ([]model.Notification, error) {
+	// 1. get user by user id
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data is not found", "op", "ListNotifications", "user_id", userID)
+		return nil, apperror.UserNotFound.New().WithDetail("user_id", userID)
+	}
+
+	// 2. get notifications by user id
+	return s.Helper.GetNotificationsByUserID(ctx, userID), nil
+}
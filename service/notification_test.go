@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestListNotifications(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name        string
+		userID      int64
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - user not found",
+			userID:      9,
+			expectedErr: apperror.UserNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:   "success",
+			userID: 1,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1}).Once()
+				mockHelper.On("GetNotificationsByUserID", mock.Anything, int64(1)).Return([]model.Notification{{NotificationID: 1, UserID: 1}}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			notifications, err := svc.ListNotifications(context.Background(), tt.userID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []model.Notification{{NotificationID: 1, UserID: 1}}, notifications)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/model"
+)
+
+// GetStats counts the loans per status, the principal funded by lenders and the agreements not signed yet
+func (s *Service) GetStats(ctx context.Context) model.Stats {
+	stats := model.Stats{
+		LoanCountByStatus: make(map[int]int),
+	}
+
+	for _, loan := range s.Helper.GetLoans(ctx) {
+		stats.LoanCountByStatus[loan.Status]++
+		stats.PrincipalFunded += loan.CollectedAmount
+	}
+	for _, agreement := range s.Helper.GetAgreements(ctx) {
+		if !agreement.IsSigned {
+			stats.AgreementsPendingSignature++
+		}
+	}
+
+	return stats
+}
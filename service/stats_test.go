@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+type fakeMetrics struct {
+	investRejections []string
+}
+
+func (f *fakeMetrics) ObserveRequest(method string, route string, statusCode int, duration time.Duration) {
+}
+
+func (f *fakeMetrics) RecordInvestRejection(reason string) {
+	f.investRejections = append(f.investRejections, reason)
+}
+
+func TestGetStats(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	mockHelper.On("GetLoans", mock.Anything).Return([]model.Loan{
+		{LoanID: 1, Status: constant.LoanStatusProposed},
+		{LoanID: 2, Status: constant.LoanStatusApproved, CollectedAmount: 400},
+		{LoanID: 3, Status: constant.LoanStatusInvested, CollectedAmount: 1000},
+		{LoanID: 4, Status: constant.LoanStatusInvested, CollectedAmount: 2000},
+	}).Once()
+	mockHelper.On("GetAgreements", mock.Anything).Return([]model.Aggrement{
+		{AggrementID: 1, LoanID: 3, IsSigned: true},
+		{AggrementID: 2, LoanID: 3},
+		{AggrementID: 3, LoanID: 4},
+	}).Once()
+
+	// main func
+	stats := svc.GetStats(context.Background())
+
+	assert.Equal(t, model.Stats{
+		LoanCountByStatus:          map[int]int{constant.LoanStatusProposed: 1, constant.LoanStatusApproved: 1, constant.LoanStatusInvested: 2},
+		PrincipalFunded:            3400,
+		AgreementsPendingSignature: 2,
+	}, stats)
+	mockHelper.AssertExpectations(t)
+}
+
+func TestInvestRecordsRejection(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	metrics := &fakeMetrics{}
+	svc := NewService(mockHelper)
+	svc.Metrics = metrics
+
+	mockHelper.On("GetLoanByPublicID", mock.Anything, loanPublicID).Return(model.Loan{LoanID: 1, Status: constant.LoanStatusProposed}).Once()
+
+	// main func
+	_, errEmpty := svc.Invest(context.Background(), loanPublicID, 0, 100)
+	_, errStatus := svc.Invest(context.Background(), loanPublicID, 2, 100)
+
+	assert.Error(t, errEmpty)
+	assert.Error(t, errStatus)
+	assert.Equal(t, []string{"invalid_request", "invalid_loan_status"}, metrics.investRejections)
+	mockHelper.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// ListEmployeeTasks returns the field visit tasks assigned to the employee, matching the filter
+func (s *Service) ListEmployeeTasks(ctx context.Context, userID int64, filter model.TaskFilter) ([]model.Task, error) {
+	// 1. get employee by user id
+	employee := s.Helper.GetUserByUserID(ctx, userID)
+	if employee.UserID == 0 {
+		logging.FromContext(ctx).Info("employee data is not found", "op", "ListEmployeeTasks", "user_id", userID)
+		return nil, apperror.UserNotFound.New().WithDetail("user_id", userID)
+	}
+
+	// 2. check user type
+	if employee.UserType != constant.UserTypeFieldValidatorEmployee && employee.UserType != constant.UserTypeFieldOfficerEmployee {
+		logging.FromContext(ctx).Info("user type is not employee", "op", "ListEmployeeTasks", "user_id", userID)
+		return nil, apperror.UserTypeNotAllowed.New().WithDetail("user_id", userID)
+	}
+
+	// 3. get tasks by assignee
+	filter.AssigneeID = userID
+	return s.Helper.GetTasksByFilter(ctx, filter), nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestListEmployeeTasks(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name        string
+		userID      int64
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - user not found",
+			userID:      9,
+			expectedErr: apperror.UserNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:        "error - user is not employee",
+			userID:      1,
+			expectedErr: apperror.UserTypeNotAllowed,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserType: constant.UserTypeBorrower}).Once()
+			},
+		},
+		{
+			name:   "success",
+			userID: 5,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}).Once()
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{AssigneeID: 5, Status: constant.TaskStatusOpen}).Return([]model.Task{{TaskID: 1, AssigneeID: 5}}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			tasks, err := svc.ListEmployeeTasks(context.Background(), tt.userID, model.TaskFilter{Status: constant.TaskStatusOpen})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []model.Task{{TaskID: 1, AssigneeID: 5}}, tasks)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"amartha-test/apperror"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// ListUsers returns every user matching the filter
+func (s *Service) ListUsers(ctx context.Context, filter model.UserFilter) []model.User {
+	return s.Helper.GetUsersByFilter(ctx, filter)
+}
+
+// GetUser returns the user of the user id
+func (s *Service) GetUser(ctx context.Context, userID int64) (model.User, error) {
+	// 1. sanitize payload
+	if userID == 0 {
+		logging.FromContext(ctx).Info("user id is zero", "op", "GetUser")
+		return model.User{}, apperror.InvalidRequest.New().WithDetail("field", "user_id")
+	}
+
+	// 2. get user by user id
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data is not found", "op", "GetUser", "user_id", userID)
+		return model.User{}, apperror.UserNotFound.New().WithDetail("user_id", userID)
+	}
+
+	return user, nil
+}
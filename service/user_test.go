@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestListUsers(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	filter := model.UserFilter{UserType: constant.UserTypeLender}
+	mockHelper.On("GetUsersByFilter", mock.Anything, filter).Return([]model.User{{UserID: 2, UserType: constant.UserTypeLender}}).Once()
+
+	users := svc.ListUsers(context.Background(), filter)
+
+	assert.Equal(t, []model.User{{UserID: 2, UserType: constant.UserTypeLender}}, users)
+	mockHelper.AssertExpectations(t)
+}
+
+func TestGetUser(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name        string
+		userID      int64
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - user id is zero",
+			userID:      0,
+			expectedErr: apperror.InvalidRequest,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - user not found",
+			userID:      9,
+			expectedErr: apperror.UserNotFound,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, mock.Anything).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:   "success",
+			userID: 1,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(model.User{UserID: 1, UserName: "Septian"}).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			user, err := svc.GetUser(context.Background(), tt.userID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.userID, user.UserID)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
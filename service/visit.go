@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// visitClockSkew is how far in the future a visit may be timestamped, the clock of a field device drifts
+const visitClockSkew = 5 * time.Minute
+
+// VisitPolicy is how the field visits of the approvals and disbursements are checked. The zero value
+// accepts a missing visit flagged, never measures the distance and never checks the age
+type VisitPolicy struct {
+	// Required rejects the approvals and disbursements without a visit
+	Required bool
+	// MaxDistanceMeters is how far from the borrower address a visit may be, zero leaves the distance unchecked
+	MaxDistanceMeters float64
+	// MaxAge is how long before the request a visit may be timestamped, zero leaves the age unchecked
+	MaxAge time.Duration
+	// OutOfRange is constant.VisitOutOfRangeFlag or constant.VisitOutOfRangeReject, anything else flags
+	OutOfRange string
+}
+
+// checkVisit validates the field visit and measures its distance from the borrower address, returning the visit
+// to store on the loan. An out of range visit is flagged or rejected as the policy says, a missing visit is
+// flagged as out of range too since its distance is unknown, a visit is kept unmeasured when the borrower has no
+// registered location
+func (s *Service) checkVisit(ctx context.Context, op string, loan model.Loan, visit *model.Visit) (*model.Visit, error) {
+	// 1. check visit is given
+	if visit == nil {
+		if s.Visits.Required {
+			logging.FromContext(ctx).Info("visit is empty", "op", op)
+			return nil, apperror.InvalidRequest.New().WithDetail("field", "visit")
+		}
+		logging.FromContext(ctx).Warn("visit is empty, flagged for review", "op", op)
+		return &model.Visit{IsMissing: true, IsOutOfRange: true}, nil
+	}
+
+	// 2. sanitize visit
+	err := visit.Location.Validate()
+	if err != nil {
+		logging.FromContext(ctx).Info("visit location is invalid", "op", op, "error", err)
+		return nil, apperror.InvalidRequest.Wrap(err).WithDetail("field", "visit.location").WithDetail("reason", err.Error())
+	}
+	if visit.VisitedAt.IsZero() || visit.VisitedAt.After(time.Now().Add(visitClockSkew)) {
+		logging.FromContext(ctx).Info("visit time is invalid", "op", op, "visited_at", visit.VisitedAt)
+		return nil, apperror.InvalidRequest.New().WithDetail("field", "visit.visited_at")
+	}
+	if s.Visits.MaxAge > 0 && visit.VisitedAt.Before(time.Now().Add(-s.Visits.MaxAge)) {
+		logging.FromContext(ctx).Info("visit is too old", "op", op, "visited_at", visit.VisitedAt, "max_age", s.Visits.MaxAge.String())
+		return nil, apperror.InvalidRequest.New().WithDetail("field", "visit.visited_at").WithDetail("max_age", s.Visits.MaxAge.String())
+	}
+	checked := &model.Visit{
+		Location:  visit.Location,
+		VisitedAt: visit.VisitedAt,
+	}
+
+	// 3. get borrower location
+	borrower := s.Helper.GetUserByUserID(ctx, loan.BorrowerID)
+	if borrower.Location == nil {
+		logging.FromContext(ctx).Warn("borrower has no registered location, visit is not measured", "op", op, "borrower_id", loan.BorrowerID)
+		return checked, nil
+	}
+
+	// 4. measure distance from borrower location
+	distance := visit.Location.DistanceMeters(*borrower.Location)
+	checked.DistanceMeters = &distance
+	if s.Visits.MaxDistanceMeters <= 0 || distance <= s.Visits.MaxDistanceMeters {
+		return checked, nil
+	}
+
+	// 5. reject or flag out of range visit
+	if s.Visits.OutOfRange == constant.VisitOutOfRangeReject {
+		logging.FromContext(ctx).Info("visit is out of range", "op", op, "distance_meters", distance, "max_distance_meters", s.Visits.MaxDistanceMeters)
+		return nil, apperror.VisitOutOfRange.New().WithDetail("distance_meters", distance).WithDetail("max_distance_meters", s.Visits.MaxDistanceMeters)
+	}
+	logging.FromContext(ctx).Warn("visit is out of range, flagged for review", "op", op, "distance_meters", distance, "max_distance_meters", s.Visits.MaxDistanceMeters)
+	checked.IsOutOfRange = true
+
+	return checked, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestCheckVisit(t *testing.T) {
+	home := model.GeoPoint{Latitude: -6.2607, Longitude: 106.8137}
+	// about 111 meters north of home
+	near := model.GeoPoint{Latitude: -6.2597, Longitude: 106.8137}
+	// about 1.1 kilometers north of home
+	far := model.GeoPoint{Latitude: -6.2507, Longitude: 106.8137}
+	visitedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	loan := model.Loan{LoanID: 1, BorrowerID: 1}
+	flag := VisitPolicy{MaxDistanceMeters: 500, OutOfRange: constant.VisitOutOfRangeFlag}
+	reject := VisitPolicy{MaxDistanceMeters: 500, OutOfRange: constant.VisitOutOfRangeReject}
+
+	tests := []struct {
+		name                 string
+		policy               VisitPolicy
+		visit                *model.Visit
+		borrower             model.User
+		expectedErr          error
+		expectedVisit        bool
+		expectedDistance     float64
+		expectedIsOutOfRange bool
+		expectedIsMissing    bool
+	}{
+		{
+			name:                 "success - missing visit is flagged",
+			policy:               flag,
+			expectedVisit:        true,
+			expectedIsOutOfRange: true,
+			expectedIsMissing:    true,
+		},
+		{
+			name:        "error - visit is required",
+			policy:      VisitPolicy{Required: true},
+			expectedErr: apperror.InvalidRequest,
+		},
+		{
+			name:        "error - location is the zero point",
+			policy:      flag,
+			visit:       &model.Visit{VisitedAt: visitedAt},
+			expectedErr: apperror.InvalidRequest,
+		},
+		{
+			name:        "error - visit time is empty",
+			policy:      flag,
+			visit:       &model.Visit{Location: near},
+			expectedErr: apperror.InvalidRequest,
+		},
+		{
+			name:        "error - visit time is in the future",
+			policy:      flag,
+			visit:       &model.Visit{Location: near, VisitedAt: time.Now().Add(time.Hour)},
+			expectedErr: apperror.InvalidRequest,
+		},
+		{
+			name:        "error - visit is older than max age",
+			policy:      VisitPolicy{MaxDistanceMeters: 500, MaxAge: 30 * time.Minute, OutOfRange: constant.VisitOutOfRangeFlag},
+			visit:       &model.Visit{Location: near, VisitedAt: visitedAt},
+			expectedErr: apperror.InvalidRequest,
+		},
+		{
+			name:             "success - visit within max age",
+			policy:           VisitPolicy{MaxDistanceMeters: 500, MaxAge: 24 * time.Hour, OutOfRange: constant.VisitOutOfRangeFlag},
+			visit:            &model.Visit{Location: near, VisitedAt: visitedAt},
+			borrower:         model.User{UserID: 1, Location: &home},
+			expectedVisit:    true,
+			expectedDistance: 111,
+		},
+		{
+			name:          "success - borrower has no location",
+			policy:        reject,
+			visit:         &model.Visit{Location: far, VisitedAt: visitedAt},
+			borrower:      model.User{UserID: 1},
+			expectedVisit: true,
+		},
+		{
+			name:             "success - in range",
+			policy:           reject,
+			visit:            &model.Visit{Location: near, VisitedAt: visitedAt, IsOutOfRange: true},
+			borrower:         model.User{UserID: 1, Location: &home},
+			expectedVisit:    true,
+			expectedDistance: 111,
+		},
+		{
+			name:                 "success - out of range is flagged",
+			policy:               flag,
+			visit:                &model.Visit{Location: far, VisitedAt: visitedAt},
+			borrower:             model.User{UserID: 1, Location: &home},
+			expectedVisit:        true,
+			expectedDistance:     1112,
+			expectedIsOutOfRange: true,
+		},
+		{
+			name:        "error - out of range is rejected",
+			policy:      reject,
+			visit:       &model.Visit{Location: far, VisitedAt: visitedAt},
+			borrower:    model.User{UserID: 1, Location: &home},
+			expectedErr: apperror.VisitOutOfRange,
+		},
+		{
+			name:             "success - distance is unchecked without maximum",
+			policy:           VisitPolicy{OutOfRange: constant.VisitOutOfRangeReject},
+			visit:            &model.Visit{Location: far, VisitedAt: visitedAt},
+			borrower:         model.User{UserID: 1, Location: &home},
+			expectedVisit:    true,
+			expectedDistance: 1112,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			if tt.borrower.UserID != 0 {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(tt.borrower).Once()
+			}
+			svc := NewService(mockHelper)
+			svc.Visits = tt.policy
+
+			// main func
+			visit, err := svc.checkVisit(context.Background(), "Test", loan, tt.visit)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedVisit, visit != nil)
+			if visit != nil {
+				assert.Equal(t, tt.expectedIsOutOfRange, visit.IsOutOfRange)
+				assert.Equal(t, tt.expectedIsMissing, visit.IsMissing)
+				if tt.visit != nil {
+					assert.Equal(t, tt.visit.Location, visit.Location)
+					assert.Equal(t, tt.visit.VisitedAt, visit.VisitedAt)
+				}
+				if tt.borrower.Location == nil {
+					assert.Nil(t, visit.DistanceMeters)
+				} else {
+					assert.InDelta(t, tt.expectedDistance, *visit.DistanceMeters, 1)
+				}
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"amartha-test/apperror"
+	"amartha-test/event"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// Subscribe registers the url of the authenticated user to receive the event types, every event type when empty,
+// the secret signing the deliveries is generated when empty. The url must reach a public address
+func (s *Service) Subscribe(ctx context.Context, userID int64, rawURL string, eventTypes []string, secret string) (model.WebhookSubscription, error) {
+	// 1. check user is authenticated
+	if userID == 0 {
+		logging.FromContext(ctx).Info("user id is empty", "op", "Subscribe", "url", rawURL)
+		return model.WebhookSubscription{}, apperror.Unauthenticated.New()
+	}
+	user := s.Helper.GetUserByUserID(ctx, userID)
+	if user.UserID == 0 {
+		logging.FromContext(ctx).Info("user data is not found", "op", "Subscribe", "user_id", userID)
+		return model.WebhookSubscription{}, apperror.Unauthenticated.New().WithDetail("user_id", userID)
+	}
+
+	// 2. sanitize payload
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		logging.FromContext(ctx).Info("url is invalid", "op", "Subscribe", "url", rawURL)
+		return model.WebhookSubscription{}, apperror.InvalidRequest.New().WithDetail("field", "url")
+	}
+	for _, eventType := range eventTypes {
+		if !event.IsValidType(eventType) {
+			logging.FromContext(ctx).Info("event type is invalid", "op", "Subscribe", "url", rawURL, "event_type", eventType)
+			return model.WebhookSubscription{}, apperror.InvalidRequest.New().WithDetail("field", "event_types").WithDetail("event_type", eventType)
+		}
+	}
+
+	// 3. check url reaches a public address, checked again on every delivery
+	err = s.WebhookGuard.CheckTarget(ctx, parsedURL)
+	if err != nil {
+		logging.FromContext(ctx).Info("url target is refused", "op", "Subscribe", "url", rawURL, "error", err)
+		return model.WebhookSubscription{}, apperror.InvalidRequest.Wrap(err).WithDetail("field", "url").WithDetail("reason", err.Error())
+	}
+
+	// 4. generate secret
+	if secret == "" {
+		b := make([]byte, 32)
+		_, err = rand.Read(b)
+		if err != nil {
+			logging.FromContext(ctx).Error("fail to generate secret", "op", "Subscribe", "url", rawURL, "error", err)
+			return model.WebhookSubscription{}, apperror.Internal.Wrap(err)
+		}
+		secret = "whsec_" + hex.EncodeToString(b)
+	}
+
+	// 5. create subscription
+	subscription := model.WebhookSubscription{
+		SubscriptionID: s.Helper.GenerateIncrementalWebhookSubscriptionID(ctx),
+		URL:            rawURL,
+		EventTypes:     eventTypes,
+		Secret:         secret,
+		CreatedAt:      time.Now(),
+	}
+	s.Helper.UpsertWebhookSubscription(ctx, subscription)
+
+	return subscription, nil
+}
+
+// ListSubscriptions returns every webhook subscription, without their secret
+func (s *Service) ListSubscriptions(ctx context.Context) []model.WebhookSubscription {
+	subscriptions := s.Helper.GetWebhookSubscriptions(ctx)
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions
+}
+
+// Unsubscribe deletes the webhook subscription, its pending deliveries are dropped
+func (s *Service) Unsubscribe(ctx context.Context, subscriptionID int64) (model.WebhookSubscription, error) {
+	// 1. get subscription by subscription id
+	subscription := s.Helper.GetWebhookSubscriptionBySubscriptionID(ctx, subscriptionID)
+	if subscription.SubscriptionID == 0 {
+		logging.FromContext(ctx).Info("subscription data is not found", "op", "Unsubscribe", "subscription_id", subscriptionID)
+		return model.WebhookSubscription{}, apperror.WebhookSubscriptionNotFound.New().WithDetail("subscription_id", subscriptionID)
+	}
+
+	// 2. delete subscription
+	s.Helper.DeleteWebhookSubscription(ctx, subscriptionID)
+	subscription.Secret = ""
+
+	return subscription, nil
+}
+
+// ListDeadLetters returns every webhook delivery that failed all of its attempts
+func (s *Service) ListDeadLetters(ctx context.Context) []model.WebhookDelivery {
+	return s.Helper.GetWebhookDeadLetters(ctx)
+}
+
+// RetryDeadLetter queues the dead-lettered delivery again and removes it from the dead-letter list
+func (s *Service) RetryDeadLetter(ctx context.Context, deliveryID int64) (model.WebhookDelivery, error) {
+	// 1. get dead letter by delivery id
+	deadLetter := s.Helper.GetWebhookDeadLetterByDeliveryID(ctx, deliveryID)
+	if deadLetter.DeliveryID == 0 {
+		logging.FromContext(ctx).Info("dead letter data is not found", "op", "RetryDeadLetter", "delivery_id", deliveryID)
+		return model.WebhookDelivery{}, apperror.WebhookDeliveryNotFound.New().WithDetail("delivery_id", deliveryID)
+	}
+
+	// 2. check subscription still exists
+	subscription := s.Helper.GetWebhookSubscriptionBySubscriptionID(ctx, deadLetter.SubscriptionID)
+	if subscription.SubscriptionID == 0 {
+		logging.FromContext(ctx).Info("subscription data is not found", "op", "RetryDeadLetter", "delivery_id", deliveryID, "subscription_id", deadLetter.SubscriptionID)
+		return model.WebhookDelivery{}, apperror.WebhookSubscriptionNotFound.New().WithDetail("subscription_id", deadLetter.SubscriptionID)
+	}
+
+	// 3. redeliver
+	if s.Webhooks == nil {
+		logging.FromContext(ctx).Info("webhook dispatcher is not configured", "op", "RetryDeadLetter", "delivery_id", deliveryID)
+		return model.WebhookDelivery{}, apperror.Internal.New().WithDetail("delivery_id", deliveryID)
+	}
+	err := s.Webhooks.Redeliver(ctx, deadLetter)
+	if err != nil {
+		logging.FromContext(ctx).Error("fail to redeliver", "op", "RetryDeadLetter", "delivery_id", deliveryID, "error", err)
+		return model.WebhookDelivery{}, apperror.Internal.Wrap(err).WithDetail("delivery_id", deliveryID)
+	}
+	s.Helper.DeleteWebhookDeadLetter(ctx, deliveryID)
+
+	return deadLetter, nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/apperror"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+	"amartha-test/webhook"
+)
+
+type fakeDispatcher struct {
+	err         error
+	redelivered []model.WebhookDelivery
+}
+
+func (d *fakeDispatcher) Handle(ctx context.Context, e event.Event) {}
+
+func (d *fakeDispatcher) Redeliver(ctx context.Context, delivery model.WebhookDelivery) error {
+	d.redelivered = append(d.redelivered, delivery)
+	return d.err
+}
+
+// staticResolver resolves the hosts of the map, any other host is not found
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return addrs, nil
+}
+
+func TestSubscribe(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+	svc.WebhookGuard = &webhook.Guard{Resolver: staticResolver{
+		"receiver.example.com": {netip.MustParseAddr("93.184.215.14")},
+		"internal.example.com": {netip.MustParseAddr("10.0.0.5")},
+	}}
+	user := model.User{UserID: 5, UserType: constant.UserTypeFieldOfficerEmployee}
+
+	tests := []struct {
+		name        string
+		userID      int64
+		url         string
+		eventTypes  []string
+		secret      string
+		expectedErr error
+		mocks       func()
+	}{
+		{
+			name:        "error - user is not identified",
+			url:         "https://receiver.example.com/events",
+			expectedErr: apperror.Unauthenticated,
+			mocks:       func() {},
+		},
+		{
+			name:        "error - user is not found",
+			userID:      9,
+			url:         "https://receiver.example.com/events",
+			expectedErr: apperror.Unauthenticated,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(9)).Return(model.User{}).Once()
+			},
+		},
+		{
+			name:        "error - url without scheme",
+			userID:      5,
+			url:         "receiver.example.com/events",
+			expectedErr: apperror.InvalidRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:        "error - url scheme not http",
+			userID:      5,
+			url:         "ftp://receiver.example.com/events",
+			expectedErr: apperror.InvalidRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:        "error - unknown event type",
+			userID:      5,
+			url:         "https://receiver.example.com/events",
+			eventTypes:  []string{"loan.invested", "loan.deleted"},
+			expectedErr: apperror.InvalidRequest,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:        "error - loopback url",
+			userID:      5,
+			url:         "http://localhost:9000/events",
+			expectedErr: webhook.ErrPrivateTarget,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:        "error - cloud metadata url",
+			userID:      5,
+			url:         "http://169.254.169.254/latest/meta-data/",
+			expectedErr: webhook.ErrPrivateTarget,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:        "error - host resolving to a private address",
+			userID:      5,
+			url:         "https://internal.example.com/events",
+			expectedErr: webhook.ErrPrivateTarget,
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+			},
+		},
+		{
+			name:       "success - secret given",
+			userID:     5,
+			url:        "https://receiver.example.com/events",
+			eventTypes: []string{"loan.invested"},
+			secret:     "shared-secret",
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+				mockHelper.On("GenerateIncrementalWebhookSubscriptionID", mock.Anything).Return(int64(1)).Once()
+				mockHelper.On("UpsertWebhookSubscription", mock.Anything, mock.MatchedBy(func(subscription model.WebhookSubscription) bool {
+					return subscription.Secret == "shared-secret"
+				})).Return().Once()
+			},
+		},
+		{
+			name:   "success - secret generated",
+			userID: 5,
+			url:    "http://93.184.215.14:9000/events",
+			mocks: func() {
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(5)).Return(user).Once()
+				mockHelper.On("GenerateIncrementalWebhookSubscriptionID", mock.Anything).Return(int64(2)).Once()
+				mockHelper.On("UpsertWebhookSubscription", mock.Anything, mock.Anything).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			subscription, err := svc.Subscribe(context.Background(), tt.userID, tt.url, tt.eventTypes, tt.secret)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.url, subscription.URL)
+				assert.NotEmpty(t, subscription.Secret)
+				if tt.secret == "" {
+					assert.True(t, strings.HasPrefix(subscription.Secret, "whsec_"))
+				}
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	mockHelper.On("GetWebhookSubscriptions", mock.Anything).Return([]model.WebhookSubscription{{SubscriptionID: 1, Secret: "shared-secret"}}).Once()
+
+	subscriptions := svc.ListSubscriptions(context.Background())
+
+	assert.Equal(t, []model.WebhookSubscription{{SubscriptionID: 1}}, subscriptions)
+	mockHelper.AssertExpectations(t)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	mockHelper := new(mocks.IHelper)
+	svc := NewService(mockHelper)
+
+	tests := []struct {
+		name           string
+		subscriptionID int64
+		expectedErr    error
+		mocks          func()
+	}{
+		{
+			name:           "error - subscription not found",
+			subscriptionID: 9,
+			expectedErr:    apperror.WebhookSubscriptionNotFound,
+			mocks: func() {
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(9)).Return(model.WebhookSubscription{}).Once()
+			},
+		},
+		{
+			name:           "success",
+			subscriptionID: 1,
+			mocks: func() {
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1, Secret: "shared-secret"}).Once()
+				mockHelper.On("DeleteWebhookSubscription", mock.Anything, int64(1)).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mocks()
+
+			// main func
+			subscription, err := svc.Unsubscribe(context.Background(), tt.subscriptionID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.subscriptionID, subscription.SubscriptionID)
+				assert.Empty(t, subscription.Secret)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetryDeadLetter(t *testing.T) {
+	deadLetter := model.WebhookDelivery{DeliveryID: 3, SubscriptionID: 1, EventID: "evt_0a1b", EventType: "loan.invested"}
+
+	tests := []struct {
+		name        string
+		deliveryID  int64
+		dispatcher  *fakeDispatcher
+		expectedErr error
+		mocks       func(mockHelper *mocks.IHelper)
+	}{
+		{
+			name:        "error - dead letter not found",
+			deliveryID:  9,
+			dispatcher:  &fakeDispatcher{},
+			expectedErr: apperror.WebhookDeliveryNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(9)).Return(model.WebhookDelivery{}).Once()
+			},
+		},
+		{
+			name:        "error - subscription deleted",
+			deliveryID:  3,
+			dispatcher:  &fakeDispatcher{},
+			expectedErr: apperror.WebhookSubscriptionNotFound,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(3)).Return(deadLetter).Once()
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{}).Once()
+			},
+		},
+		{
+			name:        "error - redeliver failed",
+			deliveryID:  3,
+			dispatcher:  &fakeDispatcher{err: errors.New("subscription 1 is not found")},
+			expectedErr: apperror.Internal,
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(3)).Return(deadLetter).Once()
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1}).Once()
+			},
+		},
+		{
+			name:       "success",
+			deliveryID: 3,
+			dispatcher: &fakeDispatcher{},
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetWebhookDeadLetterByDeliveryID", mock.Anything, int64(3)).Return(deadLetter).Once()
+				mockHelper.On("GetWebhookSubscriptionBySubscriptionID", mock.Anything, int64(1)).Return(model.WebhookSubscription{SubscriptionID: 1}).Once()
+				mockHelper.On("DeleteWebhookDeadLetter", mock.Anything, int64(3)).Return().Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			svc := NewService(mockHelper)
+			svc.Webhooks = tt.dispatcher
+
+			// main func
+			delivery, err := svc.RetryDeadLetter(context.Background(), tt.deliveryID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, deadLetter, delivery)
+				assert.Equal(t, []model.WebhookDelivery{deadLetter}, tt.dispatcher.redelivered)
+			}
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrDocumentNotFound   = errors.New("document is not found")
+	ErrInvalidDocumentKey = errors.New("document key is invalid")
+)
+
+// IDocumentStore stores documents content-addressed, the key of a document is the sha256 hash of its content
+type IDocumentStore interface {
+	Put(ctx context.Context, data []byte) (string, error)
+	Open(ctx context.Context, key string) (Document, error)
+	// Ping checks the store is reachable, it is the readiness check of the server
+	Ping(ctx context.Context) error
+}
+
+// Document is an opened document, Content must be closed by the caller
+type Document struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+	Content io.ReadSeekCloser
+}
+
+// DocumentKey returns the content address of the data
+func DocumentKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func validateDocumentKey(key string) error {
+	if len(key) != sha256.Size*2 {
+		return ErrInvalidDocumentKey
+	}
+
+	_, err := hex.DecodeString(key)
+	if err != nil {
+		return ErrInvalidDocumentKey
+	}
+
+	return nil
+}
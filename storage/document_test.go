@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentStore(t *testing.T) {
+	localStore, err := NewLocalDocumentStore(filepath.Join(t.TempDir(), "documents"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stores := map[string]IDocumentStore{
+		"local":  localStore,
+		"memory": NewMemoryDocumentStore(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			data := []byte("%PDF-1.3 agreement")
+
+			key, err := store.Put(ctx, data)
+			assert.NoError(t, err)
+			assert.Equal(t, DocumentKey(data), key)
+
+			// same content is deduplicated to the same key
+			sameKey, err := store.Put(ctx, data)
+			assert.NoError(t, err)
+			assert.Equal(t, key, sameKey)
+
+			document, err := store.Open(ctx, key)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(data)), document.Size)
+			content, err := io.ReadAll(document.Content)
+			assert.NoError(t, err)
+			assert.Equal(t, data, content)
+			assert.NoError(t, document.Content.Close())
+
+			_, err = store.Open(ctx, DocumentKey([]byte("unknown")))
+			assert.ErrorIs(t, err, ErrDocumentNotFound)
+
+			_, err = store.Open(ctx, "../../etc/passwd")
+			assert.ErrorIs(t, err, ErrInvalidDocumentKey)
+
+			assert.NoError(t, store.Ping(ctx))
+		})
+	}
+}
+
+func TestLocalDocumentStoreLayout(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalDocumentStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := store.Put(context.Background(), []byte("content"))
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, key[:2], key))
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(dir, key[:2]))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must be cleaned up")
+}
+
+func TestLocalDocumentStorePing(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "documents")
+	store, err := NewLocalDocumentStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, store.Ping(context.Background()))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "probe file must be cleaned up")
+
+	// the document dir is gone, e.g. the volume is unmounted
+	assert.NoError(t, os.RemoveAll(dir))
+	assert.Error(t, store.Ping(context.Background()))
+}
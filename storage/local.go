@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalDocumentStore stores documents on the local filesystem as {dir}/{key[:2]}/{key}
+type LocalDocumentStore struct {
+	dir string
+}
+
+func NewLocalDocumentStore(dir string) (*LocalDocumentStore, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("create document dir: %w", err)
+	}
+
+	return &LocalDocumentStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *LocalDocumentStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+func (s *LocalDocumentStore) Put(ctx context.Context, data []byte) (string, error) {
+	key := DocumentKey(data)
+	path := s.path(key)
+
+	// same content is already stored
+	_, err := os.Stat(path)
+	if err == nil {
+		return key, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return "", err
+	}
+
+	// write to temp file first so a partially written document is never visible
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return "", err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (s *LocalDocumentStore) Open(ctx context.Context, key string) (Document, error) {
+	err := validateDocumentKey(key)
+	if err != nil {
+		return Document{}, err
+	}
+
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Document{}, ErrDocumentNotFound
+	}
+	if err != nil {
+		return Document{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return Document{}, err
+	}
+
+	return Document{
+		Key:     key,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Content: file,
+	}, nil
+}
+
+// Ping checks the document dir is still there and writable, by writing and removing a probe file
+func (s *LocalDocumentStore) Ping(ctx context.Context) error {
+	probe, err := os.CreateTemp(s.dir, ".ping-*")
+	if err != nil {
+		return fmt.Errorf("write document dir: %w", err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryDocumentStore keeps documents in memory, meant for tests
+type MemoryDocumentStore struct {
+	mutex     sync.RWMutex
+	documents map[string]memoryDocument
+}
+
+type memoryDocument struct {
+	data    []byte
+	modTime time.Time
+}
+
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error {
+	return nil
+}
+
+func NewMemoryDocumentStore() *MemoryDocumentStore {
+	return &MemoryDocumentStore{
+		documents: make(map[string]memoryDocument),
+	}
+}
+
+func (s *MemoryDocumentStore) Put(ctx context.Context, data []byte) (string, error) {
+	key := DocumentKey(data)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.documents[key]; !exists {
+		s.documents[key] = memoryDocument{
+			data:    bytes.Clone(data),
+			modTime: time.Now(),
+		}
+	}
+
+	return key, nil
+}
+
+func (s *MemoryDocumentStore) Open(ctx context.Context, key string) (Document, error) {
+	err := validateDocumentKey(key)
+	if err != nil {
+		return Document{}, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	document, exists := s.documents[key]
+	if !exists {
+		return Document{}, ErrDocumentNotFound
+	}
+
+	return Document{
+		Key:     key,
+		Size:    int64(len(document.data)),
+		ModTime: document.modTime,
+		Content: nopSeekCloser{bytes.NewReader(document.data)},
+	}, nil
+}
+
+// Ping never fails, the documents live in the process
+func (s *MemoryDocumentStore) Ping(ctx context.Context) error {
+	return nil
+}
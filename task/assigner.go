@@ -0,0 +1,138 @@
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"amartha-test/audit"
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper"
+	"amartha-test/logging"
+	"amartha-test/model"
+)
+
+// Assigner creates the field visit tasks of the loans as they move through their lifecycle, assigns them to the
+// employee of the borrower region with the fewest open tasks, and completes them once the loan is approved or disbursed
+type Assigner struct {
+	Helper helper.IHelper
+
+	// mutex serializes the assignments, so two tasks created at once do not both go to the same least loaded employee
+	mutex sync.Mutex
+}
+
+func NewAssigner(helper helper.IHelper) *Assigner {
+	return &Assigner{
+		Helper: helper,
+	}
+}
+
+// Handle is the event bus handler, the tasks are kept in memory so they are written on the publisher goroutine
+// and are listed as soon as the request is answered
+func (a *Assigner) Handle(ctx context.Context, e event.Event) {
+	switch e.Type {
+	case event.LoanSubmitted:
+		a.Create(ctx, e.Loan, constant.TaskTypeValidationVisit)
+	case event.LoanApproved:
+		a.Complete(ctx, e.Loan.LoanID, constant.TaskTypeValidationVisit, e.UserID)
+	case event.LoanSigned:
+		a.Create(ctx, e.Loan, constant.TaskTypeDisbursementVisit)
+	case event.LoanDisbursed:
+		a.Complete(ctx, e.Loan.LoanID, constant.TaskTypeDisbursementVisit, e.UserID)
+	}
+}
+
+// Create creates the open task of the loan, a loan has at most one open task of each type.
+// The task is left unassigned when there is no employee of its type at all
+func (a *Assigner) Create(ctx context.Context, loan model.Loan, taskType int) model.Task {
+	ctx = logging.With(ctx, "loan_id", loan.LoanID)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// 1. check open task of the loan
+	existing := a.Helper.GetTasksByFilter(ctx, model.TaskFilter{LoanID: loan.LoanID, TaskType: taskType, Status: constant.TaskStatusOpen})
+	if len(existing) > 0 {
+		logging.FromContext(ctx).Info("loan already has an open task", "op", "CreateTask", "task_id", existing[0].TaskID)
+		return existing[0]
+	}
+
+	// 2. get borrower region
+	region := a.Helper.GetUserByUserID(ctx, loan.BorrowerID).Region
+
+	// 3. create task, assigned by the system
+	ctx = audit.WithActor(ctx, audit.SystemActor)
+	task := model.Task{
+		TaskID:       a.Helper.GenerateIncrementalTaskID(ctx),
+		LoanID:       loan.LoanID,
+		LoanPublicID: loan.PublicID,
+		TaskType:     taskType,
+		Status:       constant.TaskStatusOpen,
+		AssigneeID:   a.assignee(ctx, constant.TaskTypeAssigneeUserType[taskType], region),
+		Region:       region,
+		CreatedAt:    time.Now(),
+	}
+	task.TaskTypeDesc = constant.GetTaskTypeDesc(task.TaskType)
+	task.StatusDesc = constant.GetTaskStatusDesc(task.Status)
+	a.Helper.UpsertTask(ctx, task)
+
+	return task
+}
+
+// assignee returns the employee of the user type with the fewest open tasks, those of the region first.
+// Ties go to the lowest user id, zero when there is no employee of the user type
+func (a *Assigner) assignee(ctx context.Context, userType int, region string) int64 {
+	// 1. get employees of the region, or of every region when none works in it
+	employees := a.Helper.GetUsersByFilter(ctx, model.UserFilter{UserType: userType, Region: region})
+	if len(employees) == 0 {
+		employees = a.Helper.GetUsersByFilter(ctx, model.UserFilter{UserType: userType})
+		if len(employees) == 0 {
+			logging.FromContext(ctx).Warn("no employee to assign the task to, task is unassigned", "op", "CreateTask", "user_type", userType)
+			return 0
+		}
+		logging.FromContext(ctx).Warn("no employee in the region, task is assigned outside of it", "op", "CreateTask", "user_type", userType, "region", region)
+	}
+
+	// 2. count open tasks of every employee
+	load := make(map[int64]int)
+	for _, v := range a.Helper.GetTasksByFilter(ctx, model.TaskFilter{Status: constant.TaskStatusOpen}) {
+		load[v.AssigneeID]++
+	}
+
+	// 3. pick the least loaded employee
+	assignee := employees[0]
+	for _, v := range employees[1:] {
+		if load[v.UserID] < load[assignee.UserID] || (load[v.UserID] == load[assignee.UserID] && v.UserID < assignee.UserID) {
+			assignee = v
+		}
+	}
+
+	return assignee.UserID
+}
+
+// Complete completes the open tasks of the loan by the employee who approved or disbursed it, who may not be
+// the assignee when the visit was taken over
+func (a *Assigner) Complete(ctx context.Context, loanID int64, taskType int, employeeID int64) {
+	ctx = logging.With(ctx, "loan_id", loanID)
+
+	// 1. get open tasks of the loan
+	openTasks := a.Helper.GetTasksByFilter(ctx, model.TaskFilter{LoanID: loanID, TaskType: taskType, Status: constant.TaskStatusOpen})
+	if len(openTasks) == 0 {
+		logging.FromContext(ctx).Info("loan has no open task to complete", "op", "CompleteTask", "task_type", constant.GetTaskTypeDesc(taskType))
+		return
+	}
+
+	// 2. complete tasks
+	for _, task := range openTasks {
+		if task.AssigneeID != employeeID {
+			logging.FromContext(ctx).Info("task is completed by another employee than its assignee", "op", "CompleteTask", "task_id", task.TaskID, "assignee_id", task.AssigneeID, "employee_id", employeeID)
+		}
+
+		task.Status = constant.TaskStatusCompleted
+		task.StatusDesc = constant.GetTaskStatusDesc(task.Status)
+		task.CompletedBy = employeeID
+		completedAt := time.Now()
+		task.CompletedAt = &completedAt
+		a.Helper.UpsertTask(ctx, task)
+	}
+}
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"amartha-test/constant"
+	"amartha-test/event"
+	"amartha-test/helper/mocks"
+	"amartha-test/model"
+)
+
+func TestCreate(t *testing.T) {
+	loan := model.Loan{LoanID: 7, BorrowerID: 1}
+	borrower := model.User{UserID: 1, UserType: constant.UserTypeBorrower, Region: "jakarta-selatan"}
+	validators := []model.User{
+		{UserID: 12, UserType: constant.UserTypeFieldValidatorEmployee, Region: "jakarta-selatan"},
+		{UserID: 11, UserType: constant.UserTypeFieldValidatorEmployee, Region: "jakarta-selatan"},
+	}
+	outsider := model.User{UserID: 21, UserType: constant.UserTypeFieldValidatorEmployee, Region: "bandung"}
+
+	tests := []struct {
+		name               string
+		mocks              func(mockHelper *mocks.IHelper)
+		expectedTaskID     int64
+		expectedAssigneeID int64
+	}{
+		{
+			name: "success - assigned to least loaded employee of the region",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeValidationVisit, Status: constant.TaskStatusOpen}).Return(nil).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, model.UserFilter{UserType: constant.UserTypeFieldValidatorEmployee, Region: "jakarta-selatan"}).Return(validators).Once()
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{Status: constant.TaskStatusOpen}).Return([]model.Task{{AssigneeID: 11}, {AssigneeID: 11}, {AssigneeID: 12}}).Once()
+				mockHelper.On("GenerateIncrementalTaskID", mock.Anything).Return(int64(3)).Once()
+				mockHelper.On("UpsertTask", mock.Anything, mock.Anything).Return().Once()
+			},
+			expectedTaskID:     3,
+			expectedAssigneeID: 12,
+		},
+		{
+			name: "success - tie goes to lowest user id",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeValidationVisit, Status: constant.TaskStatusOpen}).Return(nil).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, model.UserFilter{UserType: constant.UserTypeFieldValidatorEmployee, Region: "jakarta-selatan"}).Return(validators).Once()
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{Status: constant.TaskStatusOpen}).Return([]model.Task{{AssigneeID: 11}, {AssigneeID: 12}}).Once()
+				mockHelper.On("GenerateIncrementalTaskID", mock.Anything).Return(int64(3)).Once()
+				mockHelper.On("UpsertTask", mock.Anything, mock.Anything).Return().Once()
+			},
+			expectedTaskID:     3,
+			expectedAssigneeID: 11,
+		},
+		{
+			name: "success - assigned outside of the region without employee in it",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeValidationVisit, Status: constant.TaskStatusOpen}).Return(nil).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, model.UserFilter{UserType: constant.UserTypeFieldValidatorEmployee, Region: "jakarta-selatan"}).Return(nil).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, model.UserFilter{UserType: constant.UserTypeFieldValidatorEmployee}).Return([]model.User{outsider}).Once()
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{Status: constant.TaskStatusOpen}).Return(nil).Once()
+				mockHelper.On("GenerateIncrementalTaskID", mock.Anything).Return(int64(3)).Once()
+				mockHelper.On("UpsertTask", mock.Anything, mock.Anything).Return().Once()
+			},
+			expectedTaskID:     3,
+			expectedAssigneeID: 21,
+		},
+		{
+			name: "success - unassigned without employee",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeValidationVisit, Status: constant.TaskStatusOpen}).Return(nil).Once()
+				mockHelper.On("GetUserByUserID", mock.Anything, int64(1)).Return(borrower).Once()
+				mockHelper.On("GetUsersByFilter", mock.Anything, mock.Anything).Return(nil).Twice()
+				mockHelper.On("GenerateIncrementalTaskID", mock.Anything).Return(int64(3)).Once()
+				mockHelper.On("UpsertTask", mock.Anything, mock.Anything).Return().Once()
+			},
+			expectedTaskID:     3,
+			expectedAssigneeID: 0,
+		},
+		{
+			name: "success - open task is kept",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeValidationVisit, Status: constant.TaskStatusOpen}).Return([]model.Task{{TaskID: 2, AssigneeID: 11, Status: constant.TaskStatusOpen}}).Once()
+			},
+			expectedTaskID:     2,
+			expectedAssigneeID: 11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			assigner := NewAssigner(mockHelper)
+
+			// main func
+			task := assigner.Create(context.Background(), loan, constant.TaskTypeValidationVisit)
+
+			assert.Equal(t, tt.expectedTaskID, task.TaskID)
+			assert.Equal(t, tt.expectedAssigneeID, task.AssigneeID)
+			assert.Equal(t, constant.TaskStatusOpen, task.Status)
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name  string
+		mocks func(mockHelper *mocks.IHelper)
+	}{
+		{
+			name: "success - open task is completed",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: constant.TaskTypeDisbursementVisit, Status: constant.TaskStatusOpen}).Return([]model.Task{{TaskID: 2, LoanID: 7, AssigneeID: 5}}).Once()
+				mockHelper.On("UpsertTask", mock.Anything, mock.MatchedBy(func(task model.Task) bool {
+					return task.TaskID == 2 && task.Status == constant.TaskStatusCompleted && task.StatusDesc == "completed" && task.CompletedBy == 6 && task.CompletedAt != nil
+				})).Return().Once()
+			},
+		},
+		{
+			name: "success - no open task",
+			mocks: func(mockHelper *mocks.IHelper) {
+				mockHelper.On("GetTasksByFilter", mock.Anything, mock.Anything).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			tt.mocks(mockHelper)
+			assigner := NewAssigner(mockHelper)
+
+			// main func
+			assigner.Complete(context.Background(), 7, constant.TaskTypeDisbursementVisit, 6)
+
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	loan := model.Loan{LoanID: 7, BorrowerID: 1}
+
+	tests := []struct {
+		name             string
+		event            event.Event
+		expectedTaskType int
+		isCreated        bool
+		isCompleted      bool
+	}{
+		{name: "loan submitted creates validation visit", event: event.New(event.LoanSubmitted, 1, loan), expectedTaskType: constant.TaskTypeValidationVisit, isCreated: true},
+		{name: "loan approved completes validation visit", event: event.New(event.LoanApproved, 4, loan), expectedTaskType: constant.TaskTypeValidationVisit, isCompleted: true},
+		{name: "loan signed creates disbursement visit", event: event.New(event.LoanSigned, 1, loan), expectedTaskType: constant.TaskTypeDisbursementVisit, isCreated: true},
+		{name: "loan disbursed completes disbursement visit", event: event.New(event.LoanDisbursed, 5, loan), expectedTaskType: constant.TaskTypeDisbursementVisit, isCompleted: true},
+		{name: "loan invested is ignored", event: event.New(event.LoanInvested, 2, loan)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHelper := new(mocks.IHelper)
+			if tt.isCreated || tt.isCompleted {
+				mockHelper.On("GetTasksByFilter", mock.Anything, model.TaskFilter{LoanID: 7, TaskType: tt.expectedTaskType, Status: constant.TaskStatusOpen}).Return([]model.Task{{TaskID: 2, LoanID: 7, TaskType: tt.expectedTaskType}}).Once()
+			}
+			if tt.isCompleted {
+				mockHelper.On("UpsertTask", mock.Anything, mock.MatchedBy(func(task model.Task) bool {
+					return task.CompletedBy == tt.event.UserID
+				})).Return().Once()
+			}
+			assigner := NewAssigner(mockHelper)
+
+			// main func
+			assigner.Handle(context.Background(), tt.event)
+
+			mockHelper.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name           string
+		exporter       string
+		isError        bool
+		expectedOutput string
+	}{
+		{
+			name:     "success - disabled",
+			exporter: "",
+		},
+		{
+			name:           "success - stdout",
+			exporter:       ExporterStdout,
+			expectedOutput: `"Name":"test span"`,
+		},
+		{
+			name:     "success - otlp",
+			exporter: ExporterOTLP,
+		},
+		{
+			name:     "error - unknown exporter",
+			exporter: "jaeger",
+			isError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer otel.SetTracerProvider(otel.GetTracerProvider())
+			var stdout bytes.Buffer
+
+			// main func
+			shutdown, err := Setup(context.Background(), tt.exporter, "localhost:4318", &stdout)
+
+			assert.Equal(t, tt.isError, err != nil)
+			if tt.isError {
+				return
+			}
+			if tt.expectedOutput != "" {
+				_, span := Start(context.Background(), "test span")
+				span.End()
+			}
+			assert.NoError(t, shutdown(context.Background()))
+			assert.Contains(t, stdout.String(), tt.expectedOutput)
+		})
+	}
+}
+
+func TestStartAndEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// main func
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", attribute.Int64("loan.id", 1))
+	End(child, errors.New("render failed"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("loan.id", 1)}, spans[0].Attributes())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
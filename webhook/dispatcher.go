@@ -0,0 +1,281 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"amartha-test/audit"
+	"amartha-test/event"
+	"amartha-test/helper"
+	"amartha-test/logging"
+	"amartha-test/model"
+	"amartha-test/tracing"
+)
+
+const (
+	DefaultWorkers     = 4
+	DefaultMaxAttempts = 5
+	DefaultQueueSize   = 1024
+	DefaultTimeout     = 10 * time.Second
+
+	drainPollInterval = 10 * time.Millisecond
+)
+
+// errShutdown is the error of the deliveries dead-lettered because the server stopped before sending them
+var errShutdown = errors.New("server shut down before the delivery")
+
+type IDispatcher interface {
+	Handle(ctx context.Context, e event.Event)
+	Redeliver(ctx context.Context, delivery model.WebhookDelivery) error
+}
+
+// Dispatcher delivers events to the subscribed webhooks, retrying with exponential backoff
+// and moving deliveries failing every attempt to the dead-letter list
+type Dispatcher struct {
+	Helper helper.IHelper
+	// Client sends the deliveries, only to public addresses unless replaced
+	Client      *http.Client
+	MaxAttempts int
+	// Backoff returns the wait before the given retry, starting at 1
+	Backoff func(retry int) time.Duration
+
+	queue chan delivery
+	// pending counts the queued and in-flight deliveries
+	pending atomic.Int64
+}
+
+type delivery struct {
+	subscriptionID int64
+	eventID        string
+	eventType      string
+	payload        []byte
+	// spanContext is the span of the request publishing the event, the delivery continues its trace
+	spanContext trace.SpanContext
+}
+
+func NewDispatcher(helper helper.IHelper) *Dispatcher {
+	return &Dispatcher{
+		Helper:      helper,
+		Client:      NewGuard().Client(DefaultTimeout),
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     ExponentialBackoff(time.Second, time.Minute),
+		queue:       make(chan delivery, DefaultQueueSize),
+	}
+}
+
+// ExponentialBackoff doubles the wait from base on each retry, up to max
+func ExponentialBackoff(base time.Duration, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		wait := base
+		for i := 1; i < retry && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+
+		return wait
+	}
+}
+
+// Handle is the event bus handler queueing the event for every subscription of its type
+func (d *Dispatcher) Handle(ctx context.Context, e event.Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed marshal event", "op", "Dispatcher", "event_id", e.ID, "error", err)
+		return
+	}
+
+	for _, subscription := range d.Helper.GetWebhookSubscriptions(ctx) {
+		if !subscription.IsSubscribed(string(e.Type)) {
+			continue
+		}
+
+		d.enqueue(ctx, delivery{
+			subscriptionID: subscription.SubscriptionID,
+			eventID:        e.ID,
+			eventType:      string(e.Type),
+			payload:        payload,
+			spanContext:    trace.SpanContextFromContext(ctx),
+		})
+	}
+}
+
+// Redeliver queues the dead-lettered delivery again, with a fresh set of attempts
+func (d *Dispatcher) Redeliver(ctx context.Context, deadLetter model.WebhookDelivery) error {
+	subscription := d.Helper.GetWebhookSubscriptionBySubscriptionID(ctx, deadLetter.SubscriptionID)
+	if subscription.SubscriptionID == 0 {
+		return fmt.Errorf("subscription %d is not found", deadLetter.SubscriptionID)
+	}
+
+	d.enqueue(ctx, delivery{
+		subscriptionID: deadLetter.SubscriptionID,
+		eventID:        deadLetter.EventID,
+		eventType:      deadLetter.EventType,
+		payload:        deadLetter.Payload,
+		spanContext:    trace.SpanContextFromContext(ctx),
+	})
+
+	return nil
+}
+
+// enqueue never blocks the publisher, a full queue dead-letters the delivery
+func (d *Dispatcher) enqueue(ctx context.Context, item delivery) {
+	d.pending.Add(1)
+	select {
+	case d.queue <- item:
+	default:
+		d.pending.Add(-1)
+		logging.FromContext(ctx).Warn("delivery queue is full", "op", "Dispatcher", "event_id", item.eventID, "subscription_id", item.subscriptionID)
+		d.deadLetter(ctx, item, 0, 0, fmt.Errorf("delivery queue is full"))
+	}
+}
+
+// Run delivers the queued deliveries with the given number of workers until ctx is done.
+// The deliveries cut by ctx and the ones left in the queue are dead-lettered, so they can be retried
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-d.queue:
+					d.deliver(ctx, item)
+					d.pending.Add(-1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for {
+		select {
+		case item := <-d.queue:
+			logging.FromContext(ctx).Warn("delivery is left on shutdown", "op", "Dispatcher", "event_id", item.eventID, "subscription_id", item.subscriptionID)
+			d.deadLetter(ctx, item, 0, 0, errShutdown)
+			d.pending.Add(-1)
+		default:
+			return
+		}
+	}
+}
+
+// Drain waits until every queued delivery is delivered or dead-lettered, or ctx is done.
+// Run keeps delivering meanwhile, it is stopped after Drain
+func (d *Dispatcher) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for d.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, item delivery) {
+	var statusCode int
+	var err error
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				logging.FromContext(ctx).Warn("delivery is cut on shutdown", "op", "Dispatcher", "event_id", item.eventID, "subscription_id", item.subscriptionID, "attempts", attempt-1)
+				d.deadLetter(ctx, item, attempt-1, statusCode, ctx.Err())
+				return
+			case <-time.After(d.Backoff(attempt - 1)):
+			}
+		}
+
+		// the subscription is read on every attempt so unsubscribing stops the retries
+		subscription := d.Helper.GetWebhookSubscriptionBySubscriptionID(ctx, item.subscriptionID)
+		if subscription.SubscriptionID == 0 {
+			logging.FromContext(ctx).Info("subscription is deleted, delivery dropped", "op", "Dispatcher", "event_id", item.eventID, "subscription_id", item.subscriptionID)
+			return
+		}
+
+		statusCode, err = d.send(ctx, subscription, item)
+		if err == nil {
+			return
+		}
+		logging.FromContext(ctx).Warn("delivery attempt failed", "op", "Dispatcher", "event_id", item.eventID, "subscription_id", item.subscriptionID, "attempt", attempt, "error", err)
+	}
+
+	d.deadLetter(ctx, item, d.MaxAttempts, statusCode, err)
+}
+
+// send posts the delivery in a client span under the span of the publishing request, the receiver continues the
+// trace from the W3C traceparent header
+func (d *Dispatcher) send(ctx context.Context, subscription model.WebhookSubscription, item delivery) (statusCode int, err error) {
+	ctx, span := otel.Tracer(tracing.ServiceName).Start(trace.ContextWithSpanContext(ctx, item.spanContext), "webhook.Deliver",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(http.MethodPost)))
+	defer func() {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+		tracing.End(span, err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(item.payload))
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(semconv.ServerAddress(req.URL.Hostname()))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, item.eventID)
+	req.Header.Set(HeaderEventType, item.eventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, item.payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// deadLetter keeps the failed delivery, written by the dispatcher itself rather than on behalf of a request
+func (d *Dispatcher) deadLetter(ctx context.Context, item delivery, attempts int, statusCode int, err error) {
+	ctx = audit.WithActor(ctx, audit.SystemActor)
+	d.Helper.UpsertWebhookDeadLetter(ctx, model.WebhookDelivery{
+		DeliveryID:     d.Helper.GenerateIncrementalWebhookDeliveryID(ctx),
+		SubscriptionID: item.subscriptionID,
+		EventID:        item.eventID,
+		EventType:      item.eventType,
+		Payload:        item.payload,
+		Attempts:       attempts,
+		LastStatusCode: statusCode,
+		LastError:      err.Error(),
+		FailedAt:       time.Now(),
+	})
+}
@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"amartha-test/config"
+	"amartha-test/event"
+	"amartha-test/helper"
+	"amartha-test/model"
+)
+
+// receiver is a local webhook receiver answering each request with the next status code, the last one repeats
+type receiver struct {
+	t        *testing.T
+	secret   string
+	mutex    sync.Mutex
+	statuses []int
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	assert.True(rc.t, Verify(rc.secret, timestamp, body, r.Header.Get(HeaderSignature)), "signature of %s is invalid", r.Header.Get(HeaderEventID))
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	rc.received = append(rc.received, r)
+	rc.bodies = append(rc.bodies, body)
+	status := rc.statuses[0]
+	if len(rc.statuses) > 1 {
+		rc.statuses = rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	return len(rc.received)
+}
+
+func (rc *receiver) respond(statuses ...int) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	rc.statuses = statuses
+}
+
+// newTestDispatcher runs a dispatcher retrying without delay, subscribed to a local receiver
+func newTestDispatcher(t *testing.T, eventTypes []string, statuses ...int) (*Dispatcher, *receiver, model.WebhookSubscription) {
+	dispatcher, rc, subscription := newIdleDispatcher(t, eventTypes, statuses...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go dispatcher.Run(ctx, 2)
+
+	return dispatcher, rc, subscription
+}
+
+// newIdleDispatcher returns the dispatcher of newTestDispatcher without running it
+func newIdleDispatcher(t *testing.T, eventTypes []string, statuses ...int) (*Dispatcher, *receiver, model.WebhookSubscription) {
+	h := helper.NewHelper(config.Default(), nil)
+	rc := &receiver{t: t, secret: "shared-secret", statuses: statuses}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	subscription := model.WebhookSubscription{
+		SubscriptionID: h.GenerateIncrementalWebhookSubscriptionID(context.Background()),
+		URL:            server.URL,
+		EventTypes:     eventTypes,
+		Secret:         rc.secret,
+		CreatedAt:      time.Now(),
+	}
+	h.UpsertWebhookSubscription(context.Background(), subscription)
+	t.Cleanup(func() { h.DeleteWebhookSubscription(context.Background(), subscription.SubscriptionID) })
+
+	dispatcher := NewDispatcher(h)
+	// the local receiver listens on loopback
+	dispatcher.Client = (&Guard{AllowPrivate: true}).Client(DefaultTimeout)
+	dispatcher.MaxAttempts = 3
+	dispatcher.Backoff = func(retry int) time.Duration { return time.Millisecond }
+
+	return dispatcher, rc, subscription
+}
+
+func deadLettersOf(h helper.IHelper, subscriptionID int64) []model.WebhookDelivery {
+	var deadLetters []model.WebhookDelivery
+	for _, v := range h.GetWebhookDeadLetters(context.Background()) {
+		if v.SubscriptionID == subscriptionID {
+			deadLetters = append(deadLetters, v)
+		}
+	}
+
+	return deadLetters
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"id":"evt_0a1b"}`)
+	signature := Sign("shared-secret", 1760000000, payload)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("shared-secret", 1760000000, payload, signature))
+	assert.False(t, Verify("other-secret", 1760000000, payload, signature))
+	assert.False(t, Verify("shared-secret", 1760000001, payload, signature))
+	assert.False(t, Verify("shared-secret", 1760000000, []byte(`{"id":"evt_0a1c"}`), signature))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, 4*time.Second, backoff(3))
+	assert.Equal(t, 5*time.Second, backoff(4))
+	assert.Equal(t, 5*time.Second, backoff(10))
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	dispatcher, rc, _ := newTestDispatcher(t, []string{string(event.LoanInvested)}, http.StatusOK)
+
+	dispatcher.Handle(context.Background(), event.New(event.LoanSubmitted, 1, model.Loan{LoanID: 1}))
+	invested := event.New(event.LoanInvested, 2, model.Loan{LoanID: 1, ApprovalInfo: &model.ApprovalInfo{PictureProof: "aW1hZ2U="}})
+	dispatcher.Handle(context.Background(), invested)
+
+	assert.Eventually(t, func() bool { return rc.count() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, rc.count(), "only the subscribed event type is delivered")
+
+	r := rc.received[0]
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	assert.Equal(t, invested.ID, r.Header.Get(HeaderEventID))
+	assert.Equal(t, string(event.LoanInvested), r.Header.Get(HeaderEventType))
+	assert.NotContains(t, string(rc.bodies[0]), "aW1hZ2U=")
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	dispatcher, rc, subscription := newTestDispatcher(t, nil, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusNoContent)
+
+	dispatcher.Handle(context.Background(), event.New(event.LoanDisbursed, 5, model.Loan{LoanID: 1}))
+
+	assert.Eventually(t, func() bool { return rc.count() == 3 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, rc.count())
+	assert.Empty(t, deadLettersOf(dispatcher.Helper, subscription.SubscriptionID))
+}
+
+func TestDispatcherDeadLetter(t *testing.T) {
+	dispatcher, rc, subscription := newTestDispatcher(t, nil, http.StatusInternalServerError)
+
+	e := event.New(event.LoanDisbursed, 5, model.Loan{LoanID: 1})
+	dispatcher.Handle(context.Background(), e)
+
+	assert.Eventually(t, func() bool { return len(deadLettersOf(dispatcher.Helper, subscription.SubscriptionID)) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, rc.count())
+	deadLetter := deadLettersOf(dispatcher.Helper, subscription.SubscriptionID)[0]
+	assert.Equal(t, e.ID, deadLetter.EventID)
+	assert.Equal(t, string(event.LoanDisbursed), deadLetter.EventType)
+	assert.Equal(t, 3, deadLetter.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, deadLetter.LastStatusCode)
+	assert.NotEmpty(t, deadLetter.LastError)
+
+	// redelivered once the receiver recovers
+	rc.respond(http.StatusOK)
+	assert.NoError(t, dispatcher.Redeliver(context.Background(), deadLetter))
+	assert.Eventually(t, func() bool { return rc.count() == 4 }, time.Second, time.Millisecond)
+	assert.Equal(t, rc.bodies[0], rc.bodies[3])
+
+	// not redelivered once unsubscribed
+	dispatcher.Helper.DeleteWebhookSubscription(context.Background(), subscription.SubscriptionID)
+	assert.Error(t, dispatcher.Redeliver(context.Background(), deadLetter))
+}
+
+func TestDispatcherRefusesPrivateAddress(t *testing.T) {
+	dispatcher, rc, subscription := newTestDispatcher(t, nil, http.StatusOK)
+	dispatcher.Client = NewGuard().Client(DefaultTimeout)
+
+	dispatcher.Handle(context.Background(), event.New(event.LoanDisbursed, 5, model.Loan{LoanID: 1}))
+
+	assert.Eventually(t, func() bool { return len(deadLettersOf(dispatcher.Helper, subscription.SubscriptionID)) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, rc.count())
+	assert.Contains(t, deadLettersOf(dispatcher.Helper, subscription.SubscriptionID)[0].LastError, ErrPrivateTarget.Error())
+}
+
+func TestDispatcherDrain(t *testing.T) {
+	dispatcher, rc, subscription := newIdleDispatcher(t, nil, http.StatusOK)
+	for i := 1; i <= 3; i++ {
+		dispatcher.Handle(context.Background(), event.New(event.LoanDisbursed, 5, model.Loan{LoanID: int64(i)}))
+	}
+
+	// nothing is sent without the workers
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, dispatcher.Drain(ctx), context.DeadlineExceeded)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		dispatcher.Run(workerCtx, 2)
+		close(stopped)
+	}()
+
+	// main func
+	err := dispatcher.Drain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rc.count())
+	stopWorkers()
+	<-stopped
+	assert.Empty(t, deadLettersOf(dispatcher.Helper, subscription.SubscriptionID))
+}
+
+func TestDispatcherStopDeadLettersQueued(t *testing.T) {
+	dispatcher, rc, subscription := newIdleDispatcher(t, nil, http.StatusOK)
+	eventIDs := make(map[string]bool)
+	for i := 1; i <= 3; i++ {
+		e := event.New(event.LoanDisbursed, 5, model.Loan{LoanID: int64(i)})
+		eventIDs[e.ID] = true
+		dispatcher.Handle(context.Background(), e)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// main func
+	dispatcher.Run(ctx, 2)
+
+	assert.Equal(t, 0, rc.count())
+	deadLetters := deadLettersOf(dispatcher.Helper, subscription.SubscriptionID)
+	assert.Len(t, deadLetters, 3)
+	for _, deadLetter := range deadLetters {
+		assert.True(t, eventIDs[deadLetter.EventID], "event %s is not queued", deadLetter.EventID)
+	}
+	assert.NoError(t, dispatcher.Drain(context.Background()))
+}
+
+func TestDispatcherPropagatesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	dispatcher, rc, _ := newTestDispatcher(t, nil, http.StatusOK)
+	ctx, span := otel.Tracer("test").Start(context.Background(), "POST /v1/loan/{loan_id}/invest")
+
+	// main func
+	dispatcher.Handle(ctx, event.New(event.LoanInvested, 2, model.Loan{LoanID: 1}))
+	span.End()
+
+	assert.Eventually(t, func() bool { return rc.count() == 1 }, time.Second, time.Millisecond)
+	traceParent := rc.received[0].Header.Get("traceparent")
+	assert.Contains(t, traceParent, span.SpanContext().TraceID().String())
+	var deliverSpan sdktrace.ReadOnlySpan
+	assert.Eventually(t, func() bool {
+		for _, v := range recorder.Ended() {
+			if v.Name() == "webhook.Deliver" {
+				deliverSpan = v
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, span.SpanContext().SpanID(), deliverSpan.Parent().SpanID())
+	assert.Contains(t, traceParent, deliverSpan.SpanContext().SpanID().String())
+}
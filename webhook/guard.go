@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// dialTimeout bounds the connection to a webhook receiver, the whole delivery is bounded by the client timeout
+const dialTimeout = 5 * time.Second
+
+var (
+	// ErrPrivateTarget is the error of a webhook url reaching a loopback, private or otherwise special address,
+	// the deliveries must not reach the network of the server or its cloud metadata endpoint
+	ErrPrivateTarget = errors.New("webhook target is not a public address")
+	// ErrUnresolvedTarget is the error of a webhook url whose host does not resolve
+	ErrUnresolvedTarget = errors.New("webhook target does not resolve")
+)
+
+// specialPrefixes are the ranges not reachable on the internet which netip.Addr does not tell apart
+var specialPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // this network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade nat
+	netip.MustParsePrefix("192.0.0.0/24"),   // ietf protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, broadcast included
+	netip.MustParsePrefix("64:ff9b::/96"),   // nat64, embeds an ipv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use nat64
+	netip.MustParsePrefix("2002::/16"),      // 6to4, embeds an ipv4 address
+}
+
+// Resolver resolves the host of a webhook url, *net.Resolver implements it
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error)
+}
+
+// Guard keeps the webhooks off the loopback, private and special addresses, checking the url on subscription
+// and the resolved address on every connection
+type Guard struct {
+	Resolver Resolver
+	// AllowPrivate lets the webhooks reach every address, for local runs only
+	AllowPrivate bool
+}
+
+func NewGuard() *Guard {
+	return &Guard{
+		Resolver: net.DefaultResolver,
+	}
+}
+
+// IsPublicAddr tells whether the address may be the target of a webhook
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	// loopback, link-local (169.254.169.254 included), multicast and unspecified are not global unicast
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range specialPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckTarget checks every address the host of the webhook url resolves to is public
+func (g *Guard) CheckTarget(ctx context.Context, target *url.URL) error {
+	if g.AllowPrivate {
+		return nil
+	}
+
+	// 1. resolve host, an address host is not resolved
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+	}
+	addrs, err := g.resolve(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnresolvedTarget, err)
+	}
+
+	// 2. check every address, any of them may be dialed
+	for _, addr := range addrs {
+		if IsPublicAddr(addr) {
+			continue
+		}
+		if addr.String() == host {
+			return fmt.Errorf("%w: %s", ErrPrivateTarget, addr)
+		}
+		return fmt.Errorf("%w: %s resolves to %s", ErrPrivateTarget, host, addr)
+	}
+
+	return nil
+}
+
+func (g *Guard) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	addr, err := netip.ParseAddr(host)
+	if err == nil {
+		return []netip.Addr{addr}, nil
+	}
+
+	addrs, err := g.Resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no address for %s", host)
+	}
+
+	return addrs, nil
+}
+
+// Client returns the http client delivering the webhooks, refusing the connections to a non-public address once
+// it is resolved, so a host resolving to another address after its subscription or a redirect is refused too.
+// The proxy of the environment is not used, it would dial on behalf of the client
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: g.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// control checks the resolved address of the connection before it is dialed
+func (g *Guard) control(network string, address string, _ syscall.RawConn) error {
+	if g.AllowPrivate {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPrivateTarget, err)
+	}
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, addrPort.Addr())
+	}
+
+	return nil
+}
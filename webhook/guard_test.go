@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// staticResolver resolves the hosts of the map, any other host is not found
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(ctx context.Context, network string, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return addrs, nil
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr             string
+		expectedIsPublic bool
+	}{
+		{addr: "93.184.215.14", expectedIsPublic: true},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", expectedIsPublic: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "fd00::1"},
+		{addr: "0.0.0.0"},
+		{addr: "100.64.0.1"},
+		{addr: "224.0.0.1"},
+		{addr: "255.255.255.255"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "64:ff9b::a9fe:a9fe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			// main func
+			isPublic := IsPublicAddr(netip.MustParseAddr(tt.addr))
+
+			assert.Equal(t, tt.expectedIsPublic, isPublic)
+		})
+	}
+}
+
+func TestGuardCheckTarget(t *testing.T) {
+	guard := &Guard{Resolver: staticResolver{
+		"receiver.example.com": {netip.MustParseAddr("93.184.215.14")},
+		"rebind.example.com":   {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.1")},
+	}}
+
+	tests := []struct {
+		name        string
+		guard       *Guard
+		url         string
+		expectedErr error
+	}{
+		{
+			name:  "success - public host",
+			guard: guard,
+			url:   "https://receiver.example.com/events",
+		},
+		{
+			name:  "success - public address",
+			guard: guard,
+			url:   "http://93.184.215.14:8080/events",
+		},
+		{
+			name:        "error - host resolving to a private address",
+			guard:       guard,
+			url:         "https://rebind.example.com/events",
+			expectedErr: ErrPrivateTarget,
+		},
+		{
+			name:        "error - cloud metadata address",
+			guard:       guard,
+			url:         "http://169.254.169.254/latest/meta-data/",
+			expectedErr: ErrPrivateTarget,
+		},
+		{
+			name:        "error - loopback address",
+			guard:       guard,
+			url:         "http://[::1]:9000/events",
+			expectedErr: ErrPrivateTarget,
+		},
+		{
+			name:        "error - localhost",
+			guard:       guard,
+			url:         "http://localhost:9000/events",
+			expectedErr: ErrPrivateTarget,
+		},
+		{
+			name:        "error - unknown host",
+			guard:       guard,
+			url:         "https://unknown.example.com/events",
+			expectedErr: ErrUnresolvedTarget,
+		},
+		{
+			name:  "success - private address allowed for local runs",
+			guard: &Guard{AllowPrivate: true},
+			url:   "http://localhost:9000/events",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := url.Parse(tt.url)
+			assert.NoError(t, err)
+
+			// main func
+			err = tt.guard.CheckTarget(context.Background(), target)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}